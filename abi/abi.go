@@ -231,6 +231,15 @@ func (m *Method) Decode(data []byte) (map[string]interface{}, error) {
 	return resp, nil
 }
 
+// MustNewMethod creates a new solidity method object or fails
+func MustNewMethod(name string) *Method {
+	method, err := NewMethod(name)
+	if err != nil {
+		panic(err)
+	}
+	return method
+}
+
 func NewMethod(name string) (*Method, error) {
 	name, inputs, outputs, err := parseMethodSignature(name)
 	if err != nil {
@@ -334,6 +343,52 @@ func NewError(name string) (*Error, error) {
 	return &Error{Name: name, Inputs: typ}, nil
 }
 
+// MustNewError creates a new solidity error object or fails
+func MustNewError(name string) *Error {
+	e, err := NewError(name)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Sig returns the signature of the error
+func (e *Error) Sig() string {
+	return buildSignature(e.Name, e.Inputs)
+}
+
+// ID returns the selector of the error in the revert data
+func (e *Error) ID() []byte {
+	k := acquireKeccak()
+	k.Write([]byte(e.Sig()))
+	dst := k.Sum(nil)[:4]
+	releaseKeccak(k)
+	return dst
+}
+
+// Encode encodes the revert data of the error with the arguments
+func (e *Error) Encode(args interface{}) ([]byte, error) {
+	data, err := Encode(args, e.Inputs)
+	if err != nil {
+		return nil, err
+	}
+	data = append(e.ID(), data...)
+	return data, nil
+}
+
+// Decode decodes the arguments of the error from the revert data
+func (e *Error) Decode(data []byte) (map[string]interface{}, error) {
+	if !bytes.HasPrefix(data, e.ID()) {
+		return nil, fmt.Errorf("revert data does not match this error")
+	}
+	respInterface, err := Decode(e.Inputs, data[4:])
+	if err != nil {
+		return nil, err
+	}
+	resp := respInterface.(map[string]interface{})
+	return resp, nil
+}
+
 func parseEventOrErrorSignature(prefix string, name string) (string, *Type, error) {
 	if !strings.HasPrefix(name, prefix) {
 		return "", nil, fmt.Errorf("prefix '%s' not found", prefix)
@@ -413,6 +468,15 @@ func releaseKeccak(k hash.Hash) {
 	keccakPool.Put(k)
 }
 
+// MustNewABIFromList returns a parsed ABI from a human readable list or panics if fails
+func MustNewABIFromList(humanReadableAbi []string) *ABI {
+	a, err := NewABIFromList(humanReadableAbi)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
 func NewABIFromList(humanReadableAbi []string) (*ABI, error) {
 	res := &ABI{}
 	for _, c := range humanReadableAbi {
@@ -0,0 +1,204 @@
+package abi
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// EncodePacked encodes a value with the non-standard packed mode of
+// solidity (abi.encodePacked). The elements of a tuple are concatenated,
+// the static types use the minimum number of bytes, the dynamic types are
+// encoded in place without the length and the elements of the arrays are
+// padded to 32 bytes. Nested tuples are not supported.
+func EncodePacked(v interface{}, t *Type) ([]byte, error) {
+	val := reflect.ValueOf(v)
+	if t.kind != KindTuple {
+		return encodePacked(val, t)
+	}
+	return encodePackedTuple(val, t)
+}
+
+func encodePackedTuple(v reflect.Value, t *Type) ([]byte, error) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	var err error
+	isList := true
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+	case reflect.Map:
+		isList = false
+
+	case reflect.Struct:
+		isList = false
+		v, err = mapFromStruct(v)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, encodeErr(v, "tuple")
+	}
+
+	if v.Len() < len(t.tuple) {
+		return nil, fmt.Errorf("expected at least the same length")
+	}
+
+	var ret []byte
+	var aux reflect.Value
+
+	for i, elem := range t.tuple {
+		if isList {
+			aux = v.Index(i)
+		} else {
+			name := elem.Name
+			if name == "" {
+				name = strconv.Itoa(i)
+			}
+			aux = v.MapIndex(reflect.ValueOf(name))
+		}
+		if aux.Kind() == reflect.Invalid {
+			return nil, fmt.Errorf("cannot get key %s", elem.Name)
+		}
+
+		val, err := encodePacked(aux, elem.Elem)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, val...)
+	}
+	return ret, nil
+}
+
+func encodePacked(v reflect.Value, t *Type) ([]byte, error) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+
+	switch t.kind {
+	case KindSlice, KindArray:
+		return encodePackedSliceAndArray(v, t)
+
+	case KindTuple:
+		return nil, fmt.Errorf("nested tuples are not supported in packed mode")
+
+	case KindString:
+		if v.Kind() != reflect.String {
+			return nil, encodeErr(v, "string")
+		}
+		return []byte(v.String()), nil
+
+	case KindBytes:
+		if v.Kind() == reflect.Array {
+			v = convertArrayToBytes(v)
+		}
+		if v.Kind() == reflect.String {
+			return decodeHex(v.String())
+		}
+		if v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.Uint8 {
+			return nil, encodeErr(v, "bytes")
+		}
+		return append([]byte{}, v.Bytes()...), nil
+
+	case KindBool:
+		val, err := encodeBool(v)
+		if err != nil {
+			return nil, err
+		}
+		return val[31:], nil
+
+	case KindAddress:
+		val, err := encodeAddress(v)
+		if err != nil {
+			return nil, err
+		}
+		return val[12:], nil
+
+	case KindInt, KindUInt:
+		return encodePackedNum(v, t)
+
+	case KindFixedBytes, KindFunction:
+		val, err := encodeFixedBytes(v)
+		if err != nil {
+			return nil, err
+		}
+		if !isZero(val[t.size:]) {
+			return nil, fmt.Errorf("value does not fit in %s", t.String())
+		}
+		return val[:t.size], nil
+
+	default:
+		return nil, fmt.Errorf("encoding not available for type '%s'", t.kind)
+	}
+}
+
+func encodePackedSliceAndArray(v reflect.Value, t *Type) ([]byte, error) {
+	if v.Kind() != reflect.Array && v.Kind() != reflect.Slice {
+		return nil, encodeErr(v, t.kind.String())
+	}
+	if t.kind == KindArray && t.size != v.Len() {
+		return nil, fmt.Errorf("array len incompatible")
+	}
+
+	switch t.elem.kind {
+	case KindSlice, KindArray, KindTuple, KindString, KindBytes:
+		return nil, fmt.Errorf("arrays of %s are not supported in packed mode", t.elem.kind)
+	}
+
+	// the elements of the arrays are padded as in the standard encoding
+	var ret []byte
+	for i := 0; i < v.Len(); i++ {
+		val, err := encode(v.Index(i), t.elem)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, val...)
+	}
+	return ret, nil
+}
+
+func encodePackedNum(v reflect.Value, t *Type) ([]byte, error) {
+	val, err := encodeNum(v)
+	if err != nil {
+		return nil, err
+	}
+
+	size := t.size / 8
+	head, tail := val[:32-size], val[32-size:]
+
+	// the value must fit in the size of the type. The signed
+	// numbers are sign extended in the 256 bits encoding.
+	fits := isZero(head)
+	if t.kind == KindInt {
+		negative := tail[0]&0x80 != 0
+		fits = (!negative && isZero(head)) || (negative && isOnes(head))
+	}
+	if !fits {
+		return nil, fmt.Errorf("value does not fit in %s", t.String())
+	}
+	return tail, nil
+}
+
+func isZero(b []byte) bool {
+	for _, i := range b {
+		if i != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func isOnes(b []byte) bool {
+	for _, i := range b {
+		if i != 0xff {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+package abi
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodePacked(t *testing.T) {
+	cases := []struct {
+		Type   string
+		Input  interface{}
+		Output string
+	}{
+		{
+			// example of the solidity documentation
+			"tuple(int16, bytes1, uint16, string)",
+			[]interface{}{int16(-1), [1]byte{0x42}, uint16(3), "Hello, world!"},
+			"ffff42000348656c6c6f2c20776f726c6421",
+		},
+		{
+			"tuple(address, uint256)",
+			[]interface{}{ethgo.HexToAddress("0x8ba1f109551bD432803012645Ac136ddd64DBA72"), big.NewInt(45)},
+			"8ba1f109551bd432803012645ac136ddd64dba72000000000000000000000000000000000000000000000000000000000000002d",
+		},
+		{
+			"tuple(int8, uint8, int32, uint64)",
+			[]interface{}{int8(-1), uint8(255), int32(-2), uint64(1)},
+			"ffff" + "fffffffe" + "0000000000000001",
+		},
+		{
+			"tuple(int24, uint40)",
+			[]interface{}{big.NewInt(-8388608), big.NewInt(1)},
+			"800000" + "0000000001",
+		},
+		{
+			"tuple(bool, bool, bytes, bytes4)",
+			[]interface{}{true, false, []byte{0x1, 0x2, 0x3}, "0xaabbcc"},
+			"0100" + "010203" + "aabbcc00",
+		},
+		{
+			// the elements of the arrays are padded to 32 bytes
+			"tuple(uint8[], address[2], bool[])",
+			[]interface{}{
+				[]uint8{1, 2},
+				[2]ethgo.Address{{0x1}, {0x2}},
+				[]bool{true},
+			},
+			"0000000000000000000000000000000000000000000000000000000000000001" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000100000000000000000000000000000000000000" +
+				"0000000000000000000000000200000000000000000000000000000000000000" +
+				"0000000000000000000000000000000000000000000000000000000000000001",
+		},
+		{
+			"tuple(int256[])",
+			[]interface{}{[]*big.Int{big.NewInt(-1)}},
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		},
+		{
+			"tuple(string a, uint8 b)",
+			map[string]interface{}{"a": "a", "b": uint8(1)},
+			"6101",
+		},
+		{
+			"uint32",
+			uint32(1),
+			"00000001",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Type, func(t *testing.T) {
+			typ := MustNewType(c.Type)
+
+			res, err := typ.EncodePacked(c.Input)
+			assert.NoError(t, err)
+			assert.Equal(t, c.Output, hex.EncodeToString(res))
+		})
+	}
+}
+
+func TestEncodePacked_Create2(t *testing.T) {
+	// address of the USDC/WETH pair of uniswap v2 in mainnet
+	factory := ethgo.HexToAddress("0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f")
+	initCodeHash := ethgo.HexToHash("0x96e8ac4277198ff8b6f785478aa9a39f403cb768dd02cbee326c3e7da348845f")
+
+	token0 := ethgo.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	token1 := ethgo.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+
+	salt, err := EncodePacked([]interface{}{token0, token1}, MustNewType("tuple(address, address)"))
+	assert.NoError(t, err)
+
+	data, err := EncodePacked([]interface{}{
+		[1]byte{0xff},
+		factory,
+		ethgo.BytesToHash(ethgo.Keccak256(salt)),
+		initCodeHash,
+	}, MustNewType("tuple(bytes1, address, bytes32, bytes32)"))
+	assert.NoError(t, err)
+
+	pair := ethgo.BytesToAddress(ethgo.Keccak256(data)[12:])
+	assert.Equal(t, ethgo.HexToAddress("0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc"), pair)
+}
+
+func TestEncodePacked_Errors(t *testing.T) {
+	cases := []struct {
+		Type  string
+		Input interface{}
+	}{
+		{"tuple(uint8)", []interface{}{big.NewInt(256)}},
+		{"tuple(uint16)", []interface{}{big.NewInt(-1)}},
+		{"tuple(int8)", []interface{}{big.NewInt(128)}},
+		{"tuple(int8)", []interface{}{big.NewInt(-129)}},
+		{"tuple(bytes2)", []interface{}{"0xaabbcc"}},
+		{"tuple(tuple(uint8))", []interface{}{[]interface{}{uint8(1)}}},
+		{"tuple(string[])", []interface{}{[]string{"a"}}},
+		{"tuple(uint8[][])", []interface{}{[][]uint8{{1}}}},
+		{"tuple(uint8[2])", []interface{}{[1]uint8{1}}},
+	}
+
+	for _, c := range cases {
+		_, err := EncodePacked(c.Input, MustNewType(c.Type))
+		assert.Error(t, err, c.Type)
+	}
+}
@@ -3,9 +3,16 @@ package abi
 import (
 	"bytes"
 	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
 )
 
-var revertId = []byte{0x8, 0xC3, 0x79, 0xA0}
+var (
+	revertId = []byte{0x8, 0xC3, 0x79, 0xA0}
+	panicId  = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
 
 func UnpackRevertError(b []byte) (string, error) {
 	if !bytes.HasPrefix(b, revertId) {
@@ -21,3 +28,136 @@ func UnpackRevertError(b []byte) (string, error) {
 	revVal := vals.(map[string]interface{})["0"].(string)
 	return revVal, nil
 }
+
+// UnpackPanic decodes the code of a Panic(uint256) revert
+func UnpackPanic(b []byte) (*big.Int, error) {
+	if !bytes.HasPrefix(b, panicId) {
+		return nil, fmt.Errorf("panic prefix not found")
+	}
+
+	b = b[4:]
+	tt := MustNewType("tuple(uint256)")
+	vals, err := tt.Decode(b)
+	if err != nil {
+		return nil, err
+	}
+	code := vals.(map[string]interface{})["0"].(*big.Int)
+	return code, nil
+}
+
+// panicReasons are the meanings of the panic codes of the solidity compiler
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assert failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to an uninitialized internal function",
+}
+
+// PanicReason returns the meaning of a Panic(uint256) code
+func PanicReason(code *big.Int) string {
+	if code.IsUint64() {
+		if reason, ok := panicReasons[code.Uint64()]; ok {
+			return reason
+		}
+	}
+	return "unknown panic code"
+}
+
+// RevertError is the error of a reverted call. Depending on the revert data,
+// it is either an Error(string) with a reason, a Panic(uint256) with a code
+// or a custom error of the abi with its arguments.
+type RevertError struct {
+	// Data is the raw revert data
+	Data []byte
+
+	// Reason is the message of an Error(string) revert
+	Reason string
+
+	// PanicCode is the code of a Panic(uint256) revert
+	PanicCode *big.Int
+
+	// CustomError is the custom error that matches the revert data
+	// and Args are its decoded arguments
+	CustomError *Error
+	Args        map[string]interface{}
+}
+
+// IsPanic returns true if the revert is a Panic(uint256)
+func (r *RevertError) IsPanic() bool {
+	return r.PanicCode != nil
+}
+
+// Error implements the error interface
+func (r *RevertError) Error() string {
+	switch {
+	case r.PanicCode != nil:
+		return fmt.Sprintf("execution reverted: panic: %s (0x%x)", PanicReason(r.PanicCode), r.PanicCode)
+
+	case r.CustomError != nil:
+		args := make([]string, 0, len(r.Args))
+		for indx, elem := range r.CustomError.Inputs.TupleElems() {
+			name := elem.Name
+			if name == "" {
+				name = strconv.Itoa(indx)
+			}
+			args = append(args, fmt.Sprintf("%s: %v", name, r.Args[name]))
+		}
+		return fmt.Sprintf("execution reverted: %s(%s)", r.CustomError.Name, strings.Join(args, ", "))
+
+	case r.Reason != "":
+		return "execution reverted: " + r.Reason
+	}
+	return "execution reverted"
+}
+
+// UnpackRevert decodes the revert data of a call. Besides Error(string) and
+// Panic(uint256), the data is matched against the custom errors. It does not
+// fail if the data cannot be decoded, the RevertError only includes the raw data.
+func UnpackRevert(data []byte, errs map[string]*Error) *RevertError {
+	res := &RevertError{Data: data}
+	if len(data) < 4 {
+		return res
+	}
+
+	if reason, err := UnpackRevertError(data); err == nil {
+		res.Reason = reason
+		return res
+	}
+	if code, err := UnpackPanic(data); err == nil {
+		res.PanicCode = code
+		return res
+	}
+
+	// sort the errors to make the match deterministic
+	names := make([]string, 0, len(errs))
+	for name := range errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		e := errs[name]
+		if !bytes.Equal(e.ID(), data[:4]) {
+			continue
+		}
+		args, err := e.Decode(data)
+		if err != nil {
+			continue
+		}
+		res.CustomError = e
+		res.Args = args
+		break
+	}
+	return res
+}
+
+// UnpackRevert decodes the revert data of a call with the errors of the abi
+func (a *ABI) UnpackRevert(data []byte) *RevertError {
+	return UnpackRevert(data, a.Errors)
+}
@@ -1,8 +1,12 @@
 package abi
 
 import (
+	"encoding/hex"
+	"math/big"
 	"testing"
 
+	"github.com/git-yongge/ethgo"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -16,3 +20,69 @@ func TestUnpackRevertError(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "revert reason", reason)
 }
+
+func TestUnpackRevert(t *testing.T) {
+	insufficientBalance := MustNewError("error InsufficientBalance(uint256 available, uint256 required)")
+	unauthorized := MustNewError("error Unauthorized(address)")
+
+	errs := map[string]*Error{
+		"InsufficientBalance": insufficientBalance,
+		"Unauthorized":        unauthorized,
+	}
+
+	encode := func(str string) []byte {
+		buf, err := decodeHex(str)
+		assert.NoError(t, err)
+		return buf
+	}
+
+	t.Run("reason", func(t *testing.T) {
+		data := encode("08c379a00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000d72657665727420726561736f6e00000000000000000000000000000000000000")
+
+		rev := UnpackRevert(data, errs)
+		assert.Equal(t, "revert reason", rev.Reason)
+		assert.False(t, rev.IsPanic())
+		assert.Equal(t, "execution reverted: revert reason", rev.Error())
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		// Panic(0x11)
+		data := encode("4e487b710000000000000000000000000000000000000000000000000000000000000011")
+
+		rev := UnpackRevert(data, errs)
+		assert.True(t, rev.IsPanic())
+		assert.Equal(t, big.NewInt(0x11), rev.PanicCode)
+		assert.Equal(t, "execution reverted: panic: arithmetic underflow or overflow (0x11)", rev.Error())
+
+		assert.Equal(t, "unknown panic code", PanicReason(big.NewInt(0x99)))
+	})
+
+	t.Run("custom error", func(t *testing.T) {
+		assert.Equal(t, "cf479181", hex.EncodeToString(insufficientBalance.ID()))
+
+		data, err := insufficientBalance.Encode([]interface{}{big.NewInt(1), big.NewInt(2)})
+		assert.NoError(t, err)
+
+		rev := UnpackRevert(data, errs)
+		assert.Equal(t, insufficientBalance, rev.CustomError)
+		assert.Equal(t, big.NewInt(1), rev.Args["available"])
+		assert.Equal(t, big.NewInt(2), rev.Args["required"])
+		assert.Equal(t, "execution reverted: InsufficientBalance(available: 1, required: 2)", rev.Error())
+
+		data, err = unauthorized.Encode([]interface{}{ethgo.Address{0x1}})
+		assert.NoError(t, err)
+
+		rev = UnpackRevert(data, errs)
+		assert.Equal(t, unauthorized, rev.CustomError)
+		assert.Equal(t, "execution reverted: Unauthorized(0: 0x0100000000000000000000000000000000000000)", rev.Error())
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		data := encode("deadbeef")
+
+		rev := UnpackRevert(data, errs)
+		assert.Nil(t, rev.CustomError)
+		assert.Equal(t, data, rev.Data)
+		assert.Equal(t, "execution reverted", rev.Error())
+	})
+}
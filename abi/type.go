@@ -150,6 +150,11 @@ func (t *Type) Encode(v interface{}) ([]byte, error) {
 	return Encode(v, t)
 }
 
+// EncodePacked encodes an object using this type in packed mode
+func (t *Type) EncodePacked(v interface{}) ([]byte, error) {
+	return EncodePacked(v, t)
+}
+
 func (t *Type) String() string {
 	return t.Format(false)
 }
@@ -38,11 +38,15 @@ type BlockTracker struct {
 type Config struct {
 	Tracker         BlockTrackerInterface
 	MaxBlockBacklog uint64
+
+	// Tag is the block followed as the head of the chain
+	Tag ethgo.BlockNumber
 }
 
 func DefaultConfig() *Config {
 	return &Config{
 		MaxBlockBacklog: defaultMaxBlockBacklog,
+		Tag:             ethgo.Latest,
 	}
 }
 
@@ -60,14 +64,29 @@ func WithTracker(b BlockTrackerInterface) ConfigOption {
 	}
 }
 
-func NewBlockTracker(provider BlockProvider, opts ...ConfigOption) *BlockTracker {
+// WithBlockTag sets the block followed as the head of the chain. With the
+// ethgo.Safe and ethgo.Finalized tags the head advances one epoch at a time and,
+// with ethgo.Finalized, a reorg of the tracked blocks is an error. Only the polling
+// tracker follows the tag, with ethgo.Safe and ethgo.Finalized a custom tracker set
+// with WithTracker must be a JSONBlockTracker that polls the same tag.
+func WithBlockTag(tag ethgo.BlockNumber) ConfigOption {
+	return func(c *Config) {
+		c.Tag = tag
+	}
+}
+
+func NewBlockTracker(provider BlockProvider, opts ...ConfigOption) (*BlockTracker, error) {
 	config := DefaultConfig()
 	for _, opt := range opts {
 		opt(config)
 	}
 	tracker := config.Tracker
 	if tracker == nil {
-		tracker = NewJSONBlockTracker(log.New(os.Stderr, "", log.LstdFlags), provider)
+		jsonTracker := NewJSONBlockTracker(log.New(os.Stderr, "", log.LstdFlags), provider)
+		jsonTracker.Tag = config.Tag
+		tracker = jsonTracker
+	} else if !tracksTag(tracker, config.Tag) {
+		return nil, fmt.Errorf("custom block tracker does not follow the %s block", config.Tag)
 	}
 	return &BlockTracker{
 		blocks:     []*ethgo.Block{},
@@ -76,7 +95,16 @@ func NewBlockTracker(provider BlockProvider, opts ...ConfigOption) *BlockTracker
 		subscriber: tracker,
 		provider:   provider,
 		closeCh:    make(chan struct{}),
+	}, nil
+}
+
+// tracksTag returns true if the tracker notifies the blocks of the tag
+func tracksTag(tracker BlockTrackerInterface, tag ethgo.BlockNumber) bool {
+	if tag != ethgo.Safe && tag != ethgo.Finalized {
+		return true
 	}
+	jsonTracker, ok := tracker.(*JSONBlockTracker)
+	return ok && jsonTracker.Tag == tag
 }
 
 func (b *BlockTracker) Subscribe() chan *BlockEvent {
@@ -95,7 +123,7 @@ func (b *BlockTracker) AcquireLock() Lock {
 func (t *BlockTracker) Init() (err error) {
 	var block *ethgo.Block
 	t.once.Do(func() {
-		block, err = t.provider.GetBlockByNumber(ethgo.Latest, false)
+		block, err = t.provider.GetBlockByNumber(t.config.Tag, false)
 		if err != nil {
 			return
 		}
@@ -130,6 +158,16 @@ func (b *BlockTracker) MaxBlockBacklog() uint64 {
 	return b.config.MaxBlockBacklog
 }
 
+// Tag returns the block followed as the head of the chain
+func (b *BlockTracker) Tag() ethgo.BlockNumber {
+	return b.config.Tag
+}
+
+// followsFinality returns true if the head is the safe or the finalized block
+func (b *BlockTracker) followsFinality() bool {
+	return b.config.Tag == ethgo.Safe || b.config.Tag == ethgo.Finalized
+}
+
 func (b *BlockTracker) LastBlocked() *ethgo.Block {
 	target := b.blocks[len(b.blocks)-1]
 	if target == nil {
@@ -222,9 +260,16 @@ func (t *BlockTracker) handleReconcileImpl(block *ethgo.Block) ([]*ethgo.Block,
 	added := []*ethgo.Block{block}
 	var indx int
 
+	limit := t.config.MaxBlockBacklog
+	if head := t.blocks[len(t.blocks)-1]; t.followsFinality() && block.Number > head.Number {
+		// the safe and finalized blocks advance one epoch at a time,
+		// backfill all the blocks since the head
+		limit += block.Number - head.Number
+	}
+
 	count := uint64(0)
 	for {
-		if count > t.config.MaxBlockBacklog {
+		if count > limit {
 			return nil, -1, fmt.Errorf("cannot reconcile more than max backlog values")
 		}
 		count++
@@ -265,6 +310,9 @@ func (t *BlockTracker) HandleBlockEvent(block *ethgo.Block) (*BlockEvent, error)
 
 	// there are some blocks to remove
 	if indx != -1 {
+		if t.config.Tag == ethgo.Finalized && indx != len(t.blocks)-1 {
+			return nil, fmt.Errorf("reorg of finalized block %d", t.blocks[indx+1].Number)
+		}
 		for i := indx + 1; i < len(t.blocks); i++ {
 			blockEvnt.Removed = append(blockEvnt.Removed, t.blocks[i])
 		}
@@ -316,6 +364,9 @@ type JSONBlockTracker struct {
 	logger       *log.Logger
 	PollInterval time.Duration
 	provider     BlockProvider
+
+	// Tag is the block that is polled
+	Tag ethgo.BlockNumber
 }
 
 // NewJSONBlockTracker creates a new json block tracker
@@ -324,6 +375,7 @@ func NewJSONBlockTracker(logger *log.Logger, provider BlockProvider) *JSONBlockT
 		logger:       logger,
 		provider:     provider,
 		PollInterval: defaultPollInterval,
+		Tag:          ethgo.Latest,
 	}
 }
 
@@ -338,7 +390,7 @@ func (k *JSONBlockTracker) Track(ctx context.Context, handle func(block *ethgo.B
 				return
 
 			case <-time.After(k.PollInterval):
-				block, err := k.provider.GetBlockByNumber(ethgo.Latest, false)
+				block, err := k.provider.GetBlockByNumber(k.Tag, false)
 				if err != nil {
 					k.logger.Printf("[ERR]: Tracker failed to get last block: %v", err)
 					continue
@@ -379,29 +431,40 @@ func NewSubscriptionBlockTracker(logger *log.Logger, client *jsonrpc.Client) (*S
 	return s, nil
 }
 
-// Track implements the BlockTracker interface
+// Track implements the BlockTracker interface. The subscription ends if a header
+// cannot be decoded or the connection drops and the transport does not reconnect
+// (see jsonrpc.WithReconnect), then the error is logged and no more blocks are tracked. If the transport
+// reconnects, the blocks missed in between are backfilled with the parents
+// of the next block.
 func (s *SubscriptionBlockTracker) Track(ctx context.Context, handle func(block *ethgo.Block) error) error {
-	data := make(chan []byte)
-	cancel, err := s.client.Subscribe("newHeads", func(b []byte) {
-		data <- b
-	})
+	sub, err := s.client.SubscribeNewHeads()
 	if err != nil {
 		return err
 	}
 
 	go func() {
+		blockCh := sub.BlockCh
 		for {
 			select {
-			case buf := <-data:
-				var block ethgo.Block
-				if err := block.UnmarshalJSON(buf); err != nil {
-					s.logger.Printf("[ERR]: Tracker failed to parse ethgo.Block: %v", err)
-				} else {
-					handle(&block)
+			case block, ok := <-blockCh:
+				if !ok {
+					// the subscription ended, wait for the error
+					blockCh = nil
+					continue
+				}
+				if err := handle(block); err != nil {
+					s.logger.Printf("[ERROR]: blocktracker: Failed to handle block: %v", err)
 				}
 
+			case err, ok := <-sub.ErrCh:
+				if !ok {
+					return
+				}
+				s.logger.Printf("[ERR]: Tracker subscription ended: %v", err)
+
 			case <-ctx.Done():
-				cancel()
+				sub.Unsubscribe()
+				return
 			}
 		}
 	}()
@@ -2,6 +2,7 @@ package blocktracker
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 	"os"
 	"testing"
@@ -82,7 +83,8 @@ func TestBlockTracker_Lifecycle(t *testing.T) {
 	defer s.Close()
 
 	c, _ := jsonrpc.NewClient(s.HTTPAddr())
-	tr := NewBlockTracker(c.Eth())
+	tr, err := NewBlockTracker(c.Eth())
+	assert.NoError(t, err)
 	assert.NoError(t, tr.Init())
 
 	go tr.Start()
@@ -106,9 +108,12 @@ func TestBlockTracker_PopulateBlocks(t *testing.T) {
 		m := &testutil.MockClient{}
 		m.AddScenario(l)
 
-		tt0 := NewBlockTracker(m)
+		tt0, err := NewBlockTracker(m)
+		if err != nil {
+			t.Fatal(err)
+		}
 
-		err := tt0.Init()
+		err = tt0.Init()
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -124,10 +129,13 @@ func TestBlockTracker_PopulateBlocks(t *testing.T) {
 		m1 := &testutil.MockClient{}
 		m1.AddScenario(l0)
 
-		tt1 := NewBlockTracker(m1)
+		tt1, err := NewBlockTracker(m1)
+		if err != nil {
+			t.Fatal(err)
+		}
 		tt1.provider = m1
 
-		err := tt1.Init()
+		err = tt1.Init()
 		if err != nil {
 			panic(err)
 		}
@@ -332,7 +340,10 @@ func TestBlockTracker_Events(t *testing.T) {
 			// add the full scenario with the logs
 			m.AddScenario(c.Scenario)
 
-			tt := NewBlockTracker(m)
+			tt, err := NewBlockTracker(m)
+			if err != nil {
+				t.Fatal(err)
+			}
 
 			// build past block history
 			for _, b := range c.History.ToBlocks() {
@@ -370,3 +381,107 @@ func TestBlockTracker_Events(t *testing.T) {
 		})
 	}
 }
+
+func TestBlockTracker_Finalized(t *testing.T) {
+	l := testutil.MockList{}
+	l.Create(0, 60, func(b *testutil.MockBlock) {})
+
+	m := &testutil.MockClient{}
+	m.AddScenario(l)
+	m.SetFinalized(20)
+
+	tt, err := NewBlockTracker(m, WithBlockTag(ethgo.Finalized))
+	assert.NoError(t, err)
+	assert.NoError(t, tt.Init())
+	assert.Equal(t, ethgo.Finalized, tt.Tag())
+	assert.True(t, testutil.CompareBlocks(l.ToBlocks()[11:21], tt.blocks))
+
+	// the finalized block advances more than the backlog
+	// and all the blocks in between are backfilled
+	m.SetFinalized(52)
+	block, err := m.GetBlockByNumber(ethgo.Finalized, false)
+	assert.NoError(t, err)
+
+	evnt, err := tt.HandleBlockEvent(block)
+	assert.NoError(t, err)
+	assert.True(t, testutil.CompareBlocks(l.ToBlocks()[21:53], evnt.Added))
+	assert.Empty(t, evnt.Removed)
+	assert.True(t, testutil.CompareBlocks(l.ToBlocks()[43:53], tt.blocks))
+
+	// a finalized block cannot be reorged
+	_, err = tt.HandleBlockEvent(testutil.Mock(51).Extra("f").Parent(50).Block())
+	assert.Error(t, err)
+	assert.True(t, testutil.CompareBlocks(l.ToBlocks()[43:53], tt.blocks))
+}
+
+func TestBlockTracker_TagCustomTracker(t *testing.T) {
+	m := &testutil.MockClient{}
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+
+	// a subscription only follows the latest block
+	sub := &SubscriptionBlockTracker{}
+	tt, err := NewBlockTracker(m, WithTracker(sub))
+	assert.NoError(t, err)
+	assert.Equal(t, sub, tt.subscriber)
+
+	_, err = NewBlockTracker(m, WithTracker(sub), WithBlockTag(ethgo.Finalized))
+	assert.Error(t, err)
+
+	// a polling tracker of the same tag is used
+	poll := NewJSONBlockTracker(logger, m)
+	poll.Tag = ethgo.Safe
+	tt, err = NewBlockTracker(m, WithTracker(poll), WithBlockTag(ethgo.Safe))
+	assert.NoError(t, err)
+	assert.Equal(t, poll, tt.subscriber)
+
+	_, err = NewBlockTracker(m, WithTracker(poll), WithBlockTag(ethgo.Finalized))
+	assert.Error(t, err)
+}
+
+// logWriter sends each line written by a logger on a channel
+type logWriter chan string
+
+func (l logWriter) Write(p []byte) (int, error) {
+	l <- string(p)
+	return len(p), nil
+}
+
+func TestSubscriptionBlockTracker_Ends(t *testing.T) {
+	subscribed := make(chan struct{}, 1)
+
+	s := testutil.NewMockServer()
+	s.Handle("eth_subscribe", func(params []json.RawMessage) (interface{}, error) {
+		subscribed <- struct{}{}
+		return "0x1", nil
+	})
+	s.HandleResult("eth_unsubscribe", true)
+
+	client, err := jsonrpc.NewClient(s.WsAddr())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	logs := make(logWriter, 1)
+	tracker, err := NewSubscriptionBlockTracker(log.New(logs, "", 0), client)
+	assert.NoError(t, err)
+
+	blocks := make(chan *ethgo.Block, 1)
+	err = tracker.Track(context.Background(), func(block *ethgo.Block) error {
+		blocks <- block
+		return nil
+	})
+	assert.NoError(t, err)
+	<-subscribed
+
+	assert.NoError(t, s.Notify("0x1", testutil.Mock(1).Block()))
+	assert.Equal(t, uint64(1), (<-blocks).Number)
+
+	// the connection drops and the client does not reconnect
+	s.Close()
+
+	select {
+	case line := <-logs:
+		assert.Contains(t, line, "Tracker subscription ended")
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout")
+	}
+}
@@ -2,18 +2,28 @@ package contract
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/git-yongge/ethgo"
 	"github.com/git-yongge/ethgo/abi"
+	"github.com/git-yongge/ethgo/gasoracle"
 	"github.com/git-yongge/ethgo/jsonrpc"
+	"github.com/git-yongge/ethgo/jsonrpc/codec"
+	"github.com/git-yongge/ethgo/nonce"
+	"github.com/git-yongge/ethgo/wallet"
 )
 
 // Contract is an Ethereum contract
 type Contract struct {
 	addr     ethgo.Address
 	from     *ethgo.Address
+	key      ethgo.Key
+	signer   wallet.KeySigner
+	nonces   *nonce.Manager
+	oracle   *gasoracle.Oracle
 	abi      *abi.ABI
 	provider *jsonrpc.Client
 }
@@ -23,6 +33,7 @@ func DeployContract(provider *jsonrpc.Client, from ethgo.Address, abi *abi.ABI,
 	return &Txn{
 		from:     from,
 		provider: provider,
+		abi:      abi,
 		method:   abi.Constructor,
 		args:     args,
 		bin:      bin,
@@ -53,13 +64,39 @@ func (c *Contract) SetFrom(addr ethgo.Address) {
 	c.from = &addr
 }
 
+// SetKey sets the key used to sign the transactions locally. The
+// address of the key becomes the origin of the calls.
+func (c *Contract) SetKey(key ethgo.Key) {
+	c.key = key
+	c.SetFrom(key.Address())
+}
+
+// SetSigner sets the signer used to sign the transactions with the key.
+// If it is not set, an EIP-155 signer for the chain of the provider is used.
+func (c *Contract) SetSigner(signer wallet.KeySigner) {
+	c.signer = signer
+}
+
+// SetNonceManager sets the nonce manager used to get the nonce of the
+// transactions signed with the key. It can be shared between contracts.
+func (c *Contract) SetNonceManager(m *nonce.Manager) {
+	c.nonces = m
+}
+
+// SetGasOracle sets the gas oracle used to suggest the fees of the transactions.
+// If it is not set, the transactions signed with the key use an oracle with the
+// default configuration.
+func (c *Contract) SetGasOracle(o *gasoracle.Oracle) {
+	c.oracle = o
+}
+
 // EstimateGas estimates the gas for a contract call
 func (c *Contract) EstimateGas(method string, args ...interface{}) (uint64, error) {
 	return c.Txn(method, args).EstimateGas()
 }
 
 // Call calls a method in the contract
-func (c *Contract) Call(method string, block ethgo.BlockNumber, args ...interface{}) (map[string]interface{}, error) {
+func (c *Contract) Call(method string, block ethgo.BlockNumberOrHash, args ...interface{}) (map[string]interface{}, error) {
 	m := c.abi.GetMethod(method)
 	if m == nil {
 		return nil, fmt.Errorf("method %s not found", method)
@@ -81,7 +118,7 @@ func (c *Contract) Call(method string, block ethgo.BlockNumber, args ...interfac
 
 	rawStr, err := c.provider.Eth().Call(msg, block)
 	if err != nil {
-		return nil, err
+		return nil, unpackRevert(err, c.abi)
 	}
 
 	// Decode output
@@ -106,8 +143,13 @@ func (c *Contract) Txn(method string, args ...interface{}) *Txn {
 
 	return &Txn{
 		from:     *c.from,
+		key:      c.key,
+		signer:   c.signer,
+		nonces:   c.nonces,
+		oracle:   c.oracle,
 		addr:     &c.addr,
 		provider: c.provider,
+		abi:      c.abi,
 		method:   m,
 		args:     args,
 	}
@@ -115,24 +157,36 @@ func (c *Contract) Txn(method string, args ...interface{}) *Txn {
 
 // Txn is a transaction object
 type Txn struct {
-	from     ethgo.Address
-	addr     *ethgo.Address
-	provider *jsonrpc.Client
-	method   *abi.Method
-	args     []interface{}
-	data     []byte
-	bin      []byte
-	gasLimit uint64
-	gasPrice uint64
-	value    *big.Int
-	hash     ethgo.Hash
-	receipt  *ethgo.Receipt
+	from                 ethgo.Address
+	key                  ethgo.Key
+	signer               wallet.KeySigner
+	nonces               *nonce.Manager
+	nonce                *uint64
+	oracle               *gasoracle.Oracle
+	addr                 *ethgo.Address
+	provider             *jsonrpc.Client
+	abi                  *abi.ABI
+	method               *abi.Method
+	args                 []interface{}
+	data                 []byte
+	bin                  []byte
+	gasLimit             uint64
+	gasPrice             uint64
+	maxFeePerGas         *big.Int
+	maxPriorityFeePerGas *big.Int
+	value                *big.Int
+	hash                 ethgo.Hash
+	receipt              *ethgo.Receipt
 }
 
 func (t *Txn) isContractDeployment() bool {
 	return t.bin != nil
 }
 
+func (t *Txn) isDynamicFee() bool {
+	return t.maxFeePerGas != nil || t.maxPriorityFeePerGas != nil
+}
+
 // AddArgs is used to set the arguments of the transaction
 func (t *Txn) AddArgs(args ...interface{}) *Txn {
 	t.args = args
@@ -145,7 +199,8 @@ func (t *Txn) SetValue(v *big.Int) *Txn {
 	return t
 }
 
-// EstimateGas estimates the gas for the call
+// EstimateGas estimates the gas for the call. If the call reverts,
+// the error is an *abi.RevertError.
 func (t *Txn) EstimateGas() (uint64, error) {
 	if err := t.Validate(); err != nil {
 		return 0, err
@@ -153,18 +208,22 @@ func (t *Txn) EstimateGas() (uint64, error) {
 	return t.estimateGas()
 }
 
-func (t *Txn) estimateGas() (uint64, error) {
+func (t *Txn) estimateGas() (gas uint64, err error) {
 	if t.isContractDeployment() {
-		return t.provider.Eth().EstimateGasContract(t.data)
+		gas, err = t.provider.Eth().EstimateGasContract(t.data)
+	} else {
+		msg := &ethgo.CallMsg{
+			From:  t.from,
+			To:    t.addr,
+			Data:  t.data,
+			Value: t.value,
+		}
+		gas, err = t.provider.Eth().EstimateGas(msg)
 	}
-
-	msg := &ethgo.CallMsg{
-		From:  t.from,
-		To:    t.addr,
-		Data:  t.data,
-		Value: t.value,
+	if err != nil {
+		return 0, unpackRevert(err, t.abi)
 	}
-	return t.provider.Eth().EstimateGas(msg)
+	return gas, nil
 }
 
 // DoAndWait is a blocking query that combines
@@ -179,15 +238,26 @@ func (t *Txn) DoAndWait() error {
 	return nil
 }
 
-// Do sends the transaction to the network
+// Do sends the transaction to the network. If a key is set, the transaction
+// is signed locally and sent as a raw transaction, otherwise the node signs it
+// with eth_sendTransaction. Unless the gas price or the fees are set, the transaction
+// uses the fees suggested by the gas oracle on chains that support EIP-1559 and the
+// gas price otherwise.
+// If the transaction reverts, the error is an *abi.RevertError.
 func (t *Txn) Do() error {
 	err := t.Validate()
 	if err != nil {
 		return err
 	}
 
+	// suggest the fee market values if the chain supports them
+	if t.gasPrice == 0 && !t.isDynamicFee() {
+		if err := t.suggestFees(); err != nil {
+			return err
+		}
+	}
 	// estimate gas price
-	if t.gasPrice == 0 {
+	if t.gasPrice == 0 && !t.isDynamicFee() {
 		t.gasPrice, err = t.provider.Eth().GasPrice()
 		if err != nil {
 			return err
@@ -209,16 +279,124 @@ func (t *Txn) Do() error {
 		Gas:      t.gasLimit,
 		Value:    t.value,
 	}
+	if t.isDynamicFee() {
+		txn.Type = ethgo.TransactionDynamicFee
+		txn.GasPrice = 0
+		txn.MaxFeePerGas = t.maxFeePerGas
+		txn.MaxPriorityFeePerGas = t.maxPriorityFeePerGas
+	}
 	if t.addr != nil {
 		txn.To = t.addr
 	}
-	t.hash, err = t.provider.Eth().SendTransaction(txn)
+
+	if t.key != nil {
+		t.hash, err = t.sendRawTransaction(txn)
+	} else {
+		t.hash, err = t.provider.Eth().SendTransaction(txn)
+	}
 	if err != nil {
+		return unpackRevert(err, t.abi)
+	}
+	return nil
+}
+
+// suggestFees sets the fees suggested by the gas oracle. The gas price is used instead
+// if the chain does not support EIP-1559 or, unless the oracle was set explicitly,
+// if the node cannot suggest the fees (i.e. it does not support eth_feeHistory).
+func (t *Txn) suggestFees() error {
+	oracle := t.oracle
+	if oracle == nil {
+		oracle = gasoracle.NewOracle(t.provider.Eth())
+	}
+	fees, err := oracle.Suggest()
+	if err != nil {
+		if errors.Is(err, gasoracle.ErrNotSupported) || t.oracle == nil {
+			// use the legacy gas price
+			return nil
+		}
 		return err
 	}
+	t.maxFeePerGas = fees.MaxFeePerGas
+	t.maxPriorityFeePerGas = fees.MaxPriorityFeePerGas
 	return nil
 }
 
+func (t *Txn) sendRawTransaction(txn *ethgo.Transaction) (ethgo.Hash, error) {
+	if txn.Type == ethgo.TransactionDynamicFee && (txn.MaxFeePerGas == nil || txn.MaxPriorityFeePerGas == nil) {
+		return ethgo.Hash{}, fmt.Errorf("both max fee per gas and max priority fee per gas are required")
+	}
+	if txn.Value == nil {
+		txn.Value = big.NewInt(0)
+	}
+
+	chainID, err := t.provider.Eth().ChainID()
+	if err != nil {
+		return ethgo.Hash{}, err
+	}
+	if txn.Type != ethgo.TransactionLegacy {
+		txn.ChainID = chainID
+	}
+
+	signer := t.signer
+	if signer == nil {
+		signer = wallet.NewEIP155Signer(chainID.Uint64())
+	}
+
+	var hash ethgo.Hash
+	send := func(nonce uint64) error {
+		txn.Nonce = nonce
+
+		signedTxn, err := signer.SignTxWithKey(txn, t.key)
+		if err != nil {
+			return err
+		}
+		raw, err := signedTxn.MarshalRLPTo(nil)
+		if err != nil {
+			return err
+		}
+		hash, err = t.provider.Eth().SendRawTransaction(raw)
+		return err
+	}
+
+	if t.nonce != nil {
+		err = send(*t.nonce)
+	} else if t.nonces != nil {
+		err = t.nonces.Do(t.key.Address(), send)
+	} else {
+		var nonce uint64
+		if nonce, err = t.provider.Eth().GetNonce(t.key.Address(), ethgo.Pending); err == nil {
+			err = send(nonce)
+		}
+	}
+	if err != nil {
+		return ethgo.Hash{}, err
+	}
+	return hash, nil
+}
+
+// unpackRevert returns an *abi.RevertError if the node returned the
+// revert data of the call in the error, otherwise it returns the error.
+func unpackRevert(err error, a *abi.ABI) error {
+	var obj *codec.ErrorObject
+	if !errors.As(err, &obj) {
+		return err
+	}
+	str, ok := obj.Data.(string)
+	if !ok || !strings.HasPrefix(str, "0x") {
+		return err
+	}
+	data, decodeErr := hex.DecodeString(str[2:])
+	if decodeErr != nil {
+		return err
+	}
+
+	var errs map[string]*abi.Error
+	if a != nil {
+		errs = a.Errors
+	}
+	return abi.UnpackRevert(data, errs)
+}
+
 // Validate validates the arguments of the transaction
 func (t *Txn) Validate() error {
 	if t.data != nil {
@@ -248,6 +426,50 @@ func (t *Txn) SetGasPrice(gasPrice uint64) *Txn {
 	return t
 }
 
+// SetMaxFeePerGas sets the max fee per gas of an EIP-1559 transaction
+func (t *Txn) SetMaxFeePerGas(maxFeePerGas *big.Int) *Txn {
+	t.maxFeePerGas = new(big.Int).Set(maxFeePerGas)
+	return t
+}
+
+// SetMaxPriorityFeePerGas sets the max priority fee per gas of an EIP-1559 transaction
+func (t *Txn) SetMaxPriorityFeePerGas(maxPriorityFeePerGas *big.Int) *Txn {
+	t.maxPriorityFeePerGas = new(big.Int).Set(maxPriorityFeePerGas)
+	return t
+}
+
+// SetGasOracle sets the gas oracle used to suggest the fees of the transaction
+func (t *Txn) SetGasOracle(o *gasoracle.Oracle) *Txn {
+	t.oracle = o
+	return t
+}
+
+// SetKey sets the key used to sign the transaction locally
+func (t *Txn) SetKey(key ethgo.Key) *Txn {
+	t.key = key
+	t.from = key.Address()
+	return t
+}
+
+// SetSigner sets the signer used to sign the transaction with the key
+func (t *Txn) SetSigner(signer wallet.KeySigner) *Txn {
+	t.signer = signer
+	return t
+}
+
+// SetNonce sets the nonce of the transaction signed with the key
+func (t *Txn) SetNonce(nonce uint64) *Txn {
+	t.nonce = &nonce
+	return t
+}
+
+// SetNonceManager sets the nonce manager used to get the nonce
+// of the transaction signed with the key
+func (t *Txn) SetNonceManager(m *nonce.Manager) *Txn {
+	t.nonces = m
+	return t
+}
+
 // SetGasLimit sets the gas limit of the transaction
 func (t *Txn) SetGasLimit(gasLimit uint64) *Txn {
 	t.gasLimit = gasLimit
@@ -2,13 +2,18 @@ package contract
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"math/big"
 	"testing"
 
 	"github.com/git-yongge/ethgo"
 	"github.com/git-yongge/ethgo/abi"
+	"github.com/git-yongge/ethgo/gasoracle"
 	"github.com/git-yongge/ethgo/jsonrpc"
+	"github.com/git-yongge/ethgo/jsonrpc/codec"
+	"github.com/git-yongge/ethgo/nonce"
 	"github.com/git-yongge/ethgo/testutil"
+	"github.com/git-yongge/ethgo/wallet"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -106,3 +111,224 @@ func TestDeployContract(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, resp["0"], big.NewInt(1000))
 }
+
+func TestTxn_SignWithKey(t *testing.T) {
+	s := testutil.NewMockServer()
+	defer s.Close()
+
+	key, err := wallet.GenerateKey()
+	assert.NoError(t, err)
+
+	s.HandleResult("eth_getTransactionCount", "0x5")
+	s.HandleResult("eth_chainId", "0x539")
+	s.HandleResult("eth_gasPrice", "0x10")
+	s.HandleResult("eth_estimateGas", "0x5208")
+
+	rawCh := make(chan string, 1)
+	s.Handle("eth_sendRawTransaction", func(params []json.RawMessage) (interface{}, error) {
+		var raw string
+		if err := json.Unmarshal(params[0], &raw); err != nil {
+			return nil, err
+		}
+		rawCh <- raw
+		return ethgo.Hash{0x1}, nil
+	})
+
+	p, _ := jsonrpc.NewClient(s.HTTPAddr())
+
+	abi0, err := abi.NewABIFromList([]string{
+		"function set(uint256)",
+	})
+	assert.NoError(t, err)
+
+	c := NewContract(ethgo.Address{0x1}, abi0, p)
+	c.SetKey(key)
+
+	decodeTxn := func() *ethgo.Transaction {
+		raw := <-rawCh
+		buf, err := hex.DecodeString(raw[2:])
+		assert.NoError(t, err)
+
+		txn := &ethgo.Transaction{}
+		assert.NoError(t, txn.UnmarshalRLP(buf))
+
+		from, err := wallet.NewEIP155Signer(1337).RecoverSender(txn)
+		assert.NoError(t, err)
+		assert.Equal(t, key.Address(), from)
+		return txn
+	}
+
+	t.Run("legacy", func(t *testing.T) {
+		// pre-London blocks do not have a base fee
+		s.HandleResult("eth_feeHistory", map[string]interface{}{
+			"oldestBlock":   "0x1",
+			"baseFeePerGas": []string{"0x0", "0x0"},
+			"gasUsedRatio":  []float64{0.5},
+			"reward":        [][]string{{"0x0"}},
+		})
+
+		txn := c.Txn("set", big.NewInt(1))
+		assert.NoError(t, txn.Do())
+
+		signed := decodeTxn()
+		assert.Equal(t, ethgo.TransactionLegacy, signed.Type)
+		assert.Equal(t, uint64(5), signed.Nonce)
+		assert.Equal(t, uint64(0x10), signed.GasPrice)
+		assert.Equal(t, uint64(0x5208), signed.Gas)
+	})
+
+	t.Run("dynamic fee", func(t *testing.T) {
+		txn := c.Txn("set", big.NewInt(1)).
+			SetMaxFeePerGas(big.NewInt(100)).
+			SetMaxPriorityFeePerGas(big.NewInt(2))
+		assert.NoError(t, txn.Do())
+
+		signed := decodeTxn()
+		assert.Equal(t, ethgo.TransactionDynamicFee, signed.Type)
+		assert.Equal(t, uint64(1337), signed.ChainID.Uint64())
+		assert.Equal(t, big.NewInt(100), signed.MaxFeePerGas)
+		assert.Equal(t, big.NewInt(2), signed.MaxPriorityFeePerGas)
+	})
+
+	t.Run("gas oracle", func(t *testing.T) {
+		s.HandleResult("eth_feeHistory", map[string]interface{}{
+			"oldestBlock":   "0x1",
+			"baseFeePerGas": []string{"0x64", "0x64"},
+			"gasUsedRatio":  []float64{0.5},
+			"reward":        [][]string{{"0x2"}},
+		})
+
+		txn := c.Txn("set", big.NewInt(1))
+		assert.NoError(t, txn.Do())
+
+		signed := decodeTxn()
+		assert.Equal(t, ethgo.TransactionDynamicFee, signed.Type)
+		assert.Equal(t, big.NewInt(127), signed.MaxFeePerGas)
+		assert.Equal(t, big.NewInt(2), signed.MaxPriorityFeePerGas)
+	})
+
+	t.Run("fee history not supported", func(t *testing.T) {
+		// pre-London nodes do not implement eth_feeHistory
+		s.Handle("eth_feeHistory", func(params []json.RawMessage) (interface{}, error) {
+			return nil, &codec.ErrorObject{Code: -32601, Message: "the method eth_feeHistory does not exist"}
+		})
+
+		txn := c.Txn("set", big.NewInt(1))
+		assert.NoError(t, txn.Do())
+
+		signed := decodeTxn()
+		assert.Equal(t, ethgo.TransactionLegacy, signed.Type)
+		assert.Equal(t, uint64(0x10), signed.GasPrice)
+
+		// an explicit oracle reports the error
+		txn = c.Txn("set", big.NewInt(1)).SetGasOracle(gasoracle.NewOracle(p.Eth()))
+		assert.Error(t, txn.Do())
+	})
+
+	t.Run("nonce manager", func(t *testing.T) {
+		m := nonce.NewManager(p.Eth())
+
+		for i := uint64(0); i < 3; i++ {
+			txn := c.Txn("set", big.NewInt(1)).SetNonceManager(m)
+			assert.NoError(t, txn.Do())
+
+			signed := decodeTxn()
+			assert.Equal(t, 5+i, signed.Nonce)
+		}
+	})
+}
+
+func TestTxn_FeesWithoutKey(t *testing.T) {
+	s := testutil.NewMockServer()
+	defer s.Close()
+
+	s.HandleResult("eth_estimateGas", "0x5208")
+	s.HandleResult("eth_feeHistory", map[string]interface{}{
+		"oldestBlock":   "0x1",
+		"baseFeePerGas": []string{"0x64", "0x64"},
+		"gasUsedRatio":  []float64{0.5},
+		"reward":        [][]string{{"0x2"}},
+	})
+
+	txnCh := make(chan map[string]interface{}, 1)
+	s.Handle("eth_sendTransaction", func(params []json.RawMessage) (interface{}, error) {
+		var obj map[string]interface{}
+		if err := json.Unmarshal(params[0], &obj); err != nil {
+			return nil, err
+		}
+		txnCh <- obj
+		return ethgo.Hash{0x1}, nil
+	})
+
+	p, _ := jsonrpc.NewClient(s.HTTPAddr())
+
+	abi0, err := abi.NewABIFromList([]string{
+		"function set(uint256)",
+	})
+	assert.NoError(t, err)
+
+	c := NewContract(ethgo.Address{0x1}, abi0, p)
+	c.SetFrom(ethgo.Address{0x2})
+
+	// the node signs the transaction with the fees of the oracle
+	assert.NoError(t, c.Txn("set", big.NewInt(1)).Do())
+
+	obj := <-txnCh
+	assert.NotContains(t, obj, "gasPrice")
+	assert.Equal(t, "0x7f", obj["maxFeePerGas"])
+	assert.Equal(t, "0x2", obj["maxPriorityFeePerGas"])
+}
+
+func TestContract_RevertError(t *testing.T) {
+	s := testutil.NewMockServer()
+	defer s.Close()
+
+	abi0, err := abi.NewABIFromList([]string{
+		"function withdraw(uint256 amount) returns (uint256)",
+		"error InsufficientBalance(uint256 available, uint256 required)",
+	})
+	assert.NoError(t, err)
+
+	revertData, err := abi0.Errors["InsufficientBalance"].Encode([]interface{}{big.NewInt(1), big.NewInt(2)})
+	assert.NoError(t, err)
+
+	revert := func(params []json.RawMessage) (interface{}, error) {
+		return nil, &codec.ErrorObject{
+			Code:    3,
+			Message: "execution reverted",
+			Data:    "0x" + hex.EncodeToString(revertData),
+		}
+	}
+	s.Handle("eth_call", revert)
+	s.Handle("eth_estimateGas", revert)
+	s.HandleResult("eth_gasPrice", "0x10")
+
+	p, _ := jsonrpc.NewClient(s.HTTPAddr())
+
+	c := NewContract(ethgo.Address{0x1}, abi0, p)
+	c.SetFrom(ethgo.Address{0x2})
+
+	checkRevert := func(err error) {
+		revertErr, ok := err.(*abi.RevertError)
+		assert.True(t, ok)
+		assert.Equal(t, "InsufficientBalance", revertErr.CustomError.Name)
+		assert.Equal(t, big.NewInt(2), revertErr.Args["required"])
+	}
+
+	_, err = c.Call("withdraw", ethgo.Latest, big.NewInt(2))
+	checkRevert(err)
+
+	_, err = c.Txn("withdraw", big.NewInt(2)).EstimateGas()
+	checkRevert(err)
+
+	err = c.Txn("withdraw", big.NewInt(2)).Do()
+	checkRevert(err)
+
+	// errors without revert data are returned as they are
+	s.Handle("eth_call", func(params []json.RawMessage) (interface{}, error) {
+		return nil, &codec.ErrorObject{Code: -32000, Message: "header not found"}
+	})
+	_, err = c.Call("withdraw", ethgo.Latest, big.NewInt(2))
+	_, ok := err.(*codec.ErrorObject)
+	assert.True(t, ok)
+}
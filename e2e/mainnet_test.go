@@ -0,0 +1,133 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/jsonrpc"
+	"github.com/git-yongge/ethgo/trie"
+	"github.com/stretchr/testify/assert"
+)
+
+var updateTestsuite = flag.Bool("update-testsuite", false, "write the mainnet blocks to the testsuite")
+
+func testMainnetClient(t *testing.T) *jsonrpc.Client {
+	url := os.Getenv("ETH_MAINNET_URL")
+	if url == "" {
+		t.Skip("Mainnet url not specified")
+	}
+	c, err := jsonrpc.NewClient(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestMainnet_ForkBlocks(t *testing.T) {
+	// the first block of each fork on mainnet
+	cases := []struct {
+		name   string
+		number ethgo.BlockNumber
+	}{
+		{"block-mainnet-london.json", 12965000},
+		{"block-mainnet-paris.json", 15537394},
+		{"block-mainnet-shanghai.json", 17034870},
+		{"block-mainnet-cancun.json", 19426587},
+	}
+
+	c := testMainnetClient(t)
+	defer c.Close()
+
+	for _, cc := range cases {
+		t.Run(cc.name, func(t *testing.T) {
+			b, err := c.Eth().GetBlockByNumber(cc.number, false)
+			assert.NoError(t, err)
+
+			hash, err := b.ComputeHash()
+			assert.NoError(t, err)
+			assert.Equal(t, b.Hash, hash)
+
+			raw, err := b.MarshalJSON()
+			assert.NoError(t, err)
+
+			var buf bytes.Buffer
+			assert.NoError(t, json.Indent(&buf, raw, "", "    "))
+			buf.WriteString("\n")
+
+			path := filepath.Join("../testsuite", cc.name)
+			if *updateTestsuite {
+				assert.NoError(t, ioutil.WriteFile(path, buf.Bytes(), 0644))
+				return
+			}
+
+			expected, err := ioutil.ReadFile(path)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(expected), buf.String())
+		})
+	}
+}
+
+// mainnetBlock is a block with its full transactions and receipts
+// as returned by the node
+type mainnetBlock struct {
+	Block    json.RawMessage   `json:"block"`
+	Receipts []json.RawMessage `json:"receipts"`
+}
+
+func TestMainnet_DeriveRoots(t *testing.T) {
+	// a block of each typed transaction era on mainnet
+	cases := []struct {
+		name   string
+		number ethgo.BlockNumber
+	}{
+		{"mainnet-legacy.json", 4370000},
+		{"mainnet-access-list.json", 12244000},
+		{"mainnet-dynamic-fee.json", 12965000},
+		{"mainnet-blob.json", 19426587},
+	}
+
+	c := testMainnetClient(t)
+	defer c.Close()
+
+	for _, cc := range cases {
+		t.Run(cc.name, func(t *testing.T) {
+			var res mainnetBlock
+			assert.NoError(t, c.Call("eth_getBlockByNumber", &res.Block, cc.number.String(), true))
+
+			b := new(ethgo.Block)
+			assert.NoError(t, b.UnmarshalJSON(res.Block))
+
+			receipts := []*ethgo.Receipt{}
+			for _, txn := range b.Transactions {
+				var raw json.RawMessage
+				assert.NoError(t, c.Call("eth_getTransactionReceipt", &raw, txn.Hash))
+
+				receipt := new(ethgo.Receipt)
+				assert.NoError(t, receipt.UnmarshalJSON(raw))
+
+				receipts = append(receipts, receipt)
+				res.Receipts = append(res.Receipts, raw)
+			}
+
+			root, err := trie.TransactionsRoot(b.Transactions)
+			assert.NoError(t, err)
+			assert.Equal(t, b.TransactionsRoot, root)
+
+			root, err = trie.ReceiptsRoot(receipts)
+			assert.NoError(t, err)
+			assert.Equal(t, b.ReceiptsRoot, root)
+
+			if *updateTestsuite {
+				raw, err := json.MarshalIndent(res, "", "    ")
+				assert.NoError(t, err)
+				assert.NoError(t, ioutil.WriteFile(filepath.Join("../testsuite", cc.name), append(raw, '\n'), 0644))
+			}
+		})
+	}
+}
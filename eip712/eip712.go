@@ -0,0 +1,444 @@
+package eip712
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/abi"
+	"github.com/git-yongge/ethgo/wallet"
+)
+
+// domainType is the name of the type of the domain
+const domainType = "EIP712Domain"
+
+// Field is a member of a struct type
+type Field struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Types are the struct types of the typed data by name
+type Types map[string][]*Field
+
+// Domain is the domain separator of the typed data. Only the
+// fields that are set are part of the domain.
+type Domain struct {
+	Name              string
+	Version           string
+	ChainID           *big.Int
+	VerifyingContract *ethgo.Address
+	Salt              *ethgo.Hash
+}
+
+type domainJSON struct {
+	Name              string         `json:"name,omitempty"`
+	Version           string         `json:"version,omitempty"`
+	ChainID           interface{}    `json:"chainId,omitempty"`
+	VerifyingContract *ethgo.Address `json:"verifyingContract,omitempty"`
+	Salt              *ethgo.Hash    `json:"salt,omitempty"`
+}
+
+// MarshalJSON implements the json marshaler interface
+func (d *Domain) MarshalJSON() ([]byte, error) {
+	obj := &domainJSON{
+		Name:              d.Name,
+		Version:           d.Version,
+		VerifyingContract: d.VerifyingContract,
+		Salt:              d.Salt,
+	}
+	if d.ChainID != nil {
+		obj.ChainID = json.Number(d.ChainID.String())
+	}
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON implements the json unmarshaler interface. The chain id
+// is either a number or a decimal or hex string.
+func (d *Domain) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var obj domainJSON
+	if err := dec.Decode(&obj); err != nil {
+		return err
+	}
+	d.Name = obj.Name
+	d.Version = obj.Version
+	d.VerifyingContract = obj.VerifyingContract
+	d.Salt = obj.Salt
+	d.ChainID = nil
+
+	if obj.ChainID != nil {
+		chainID, err := parseBigInt(obj.ChainID)
+		if err != nil {
+			return fmt.Errorf("invalid chain id: %v", err)
+		}
+		d.ChainID = chainID
+	}
+	return nil
+}
+
+// Map returns the values of the domain that are set
+func (d *Domain) Map() map[string]interface{} {
+	res := map[string]interface{}{}
+	if d.Name != "" {
+		res["name"] = d.Name
+	}
+	if d.Version != "" {
+		res["version"] = d.Version
+	}
+	if d.ChainID != nil {
+		res["chainId"] = d.ChainID
+	}
+	if d.VerifyingContract != nil {
+		res["verifyingContract"] = *d.VerifyingContract
+	}
+	if d.Salt != nil {
+		res["salt"] = *d.Salt
+	}
+	return res
+}
+
+// fields returns the type of the domain with the fields that are set
+func (d *Domain) fields() []*Field {
+	fields := []*Field{}
+	if d.Name != "" {
+		fields = append(fields, &Field{Name: "name", Type: "string"})
+	}
+	if d.Version != "" {
+		fields = append(fields, &Field{Name: "version", Type: "string"})
+	}
+	if d.ChainID != nil {
+		fields = append(fields, &Field{Name: "chainId", Type: "uint256"})
+	}
+	if d.VerifyingContract != nil {
+		fields = append(fields, &Field{Name: "verifyingContract", Type: "address"})
+	}
+	if d.Salt != nil {
+		fields = append(fields, &Field{Name: "salt", Type: "bytes32"})
+	}
+	return fields
+}
+
+// TypedData is the typed structured data of EIP-712
+type TypedData struct {
+	Types       Types                  `json:"types"`
+	PrimaryType string                 `json:"primaryType"`
+	Domain      *Domain                `json:"domain"`
+	Message     map[string]interface{} `json:"message"`
+}
+
+// ParseTypedData parses the typed data in the json format of eth_signTypedData_v4
+func ParseTypedData(data []byte) (*TypedData, error) {
+	t := &TypedData{}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// UnmarshalJSON implements the json unmarshaler interface. The numbers
+// of the message are decoded without loss of precision.
+func (t *TypedData) UnmarshalJSON(data []byte) error {
+	type typedData TypedData
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var obj typedData
+	if err := dec.Decode(&obj); err != nil {
+		return err
+	}
+	*t = TypedData(obj)
+	return nil
+}
+
+// EncodeType returns the encoding of the type with the types it references
+// sorted by name (i.e. 'Mail(Person from,Person to,string contents)Person(string name,address wallet)')
+func (t *TypedData) EncodeType(primaryType string) (string, error) {
+	if _, ok := t.fieldsOf(primaryType); !ok {
+		return "", fmt.Errorf("type %s not found", primaryType)
+	}
+
+	deps := map[string]struct{}{}
+	t.dependencies(primaryType, deps)
+	delete(deps, primaryType)
+
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf strings.Builder
+	for _, name := range append([]string{primaryType}, names...) {
+		fields, _ := t.fieldsOf(name)
+
+		args := make([]string, len(fields))
+		for i, field := range fields {
+			args[i] = field.Type + " " + field.Name
+		}
+		buf.WriteString(name + "(" + strings.Join(args, ",") + ")")
+	}
+	return buf.String(), nil
+}
+
+func (t *TypedData) dependencies(typ string, deps map[string]struct{}) {
+	typ = baseType(typ)
+	if _, ok := deps[typ]; ok {
+		return
+	}
+	fields, ok := t.fieldsOf(typ)
+	if !ok {
+		return
+	}
+	deps[typ] = struct{}{}
+	for _, field := range fields {
+		t.dependencies(field.Type, deps)
+	}
+}
+
+// TypeHash returns the hash of the encoding of the type
+func (t *TypedData) TypeHash(primaryType string) (ethgo.Hash, error) {
+	str, err := t.EncodeType(primaryType)
+	if err != nil {
+		return ethgo.Hash{}, err
+	}
+	return ethgo.BytesToHash(ethgo.Keccak256([]byte(str))), nil
+}
+
+// EncodeData encodes the values of a struct (the type hash followed by the
+// 32 bytes encoding of each field)
+func (t *TypedData) EncodeData(primaryType string, data map[string]interface{}) ([]byte, error) {
+	fields, ok := t.fieldsOf(primaryType)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found", primaryType)
+	}
+	typeHash, err := t.TypeHash(primaryType)
+	if err != nil {
+		return nil, err
+	}
+
+	res := append([]byte{}, typeHash[:]...)
+	for _, field := range fields {
+		value, ok := data[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("field %s of %s not found", field.Name, primaryType)
+		}
+		val, err := t.encodeValue(field.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode field %s of %s: %v", field.Name, primaryType, err)
+		}
+		res = append(res, val...)
+	}
+	return res, nil
+}
+
+// HashStruct returns the hash of the encoding of a struct
+func (t *TypedData) HashStruct(primaryType string, data map[string]interface{}) (ethgo.Hash, error) {
+	buf, err := t.EncodeData(primaryType, data)
+	if err != nil {
+		return ethgo.Hash{}, err
+	}
+	return ethgo.BytesToHash(ethgo.Keccak256(buf)), nil
+}
+
+// DomainSeparator returns the hash of the domain
+func (t *TypedData) DomainSeparator() (ethgo.Hash, error) {
+	if t.Domain == nil {
+		return ethgo.Hash{}, fmt.Errorf("domain not set")
+	}
+	return t.HashStruct(domainType, t.Domain.Map())
+}
+
+// Digest returns the hash to sign of the message:
+// keccak256("\x19\x01" ‖ domainSeparator ‖ hashStruct(message))
+func (t *TypedData) Digest() (ethgo.Hash, error) {
+	domainSeparator, err := t.DomainSeparator()
+	if err != nil {
+		return ethgo.Hash{}, err
+	}
+	msgHash, err := t.HashStruct(t.PrimaryType, t.Message)
+	if err != nil {
+		return ethgo.Hash{}, err
+	}
+
+	buf := []byte{0x19, 0x01}
+	buf = append(buf, domainSeparator[:]...)
+	buf = append(buf, msgHash[:]...)
+	return ethgo.BytesToHash(ethgo.Keccak256(buf)), nil
+}
+
+// Sign signs the digest of the typed data with the key. As in eth_signTypedData_v4,
+// the signature is encoded as r ‖ s ‖ v with v being 27 or 28.
+func (t *TypedData) Sign(key ethgo.Key) ([]byte, error) {
+	digest, err := t.Digest()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := key.Sign(digest[:])
+	if err != nil {
+		return nil, err
+	}
+	if sig[64] < 27 {
+		sig[64] += 27
+	}
+	return sig, nil
+}
+
+// Recover returns the address that signed the typed data. The v value
+// of the signature can be either 0 or 1 or 27 or 28.
+func (t *TypedData) Recover(signature []byte) (ethgo.Address, error) {
+	if len(signature) != 65 {
+		return ethgo.Address{}, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+	digest, err := t.Digest()
+	if err != nil {
+		return ethgo.Address{}, err
+	}
+	return wallet.Ecrecover(digest[:], signature)
+}
+
+// fieldsOf returns the fields of a struct type
+func (t *TypedData) fieldsOf(typ string) ([]*Field, bool) {
+	fields, ok := t.Types[typ]
+	if !ok && typ == domainType && t.Domain != nil {
+		// the type of the domain is derived from the values if not set
+		return t.Domain.fields(), true
+	}
+	return fields, ok
+}
+
+// encodeValue returns the 32 bytes encoding of the value of a field
+func (t *TypedData) encodeValue(typ string, value interface{}) ([]byte, error) {
+	if num, ok := value.(json.Number); ok {
+		value = num.String()
+	}
+
+	if strings.HasSuffix(typ, "]") {
+		// the arrays are encoded as the hash of the concatenation of the encodings
+		indx := strings.LastIndex(typ, "[")
+		if indx == -1 {
+			return nil, fmt.Errorf("invalid array type %s", typ)
+		}
+		elemType, sizeStr := typ[:indx], typ[indx+1:len(typ)-1]
+
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return nil, fmt.Errorf("expected an array for %s", typ)
+		}
+		if sizeStr != "" {
+			size, err := strconv.Atoi(sizeStr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid array type %s", typ)
+			}
+			if size != v.Len() {
+				return nil, fmt.Errorf("expected %d elements for %s but found %d", size, typ, v.Len())
+			}
+		}
+
+		var buf []byte
+		for i := 0; i < v.Len(); i++ {
+			val, err := t.encodeValue(elemType, v.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			buf = append(buf, val...)
+		}
+		return ethgo.Keccak256(buf), nil
+	}
+
+	if _, ok := t.fieldsOf(typ); ok {
+		// the structs are encoded as the hash of the struct
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected an object for %s", typ)
+		}
+		hash, err := t.HashStruct(typ, data)
+		if err != nil {
+			return nil, err
+		}
+		return hash[:], nil
+	}
+
+	switch typ {
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string")
+		}
+		return ethgo.Keccak256([]byte(str)), nil
+
+	case "bytes":
+		buf, err := parseBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return ethgo.Keccak256(buf), nil
+	}
+
+	// the atomic types use the standard abi encoding
+	abiType, err := abi.NewType(typ)
+	if err != nil {
+		return nil, err
+	}
+	switch abiType.Kind() {
+	case abi.KindBool, abi.KindAddress, abi.KindInt, abi.KindUInt, abi.KindFixedBytes:
+	default:
+		return nil, fmt.Errorf("type %s not supported", typ)
+	}
+	return abiType.Encode(value)
+}
+
+func parseBytes(value interface{}) ([]byte, error) {
+	switch obj := value.(type) {
+	case []byte:
+		return obj, nil
+	case string:
+		if !strings.HasPrefix(obj, "0x") {
+			return nil, fmt.Errorf("expected a hex string with 0x prefix")
+		}
+		return hex.DecodeString(obj[2:])
+	default:
+		return nil, fmt.Errorf("expected bytes")
+	}
+}
+
+func parseBigInt(value interface{}) (*big.Int, error) {
+	var str string
+	switch obj := value.(type) {
+	case json.Number:
+		str = obj.String()
+	case string:
+		str = obj
+	default:
+		return nil, fmt.Errorf("expected a number")
+	}
+
+	num, ok := new(big.Int), false
+	if strings.HasPrefix(str, "0x") {
+		num, ok = num.SetString(str[2:], 16)
+	} else {
+		num, ok = num.SetString(str, 10)
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid number %s", str)
+	}
+	return num, nil
+}
+
+// baseType returns the type of the elements of an array type
+func baseType(typ string) string {
+	if indx := strings.Index(typ, "["); indx != -1 {
+		return typ[:indx]
+	}
+	return typ
+}
@@ -0,0 +1,224 @@
+package eip712
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func readTypedData(t *testing.T, name string) *TypedData {
+	data, err := ioutil.ReadFile("./testdata/" + name)
+	assert.NoError(t, err)
+
+	typedData, err := ParseTypedData(data)
+	assert.NoError(t, err)
+	return typedData
+}
+
+func TestTypedData_Vectors(t *testing.T) {
+	// the vectors are computed with go-ethereum
+	cases := []struct {
+		file       string
+		encodeType string
+		domain     string
+		message    string
+		digest     string
+		signature  string
+	}{
+		{
+			// example of the EIP-712 specification
+			"mail.json",
+			"Mail(Person from,Person to,string contents)Person(string name,address wallet)",
+			"0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f",
+			"0xc52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e",
+			"0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2",
+			"4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c",
+		},
+		{
+			"order.json",
+			"Order(address maker,Item[] items,string[] tags,uint256 nonce,int64 offset,bytes extra,bytes32[] hashes,bool[] flags)Item(address token,uint256 amount,Meta meta)Meta(string note,int32 id)",
+			"0xc8b4294d344de28f2038e67add08baf2a3c79b5bbe84744a0a01a5fb9a1f03b1",
+			"0x21242710c870caa34163a935988526643316b15eb06229d47cf1cdcc847d430d",
+			"0x51bbbe8b6ab91ca97e87f52c99ae3e9f3cf214376fe5f39df1f29b32e6e2dc63",
+			"d31d3e63fb8cc7f4e3d8188a50cf36bb5686c6ee29a68fefe7297a1208fb321172f2106ca87293c3a65a0099022e3bf40f7ba05edb3486aa99db4feabf5a3c3e1c",
+		},
+	}
+
+	// the key of the specification is keccak256("cow")
+	key, err := wallet.NewWalletFromPrivKey(ethgo.Keccak256([]byte("cow")))
+	assert.NoError(t, err)
+
+	for _, c := range cases {
+		t.Run(c.file, func(t *testing.T) {
+			typedData := readTypedData(t, c.file)
+
+			encodeType, err := typedData.EncodeType(typedData.PrimaryType)
+			assert.NoError(t, err)
+			assert.Equal(t, c.encodeType, encodeType)
+
+			domain, err := typedData.DomainSeparator()
+			assert.NoError(t, err)
+			assert.Equal(t, c.domain, domain.String())
+
+			message, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+			assert.NoError(t, err)
+			assert.Equal(t, c.message, message.String())
+
+			digest, err := typedData.Digest()
+			assert.NoError(t, err)
+			assert.Equal(t, c.digest, digest.String())
+
+			signature, err := typedData.Sign(key)
+			assert.NoError(t, err)
+			assert.Equal(t, c.signature, hex.EncodeToString(signature))
+
+			addr, err := typedData.Recover(signature)
+			assert.NoError(t, err)
+			assert.Equal(t, key.Address(), addr)
+
+			// v as 0 or 1
+			signature[64] -= 27
+			addr, err = typedData.Recover(signature)
+			assert.NoError(t, err)
+			assert.Equal(t, key.Address(), addr)
+		})
+	}
+}
+
+func TestTypedData_GoValues(t *testing.T) {
+	// the mail example with go values and the type of the domain derived from its values
+	verifyingContract := ethgo.HexToAddress("0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC")
+
+	typedData := &TypedData{
+		Types: Types{
+			"Person": {
+				{Name: "name", Type: "string"},
+				{Name: "wallet", Type: "address"},
+			},
+			"Mail": {
+				{Name: "from", Type: "Person"},
+				{Name: "to", Type: "Person"},
+				{Name: "contents", Type: "string"},
+			},
+		},
+		PrimaryType: "Mail",
+		Domain: &Domain{
+			Name:              "Ether Mail",
+			Version:           "1",
+			ChainID:           big.NewInt(1),
+			VerifyingContract: &verifyingContract,
+		},
+		Message: map[string]interface{}{
+			"from": map[string]interface{}{
+				"name":   "Cow",
+				"wallet": ethgo.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"),
+			},
+			"to": map[string]interface{}{
+				"name":   "Bob",
+				"wallet": ethgo.HexToAddress("0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"),
+			},
+			"contents": "Hello, Bob!",
+		},
+	}
+
+	digest, err := typedData.Digest()
+	assert.NoError(t, err)
+	assert.Equal(t, "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2", digest.String())
+
+	// json round trip
+	data, err := json.Marshal(typedData)
+	assert.NoError(t, err)
+
+	typedData2, err := ParseTypedData(data)
+	assert.NoError(t, err)
+
+	digest2, err := typedData2.Digest()
+	assert.NoError(t, err)
+	assert.Equal(t, digest, digest2)
+}
+
+func TestTypedData_Arrays(t *testing.T) {
+	typedData := &TypedData{
+		Types: Types{
+			"Grid": {
+				{Name: "cells", Type: "uint16[][]"},
+				{Name: "corners", Type: "bytes1[2]"},
+			},
+		},
+		PrimaryType: "Grid",
+		Domain:      &Domain{Name: "grid"},
+	}
+
+	encode := func(vals ...uint64) []byte {
+		var buf []byte
+		for _, val := range vals {
+			buf = append(buf, ethgo.BytesToHash(new(big.Int).SetUint64(val).Bytes()).Bytes()...)
+		}
+		return buf
+	}
+
+	// nested arrays are the hash of the concatenation of the hashes of each array
+	var cells []byte
+	cells = append(cells, ethgo.Keccak256(encode(1, 2))...)
+	cells = append(cells, ethgo.Keccak256(encode())...)
+	cells = append(cells, ethgo.Keccak256(encode(3))...)
+
+	corners := ethgo.Keccak256(
+		ethgo.Hash{0xaa}.Bytes(),
+		ethgo.Hash{0xbb}.Bytes(),
+	)
+
+	typeHash := ethgo.Keccak256([]byte("Grid(uint16[][] cells,bytes1[2] corners)"))
+
+	data, err := typedData.EncodeData("Grid", map[string]interface{}{
+		"cells":   [][]uint16{{1, 2}, {}, {3}},
+		"corners": []string{"0xaa", "0xbb"},
+	})
+	assert.NoError(t, err)
+
+	expected := append(typeHash, ethgo.Keccak256(cells)...)
+	expected = append(expected, corners...)
+	assert.Equal(t, expected, data)
+
+	// the size of the fixed arrays is checked
+	_, err = typedData.EncodeData("Grid", map[string]interface{}{
+		"cells":   [][]uint16{},
+		"corners": []string{"0xaa"},
+	})
+	assert.Error(t, err)
+}
+
+func TestTypedData_Errors(t *testing.T) {
+	typedData := &TypedData{
+		Types: Types{
+			"A": {
+				{Name: "b", Type: "B"},
+			},
+		},
+		PrimaryType: "A",
+		Domain:      &Domain{Name: "a"},
+		Message: map[string]interface{}{
+			"b": map[string]interface{}{},
+		},
+	}
+
+	// unknown type
+	_, err := typedData.Digest()
+	assert.Error(t, err)
+
+	// missing field
+	typedData.Types["A"][0].Type = "uint256"
+	typedData.Message = map[string]interface{}{}
+
+	_, err = typedData.Digest()
+	assert.Error(t, err)
+
+	_, err = typedData.Recover([]byte{0x1})
+	assert.Error(t, err)
+}
@@ -3,20 +3,43 @@ package ens
 import (
 	"fmt"
 	"log"
+	"math/big"
+	"strings"
 
 	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/abi"
+	"github.com/git-yongge/ethgo/contract"
 	"github.com/git-yongge/ethgo/jsonrpc"
 )
 
+var registryABI = abi.MustNewABIFromList([]string{
+	"function resolver(bytes32 node) view returns (address)",
+	"function owner(bytes32 node) view returns (address)",
+})
+
+var resolverABI = abi.MustNewABIFromList([]string{
+	"function addr(bytes32 node) view returns (address)",
+	"function addr(bytes32 node, uint256 coinType) view returns (bytes)",
+	"function name(bytes32 node) view returns (string)",
+	"function text(bytes32 node, string key) view returns (string)",
+	"function contenthash(bytes32 node) view returns (bytes)",
+})
+
+// CoinTypeETH is the coin type of the ether addresses (SLIP-44)
+const CoinTypeETH = 60
+
 type EnsConfig struct {
-	Logger   *log.Logger
-	Client   *jsonrpc.Client
-	Addr     string
+	Logger *log.Logger
+	Client *jsonrpc.Client
+	Addr   string
+
+	// Resolver is the address of the ENS registry
 	Resolver ethgo.Address
 }
 
 type EnsOption func(*EnsConfig)
 
+// WithResolver sets the address of the ENS registry used to look up the resolvers
 func WithResolver(resolver ethgo.Address) EnsOption {
 	return func(c *EnsConfig) {
 		c.Resolver = resolver
@@ -42,7 +65,8 @@ func WithClient(client *jsonrpc.Client) EnsOption {
 }
 
 type ENS struct {
-	config *EnsConfig
+	config   *EnsConfig
+	registry *contract.Contract
 }
 
 func NewENS(opts ...EnsOption) (*ENS, error) {
@@ -76,12 +100,126 @@ func NewENS(opts ...EnsOption) (*ENS, error) {
 		config.Resolver = addr
 	}
 	ens := &ENS{
-		config: config,
+		config:   config,
+		registry: contract.NewContract(config.Resolver, registryABI, config.Client),
 	}
 	return ens, nil
 }
 
-//func (e *ENS) Resolve(name string) (ethgo.Address, error) {
-//	resolver := ens.NewENSResolver(e.config.Resolver, e.config.Client)
-//	return resolver.Resolve(name)
-//}
+// ResolverAddr returns the address of the resolver of the name in the registry
+func (e *ENS) ResolverAddr(name string) (ethgo.Address, error) {
+	name, err := Normalize(name)
+	if err != nil {
+		return ethgo.Address{}, err
+	}
+	return e.resolverAddr(NameHash(name), name)
+}
+
+func (e *ENS) resolverAddr(node ethgo.Hash, name string) (ethgo.Address, error) {
+	res, err := e.registry.Call("resolver", ethgo.Latest, node)
+	if err != nil {
+		return ethgo.Address{}, err
+	}
+	addr := res["0"].(ethgo.Address)
+	if addr == ethgo.ZeroAddress {
+		return ethgo.Address{}, fmt.Errorf("no resolver found for %s", name)
+	}
+	return addr, nil
+}
+
+// resolver returns the resolver contract and the node of the name
+func (e *ENS) resolver(name string) (*contract.Contract, ethgo.Hash, error) {
+	name, err := Normalize(name)
+	if err != nil {
+		return nil, ethgo.Hash{}, err
+	}
+	node := NameHash(name)
+
+	addr, err := e.resolverAddr(node, name)
+	if err != nil {
+		return nil, ethgo.Hash{}, err
+	}
+	return contract.NewContract(addr, resolverABI, e.config.Client), node, nil
+}
+
+// Resolve resolves an ENS name to its registered address
+func (e *ENS) Resolve(name string) (ethgo.Address, error) {
+	resolver, node, err := e.resolver(name)
+	if err != nil {
+		return ethgo.Address{}, err
+	}
+	res, err := resolver.Call("addr", ethgo.Latest, node)
+	if err != nil {
+		return ethgo.Address{}, err
+	}
+	return res["0"].(ethgo.Address), nil
+}
+
+// ReverseResolve returns the primary name of the address. The name is only
+// returned if it resolves back to the same address.
+func (e *ENS) ReverseResolve(addr ethgo.Address) (string, error) {
+	reverseName := strings.ToLower(addr.String()[2:]) + ".addr.reverse"
+
+	resolver, node, err := e.resolver(reverseName)
+	if err != nil {
+		return "", err
+	}
+	res, err := resolver.Call("name", ethgo.Latest, node)
+	if err != nil {
+		return "", err
+	}
+	name := res["0"].(string)
+	if name == "" {
+		return "", fmt.Errorf("no name found for %s", addr)
+	}
+
+	// forward check
+	forwardAddr, err := e.Resolve(name)
+	if err != nil {
+		return "", err
+	}
+	if forwardAddr != addr {
+		return "", fmt.Errorf("name %s does not resolve to %s", name, addr)
+	}
+	return name, nil
+}
+
+// Text returns the text record of the name for the key (i.e. 'url' or 'com.twitter')
+func (e *ENS) Text(name, key string) (string, error) {
+	resolver, node, err := e.resolver(name)
+	if err != nil {
+		return "", err
+	}
+	res, err := resolver.Call("text", ethgo.Latest, node, key)
+	if err != nil {
+		return "", err
+	}
+	return res["0"].(string), nil
+}
+
+// ContentHash returns the content hash record of the name (EIP-1577)
+func (e *ENS) ContentHash(name string) ([]byte, error) {
+	resolver, node, err := e.resolver(name)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resolver.Call("contenthash", ethgo.Latest, node)
+	if err != nil {
+		return nil, err
+	}
+	return res["0"].([]byte), nil
+}
+
+// CoinAddress returns the address record of the name for a coin type of
+// SLIP-44 in its binary format (ENSIP-9)
+func (e *ENS) CoinAddress(name string, coinType uint64) ([]byte, error) {
+	resolver, node, err := e.resolver(name)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resolver.Call("addr0", ethgo.Latest, node, new(big.Int).SetUint64(coinType))
+	if err != nil {
+		return nil, err
+	}
+	return res["0"].([]byte), nil
+}
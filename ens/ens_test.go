@@ -1,9 +1,16 @@
 package ens
 
 import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/abi"
+	"github.com/git-yongge/ethgo/jsonrpc"
 	"github.com/git-yongge/ethgo/testutil"
 	"github.com/stretchr/testify/assert"
 )
@@ -16,3 +23,169 @@ func TestENS_Resolve(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, ethgo.HexToAddress("0xb8c2C29ee19D8307cb7255e1Cd9CbDE883A267d5"), addr)
 }
+
+// mockENS is an ENS registry and a resolver with records for the names
+type mockENS struct {
+	resolver ethgo.Address
+
+	// resolvers are the names with a resolver in the registry
+	resolvers map[ethgo.Hash]bool
+
+	addrs     map[ethgo.Hash]ethgo.Address
+	names     map[ethgo.Hash]string
+	texts     map[ethgo.Hash]map[string]string
+	content   map[ethgo.Hash][]byte
+	coinAddrs map[ethgo.Hash]map[uint64][]byte
+}
+
+func (m *mockENS) call(t *testing.T, params []json.RawMessage) (interface{}, error) {
+	var msg struct {
+		To   ethgo.Address
+		Data string
+	}
+	assert.NoError(t, json.Unmarshal(params[0], &msg))
+
+	data, err := hex.DecodeString(msg.Data[2:])
+	assert.NoError(t, err)
+
+	contractABI := resolverABI
+	if msg.To == defaultEnsAddr {
+		contractABI = registryABI
+	} else {
+		assert.Equal(t, m.resolver, msg.To)
+	}
+
+	var method *abi.Method
+	for _, mm := range contractABI.Methods {
+		if bytes.Equal(mm.ID(), data[:4]) {
+			method = mm
+		}
+	}
+	assert.NotNil(t, method)
+
+	args, err := method.Inputs.Decode(data[4:])
+	assert.NoError(t, err)
+	input := args.(map[string]interface{})
+
+	node := ethgo.Hash(input["node"].([32]byte))
+
+	var res interface{}
+	switch method.Name {
+	case "resolver":
+		res = ethgo.Address{}
+		if m.resolvers[node] {
+			res = m.resolver
+		}
+	case "addr":
+		if coinType, ok := input["coinType"]; ok {
+			res = m.coinAddrs[node][coinType.(*big.Int).Uint64()]
+		} else {
+			res = m.addrs[node]
+		}
+	case "name":
+		res = m.names[node]
+	case "text":
+		res = m.texts[node][input["key"].(string)]
+	case "contenthash":
+		res = m.content[node]
+	}
+	if res == nil {
+		res = []byte{}
+	}
+
+	out, err := method.Outputs.Encode([]interface{}{res})
+	assert.NoError(t, err)
+	return "0x" + hex.EncodeToString(out), nil
+}
+
+func reverseNode(addr ethgo.Address) ethgo.Hash {
+	return NameHash(strings.ToLower(addr.String()[2:]) + ".addr.reverse")
+}
+
+func TestENS_Records(t *testing.T) {
+	owner := ethgo.Address{0x11}
+	other := ethgo.Address{0x22}
+
+	nick := NameHash("nick.eth")
+
+	m := &mockENS{
+		resolver: ethgo.Address{0xaa},
+		resolvers: map[ethgo.Hash]bool{
+			nick:               true,
+			reverseNode(owner): true,
+			reverseNode(other): true,
+		},
+		addrs: map[ethgo.Hash]ethgo.Address{
+			nick: owner,
+		},
+		names: map[ethgo.Hash]string{
+			reverseNode(owner): "nick.eth",
+			// other claims a name that does not resolve to it
+			reverseNode(other): "nick.eth",
+		},
+		texts: map[ethgo.Hash]map[string]string{
+			nick: {"url": "https://ens.domains"},
+		},
+		content: map[ethgo.Hash][]byte{
+			nick: {0xe3, 0x01, 0x01, 0x70},
+		},
+		coinAddrs: map[ethgo.Hash]map[uint64][]byte{
+			nick: {
+				CoinTypeETH: owner.Bytes(),
+				0:           {0x76, 0xa9, 0x14},
+			},
+		},
+	}
+
+	s := testutil.NewMockServer()
+	defer s.Close()
+
+	s.HandleResult("eth_chainId", "0x1")
+	s.Handle("eth_call", func(params []json.RawMessage) (interface{}, error) {
+		return m.call(t, params)
+	})
+
+	client, _ := jsonrpc.NewClient(s.HTTPAddr())
+
+	ens, err := NewENS(WithClient(client))
+	assert.NoError(t, err)
+
+	// the name is normalized
+	addr, err := ens.Resolve("Nick.ETH")
+	assert.NoError(t, err)
+	assert.Equal(t, owner, addr)
+
+	resolver, err := ens.ResolverAddr("nick.eth")
+	assert.NoError(t, err)
+	assert.Equal(t, m.resolver, resolver)
+
+	_, err = ens.Resolve("unknown.eth")
+	assert.Error(t, err)
+
+	_, err = ens.Resolve("nick..eth")
+	assert.Error(t, err)
+
+	name, err := ens.ReverseResolve(owner)
+	assert.NoError(t, err)
+	assert.Equal(t, "nick.eth", name)
+
+	// the forward check fails
+	_, err = ens.ReverseResolve(other)
+	assert.Error(t, err)
+
+	text, err := ens.Text("nick.eth", "url")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://ens.domains", text)
+
+	content, err := ens.ContentHash("nick.eth")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xe3, 0x01, 0x01, 0x70}, content)
+
+	coinAddr, err := ens.CoinAddress("nick.eth", CoinTypeETH)
+	assert.NoError(t, err)
+	assert.Equal(t, owner.Bytes(), coinAddr)
+
+	coinAddr, err = ens.CoinAddress("nick.eth", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x76, 0xa9, 0x14}, coinAddr)
+}
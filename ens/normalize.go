@@ -0,0 +1,117 @@
+package ens
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/git-yongge/ethgo"
+	"golang.org/x/net/idna"
+)
+
+// mapProfile maps the labels with the UTS-46 mapping for lookups. The
+// joiners are validated separately to allow the emoji sequences.
+var mapProfile = idna.New(
+	idna.MapForLookup(),
+	idna.Transitional(false),
+	idna.StrictDomainName(false),
+	idna.CheckHyphens(false),
+	idna.CheckJoiners(false),
+)
+
+// Normalize normalizes an ENS name following ENSIP-15. The labels are case folded
+// and mapped with UTS-46 in NFC form and the emoji presentation selectors are
+// removed. It fails for empty labels, punycode labels, hyphens in the third and
+// fourth positions of ascii labels, underscores that are not leading, whitespaces,
+// punctuation and zero width joiners outside of emoji sequences. The confusable
+// checks of ENSIP-15 are not performed.
+func Normalize(name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		normLabel, err := normalizeLabel(label)
+		if err != nil {
+			return "", fmt.Errorf("invalid label '%s': %v", label, err)
+		}
+		labels[i] = normLabel
+	}
+	return strings.Join(labels, "."), nil
+}
+
+func normalizeLabel(label string) (string, error) {
+	if label == "" {
+		return "", fmt.Errorf("empty label")
+	}
+
+	// emoji are normalized without the presentation selector
+	label = strings.ReplaceAll(label, "\ufe0f", "")
+
+	if strings.HasPrefix(strings.ToLower(label), "xn--") {
+		return "", fmt.Errorf("punycode is not allowed")
+	}
+	label, err := mapProfile.ToUnicode(label)
+	if err != nil {
+		return "", err
+	}
+
+	runes := []rune(label)
+	if len(runes) >= 4 && runes[2] == '-' && runes[3] == '-' && isASCII(label) {
+		return "", fmt.Errorf("hyphens in the third and fourth positions")
+	}
+
+	leading := true
+	for i, r := range runes {
+		if r == '_' {
+			if !leading {
+				return "", fmt.Errorf("underscore is only allowed at the start")
+			}
+			continue
+		}
+		leading = false
+
+		switch {
+		case r == '-':
+		case r == '\u200d':
+			// zero width joiner only inside emoji sequences
+			if i == 0 || i == len(runes)-1 || !isEmoji(runes[i-1]) || !isEmoji(runes[i+1]) {
+				return "", fmt.Errorf("zero width joiner outside of an emoji sequence")
+			}
+		case unicode.IsLetter(r), unicode.IsDigit(r), unicode.IsMark(r), isEmoji(r):
+		default:
+			return "", fmt.Errorf("character %U not allowed", r)
+		}
+	}
+	return label, nil
+}
+
+func isEmoji(r rune) bool {
+	return unicode.Is(unicode.So, r) || (r >= 0x1f3fb && r <= 0x1f3ff)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// NameHash returns the node of a name (EIP-137). The name
+// must be normalized.
+func NameHash(name string) ethgo.Hash {
+	node := ethgo.Hash{}
+	if name == "" {
+		return node
+	}
+
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		labelHash := ethgo.Keccak256([]byte(labels[i]))
+		node = ethgo.BytesToHash(ethgo.Keccak256(node[:], labelHash))
+	}
+	return node
+}
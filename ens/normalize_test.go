@@ -0,0 +1,60 @@
+package ens
+
+import (
+	"testing"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameHash(t *testing.T) {
+	cases := []struct {
+		name string
+		node string
+	}{
+		{"", "0x0000000000000000000000000000000000000000000000000000000000000000"},
+		{"eth", "0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae"},
+		{"foo.eth", "0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, ethgo.HexToHash(c.node), NameHash(c.name), c.name)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		name       string
+		normalized string
+	}{
+		{"nick.eth", "nick.eth"},
+		{"Nick.ETH", "nick.eth"},
+		{"ÖBB.eth", "öbb.eth"},
+		{"ｆｏｏ.eth", "foo.eth"},
+		{"_ab.eth", "_ab.eth"},
+		{"a-b.eth", "a-b.eth"},
+		{"🚀.eth", "🚀.eth"},
+		// the emoji presentation selector is removed
+		{"❤️.eth", "❤.eth"},
+		{"👩🏽‍⚕️.eth", "👩🏽‍⚕.eth"},
+	}
+	for _, c := range cases {
+		normalized, err := Normalize(c.name)
+		assert.NoError(t, err, c.name)
+		assert.Equal(t, c.normalized, normalized, c.name)
+	}
+
+	invalid := []string{
+		"a..eth",
+		".eth",
+		"xn--ls8h.eth",
+		"ab--c.eth",
+		"a_b.eth",
+		"foo bar.eth",
+		"foo!.eth",
+		"a‍b.eth",
+	}
+	for _, name := range invalid {
+		_, err := Normalize(name)
+		assert.Error(t, err, name)
+	}
+}
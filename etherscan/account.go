@@ -0,0 +1,379 @@
+package etherscan
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/git-yongge/ethgo"
+)
+
+// ListOptions are the block range, pagination and sort options of the list queries
+type ListOptions struct {
+	StartBlock uint64
+
+	// EndBlock is the last block of the range, the latest block if zero
+	EndBlock uint64
+
+	// Page and Offset paginate the results (Offset items per page)
+	Page   int
+	Offset int
+
+	// Desc sorts the results by block number in descending order
+	Desc bool
+}
+
+func (o *ListOptions) params(params map[string]string) {
+	if o == nil {
+		return
+	}
+	params["startblock"] = strconv.FormatUint(o.StartBlock, 10)
+	if o.EndBlock != 0 {
+		params["endblock"] = strconv.FormatUint(o.EndBlock, 10)
+	}
+	if o.Page != 0 {
+		params["page"] = strconv.Itoa(o.Page)
+	}
+	if o.Offset != 0 {
+		params["offset"] = strconv.Itoa(o.Offset)
+	}
+	if o.Desc {
+		params["sort"] = "desc"
+	} else {
+		params["sort"] = "asc"
+	}
+}
+
+// Transaction is a transaction of an account (txlist)
+type Transaction struct {
+	BlockNumber       uint64
+	Timestamp         uint64
+	Hash              ethgo.Hash
+	BlockHash         ethgo.Hash
+	TransactionIndex  uint64
+	Nonce             uint64
+	From              ethgo.Address
+	To                *ethgo.Address
+	Value             *big.Int
+	Gas               uint64
+	GasPrice          *big.Int
+	GasUsed           uint64
+	CumulativeGasUsed uint64
+	Input             []byte
+	IsError           bool
+
+	// ContractAddress is the address of the contract created by the transaction
+	ContractAddress *ethgo.Address
+
+	Confirmations uint64
+}
+
+// InternalTransaction is an internal transaction of an account (txlistinternal)
+type InternalTransaction struct {
+	BlockNumber     uint64
+	Timestamp       uint64
+	Hash            ethgo.Hash
+	From            ethgo.Address
+	To              *ethgo.Address
+	Value           *big.Int
+	ContractAddress *ethgo.Address
+	Input           []byte
+	Type            string
+	Gas             uint64
+	GasUsed         uint64
+	TraceID         string
+	IsError         bool
+	ErrCode         string
+}
+
+// TokenStandard is the standard of the tokens of a transfer
+type TokenStandard int
+
+const (
+	// ERC20 are the fungible token transfers (tokentx)
+	ERC20 TokenStandard = iota
+
+	// ERC721 are the non fungible token transfers (tokennfttx)
+	ERC721
+
+	// ERC1155 are the multi token transfers (token1155tx)
+	ERC1155
+)
+
+func (t TokenStandard) action() string {
+	switch t {
+	case ERC721:
+		return "tokennfttx"
+	case ERC1155:
+		return "token1155tx"
+	default:
+		return "tokentx"
+	}
+}
+
+// TokenTransfer is a token transfer of an account
+type TokenTransfer struct {
+	BlockNumber      uint64
+	Timestamp        uint64
+	Hash             ethgo.Hash
+	BlockHash        ethgo.Hash
+	TransactionIndex uint64
+	Nonce            uint64
+	From             ethgo.Address
+	To               ethgo.Address
+	ContractAddress  ethgo.Address
+	TokenName        string
+	TokenSymbol      string
+	TokenDecimal     uint64
+
+	// Value is the amount of the ERC20 and ERC1155 transfers
+	Value *big.Int
+
+	// TokenID is the id of the ERC721 and ERC1155 tokens
+	TokenID *big.Int
+
+	Gas               uint64
+	GasPrice          *big.Int
+	GasUsed           uint64
+	CumulativeGasUsed uint64
+	Confirmations     uint64
+}
+
+// GetTransactions returns the normal transactions of an account
+func (e *Etherscan) GetTransactions(addr ethgo.Address, opts *ListOptions) ([]*Transaction, error) {
+	params := map[string]string{
+		"address": addr.String(),
+	}
+	opts.params(params)
+
+	var items []map[string]string
+	if err := e.Query("account", "txlist", &items, params); err != nil {
+		return nil, err
+	}
+
+	res := make([]*Transaction, len(items))
+	for i, item := range items {
+		p := &itemParser{item: item}
+		res[i] = &Transaction{
+			BlockNumber:       p.uint64("blockNumber"),
+			Timestamp:         p.uint64("timeStamp"),
+			Hash:              p.hash("hash"),
+			BlockHash:         p.hash("blockHash"),
+			TransactionIndex:  p.uint64("transactionIndex"),
+			Nonce:             p.uint64("nonce"),
+			From:              p.addr("from"),
+			To:                p.optAddr("to"),
+			Value:             p.bigInt("value"),
+			Gas:               p.uint64("gas"),
+			GasPrice:          p.bigInt("gasPrice"),
+			GasUsed:           p.uint64("gasUsed"),
+			CumulativeGasUsed: p.uint64("cumulativeGasUsed"),
+			Input:             p.bytes("input"),
+			IsError:           p.bool("isError"),
+			ContractAddress:   p.optAddr("contractAddress"),
+			Confirmations:     p.uint64("confirmations"),
+		}
+		if p.err != nil {
+			return nil, p.err
+		}
+	}
+	return res, nil
+}
+
+// GetInternalTransactions returns the internal transactions of an account
+func (e *Etherscan) GetInternalTransactions(addr ethgo.Address, opts *ListOptions) ([]*InternalTransaction, error) {
+	return e.getInternalTransactions(map[string]string{
+		"address": addr.String(),
+	}, opts)
+}
+
+// GetInternalTransactionsByHash returns the internal transactions of a transaction
+func (e *Etherscan) GetInternalTransactionsByHash(hash ethgo.Hash) ([]*InternalTransaction, error) {
+	return e.getInternalTransactions(map[string]string{
+		"txhash": hash.String(),
+	}, nil)
+}
+
+func (e *Etherscan) getInternalTransactions(params map[string]string, opts *ListOptions) ([]*InternalTransaction, error) {
+	opts.params(params)
+
+	var items []map[string]string
+	if err := e.Query("account", "txlistinternal", &items, params); err != nil {
+		return nil, err
+	}
+
+	res := make([]*InternalTransaction, len(items))
+	for i, item := range items {
+		p := &itemParser{item: item}
+		res[i] = &InternalTransaction{
+			BlockNumber:     p.uint64("blockNumber"),
+			Timestamp:       p.uint64("timeStamp"),
+			Hash:            p.hash("hash"),
+			From:            p.addr("from"),
+			To:              p.optAddr("to"),
+			Value:           p.bigInt("value"),
+			ContractAddress: p.optAddr("contractAddress"),
+			Input:           p.bytes("input"),
+			Type:            item["type"],
+			Gas:             p.uint64("gas"),
+			GasUsed:         p.uint64("gasUsed"),
+			TraceID:         item["traceId"],
+			IsError:         p.bool("isError"),
+			ErrCode:         item["errCode"],
+		}
+		if p.err != nil {
+			return nil, p.err
+		}
+	}
+	return res, nil
+}
+
+// GetTokenTransfers returns the token transfers of an account. If the token is
+// set, only the transfers of that token contract are returned. If the account
+// is not set, all the transfers of the token are returned.
+func (e *Etherscan) GetTokenTransfers(standard TokenStandard, addr, token *ethgo.Address, opts *ListOptions) ([]*TokenTransfer, error) {
+	if addr == nil && token == nil {
+		return nil, fmt.Errorf("either the account or the token address is required")
+	}
+
+	params := map[string]string{}
+	if addr != nil {
+		params["address"] = addr.String()
+	}
+	if token != nil {
+		params["contractaddress"] = token.String()
+	}
+	opts.params(params)
+
+	var items []map[string]string
+	if err := e.Query("account", standard.action(), &items, params); err != nil {
+		return nil, err
+	}
+
+	res := make([]*TokenTransfer, len(items))
+	for i, item := range items {
+		p := &itemParser{item: item}
+		transfer := &TokenTransfer{
+			BlockNumber:       p.uint64("blockNumber"),
+			Timestamp:         p.uint64("timeStamp"),
+			Hash:              p.hash("hash"),
+			BlockHash:         p.hash("blockHash"),
+			TransactionIndex:  p.uint64("transactionIndex"),
+			Nonce:             p.uint64("nonce"),
+			From:              p.addr("from"),
+			To:                p.addr("to"),
+			ContractAddress:   p.addr("contractAddress"),
+			TokenName:         item["tokenName"],
+			TokenSymbol:       item["tokenSymbol"],
+			TokenDecimal:      p.uint64("tokenDecimal"),
+			Gas:               p.uint64("gas"),
+			GasPrice:          p.bigInt("gasPrice"),
+			GasUsed:           p.uint64("gasUsed"),
+			CumulativeGasUsed: p.uint64("cumulativeGasUsed"),
+			Confirmations:     p.uint64("confirmations"),
+		}
+		if _, ok := item["tokenID"]; ok {
+			transfer.TokenID = p.bigInt("tokenID")
+		}
+		if _, ok := item["tokenValue"]; ok {
+			// ERC1155 transfers
+			transfer.Value = p.bigInt("tokenValue")
+		} else if _, ok := item["value"]; ok {
+			transfer.Value = p.bigInt("value")
+		}
+		if p.err != nil {
+			return nil, p.err
+		}
+		res[i] = transfer
+	}
+	return res, nil
+}
+
+// itemParser parses the string values of the items of the Etherscan lists.
+// The numbers are either decimal or hex strings. The first error is kept.
+type itemParser struct {
+	item map[string]string
+	err  error
+}
+
+func (p *itemParser) setErr(key string, err error) {
+	if p.err == nil {
+		p.err = fmt.Errorf("failed to parse '%s': %v", key, err)
+	}
+}
+
+func (p *itemParser) uint64(key string) uint64 {
+	str := p.item[key]
+	if str == "" || str == "0x" {
+		return 0
+	}
+	num, err := parseUint64orHex(str)
+	if err != nil {
+		p.setErr(key, err)
+	}
+	return num
+}
+
+func (p *itemParser) bigInt(key string) *big.Int {
+	str := p.item[key]
+	if str == "" || str == "0x" {
+		return big.NewInt(0)
+	}
+
+	num, ok := new(big.Int), false
+	if strings.HasPrefix(str, "0x") {
+		num, ok = num.SetString(str[2:], 16)
+	} else {
+		num, ok = num.SetString(str, 10)
+	}
+	if !ok {
+		p.setErr(key, fmt.Errorf("invalid number %s", str))
+		return nil
+	}
+	return num
+}
+
+func (p *itemParser) hash(key string) (h ethgo.Hash) {
+	if str := p.item[key]; str != "" {
+		if err := h.UnmarshalText([]byte(str)); err != nil {
+			p.setErr(key, err)
+		}
+	}
+	return
+}
+
+func (p *itemParser) addr(key string) (a ethgo.Address) {
+	if str := p.item[key]; str != "" {
+		if err := a.UnmarshalText([]byte(str)); err != nil {
+			p.setErr(key, err)
+		}
+	}
+	return
+}
+
+func (p *itemParser) optAddr(key string) *ethgo.Address {
+	if p.item[key] == "" {
+		return nil
+	}
+	addr := p.addr(key)
+	return &addr
+}
+
+func (p *itemParser) bytes(key string) []byte {
+	str := p.item[key]
+	if !strings.HasPrefix(str, "0x") {
+		// empty or deprecated fields
+		return nil
+	}
+	buf, err := hex.DecodeString(str[2:])
+	if err != nil {
+		p.setErr(key, err)
+	}
+	return buf
+}
+
+func (p *itemParser) bool(key string) bool {
+	return p.item[key] == "1"
+}
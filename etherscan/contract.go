@@ -0,0 +1,193 @@
+package etherscan
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/abi"
+	"github.com/valyala/fasthttp"
+)
+
+type ContractCode struct {
+	SourceCode           string
+	ABI                  string
+	ContractName         string
+	CompilerVersion      string
+	OptimizationUsed     string
+	Runs                 string
+	ConstructorArguments string
+	EVMVersion           string
+	Library              string
+	LicenseType          string
+	Proxy                string
+	Implementation       string
+	SwarmSource          string
+}
+
+func (e *Etherscan) GetContractCode(addr ethgo.Address) (*ContractCode, error) {
+	var out []*ContractCode
+	err := e.Query("contract", "getsourcecode", &out, map[string]string{
+		"address": addr.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(out) != 1 {
+		return nil, fmt.Errorf("incorrect values")
+	}
+	return out[0], nil
+}
+
+// GetABI returns the abi of a verified contract
+func (e *Etherscan) GetABI(addr ethgo.Address) (*abi.ABI, error) {
+	var out string
+	err := e.Query("contract", "getabi", &out, map[string]string{
+		"address": addr.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return abi.NewABI(out)
+}
+
+// CodeFormat is the format of the source code of a verification
+type CodeFormat string
+
+const (
+	// SingleFile is the source code of a flattened contract
+	SingleFile CodeFormat = "solidity-single-file"
+
+	// StandardJSONInput is the standard json input of the compiler
+	StandardJSONInput CodeFormat = "solidity-standard-json-input"
+)
+
+// VerifyRequest is a request to verify the source code of a contract
+type VerifyRequest struct {
+	Address    ethgo.Address
+	SourceCode string
+	CodeFormat CodeFormat
+
+	// ContractName is the name of the contract. For the standard json
+	// input it includes the file (i.e. contracts/Token.sol:Token)
+	ContractName string
+
+	// CompilerVersion is the full version of the compiler (i.e. v0.8.19+commit.7dd6d404)
+	CompilerVersion string
+
+	OptimizationUsed bool
+	Runs             uint64
+
+	// ConstructorArguments are the abi encoded arguments of the constructor
+	ConstructorArguments []byte
+
+	EVMVersion string
+
+	// LicenseType is the code of the license in Etherscan (1 is no license)
+	LicenseType int
+}
+
+// VerifySourceCode submits the source code of a contract for verification and
+// returns the guid of the submission to check its status
+func (e *Etherscan) VerifySourceCode(req *VerifyRequest) (string, error) {
+	codeFormat := req.CodeFormat
+	if codeFormat == "" {
+		codeFormat = SingleFile
+	}
+	params := map[string]string{
+		"contractaddress": req.Address.String(),
+		"sourceCode":      req.SourceCode,
+		"codeformat":      string(codeFormat),
+		"contractname":    req.ContractName,
+		"compilerversion": req.CompilerVersion,
+		"runs":            strconv.FormatUint(req.Runs, 10),
+		// the typo is part of the api
+		"constructorArguements": hex.EncodeToString(req.ConstructorArguments),
+		"optimizationUsed":      "0",
+	}
+	if req.OptimizationUsed {
+		params["optimizationUsed"] = "1"
+	}
+	if req.EVMVersion != "" {
+		params["evmversion"] = req.EVMVersion
+	}
+	if req.LicenseType != 0 {
+		params["licenseType"] = strconv.Itoa(req.LicenseType)
+	}
+
+	var guid string
+	if err := e.Post("contract", "verifysourcecode", &guid, params); err != nil {
+		return "", err
+	}
+	return guid, nil
+}
+
+// VerifyState is the state of a verification
+type VerifyState int
+
+const (
+	VerifyPending VerifyState = iota
+	VerifyPass
+	VerifyFail
+)
+
+// VerifyStatus is the status of a verification
+type VerifyStatus struct {
+	State VerifyState
+
+	// Message is the message of Etherscan (i.e. 'Fail - Unable to verify')
+	Message string
+}
+
+// CheckVerifyStatus returns the status of the verification with the guid
+func (e *Etherscan) CheckVerifyStatus(guid string) (*VerifyStatus, error) {
+	resp, err := e.query(fasthttp.MethodGet, "contract", "checkverifystatus", map[string]string{
+		"guid": guid,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the pending and failed verifications are not successful responses
+	msg, ok := resp.resultStr()
+	if !ok {
+		return nil, fmt.Errorf("unexpected verify status response")
+	}
+
+	status := &VerifyStatus{Message: msg}
+	switch {
+	case resp.Status == "1" || strings.Contains(msg, "Already Verified"):
+		status.State = VerifyPass
+	case strings.Contains(msg, "Pending"):
+		status.State = VerifyPending
+	case strings.HasPrefix(msg, "Fail"):
+		status.State = VerifyFail
+	default:
+		return nil, fmt.Errorf("%s: %s", resp.Message, msg)
+	}
+	return status, nil
+}
+
+// WaitForVerification polls the status of the verification with the guid
+// every interval until it is not pending
+func (e *Etherscan) WaitForVerification(ctx context.Context, guid string, interval time.Duration) (*VerifyStatus, error) {
+	for {
+		status, err := e.CheckVerifyStatus(guid)
+		if err != nil {
+			return nil, err
+		}
+		if status.State != VerifyPending {
+			return status, nil
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
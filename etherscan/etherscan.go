@@ -3,105 +3,254 @@ package etherscan
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/git-yongge/ethgo"
 	"github.com/git-yongge/ethgo/jsonrpc/codec"
 	"github.com/valyala/fasthttp"
 )
 
+// rateLimitMsg is the error returned by Etherscan when the
+// requests per second of the api key are exceeded
+const rateLimitMsg = "Max rate limit reached"
+
+// Config is the Etherscan client configuration
+type Config struct {
+	// RateLimit is the maximum number of requests per second (5 in the free tier).
+	// Zero disables the limiter.
+	RateLimit float64
+
+	// MaxRetries is the number of times a request is retried if it is rate limited
+	MaxRetries int
+
+	// RetryDelay is the time to wait before retrying a rate limited request
+	RetryDelay time.Duration
+}
+
+// DefaultConfig returns the default Etherscan client configuration
+func DefaultConfig() *Config {
+	return &Config{
+		RateLimit:  5,
+		MaxRetries: 5,
+		RetryDelay: time.Second,
+	}
+}
+
+// ConfigOption is an option to configure the Etherscan client
+type ConfigOption func(*Config)
+
+// WithRateLimit sets the maximum number of requests per second
+func WithRateLimit(rps float64) ConfigOption {
+	return func(c *Config) {
+		c.RateLimit = rps
+	}
+}
+
+// WithRetries sets the number of retries of the rate limited
+// requests and the delay between them
+func WithRetries(maxRetries int, delay time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.MaxRetries = maxRetries
+		c.RetryDelay = delay
+	}
+}
+
 // Etherscan is a provider using the Etherscan api
 type Etherscan struct {
-	client fasthttp.Client
-	url    string
-	apiKey string
+	client  fasthttp.Client
+	url     string
+	apiKey  string
+	config  *Config
+	limiter *limiter
+}
+
+// networkURLs are the api endpoints of the Etherscan explorers by chain id
+var networkURLs = map[uint64]string{
+	1:        "https://api.etherscan.io",
+	3:        "https://api-ropsten.etherscan.io",
+	4:        "https://api-rinkeby.etherscan.io",
+	5:        "https://api-goerli.etherscan.io",
+	10:       "https://api-optimistic.etherscan.io",
+	56:       "https://api.bscscan.com",
+	137:      "https://api.polygonscan.com",
+	8453:     "https://api.basescan.org",
+	42161:    "https://api.arbiscan.io",
+	11155111: "https://api-sepolia.etherscan.io",
 }
 
 // NewEtherscanFromNetwork creates a new client from the network id
-func NewEtherscanFromNetwork(n ethgo.Network, apiKey string) (*Etherscan, error) {
-	var url string
-	switch n {
-	case ethgo.Mainnet:
-		url = "https://api.etherscan.io"
+func NewEtherscanFromNetwork(n ethgo.Network, apiKey string, opts ...ConfigOption) (*Etherscan, error) {
+	url, ok := networkURLs[uint64(n)]
+	if !ok {
+		return nil, fmt.Errorf("unknwon network id %d", n)
+	}
+	return NewEtherscan(url, apiKey, opts...), nil
+}
 
-	case ethgo.Ropsten:
-		url = "https://ropsten.etherscan.io"
+// NewEtherscan creates a new Etherscan service from a url. The url is the base url
+// of the explorer (i.e. https://api.etherscan.io) or its api endpoint
+// (i.e. https://explorer.com/api) for other Etherscan compatible explorers.
+func NewEtherscan(url, apiKey string, opts ...ConfigOption) *Etherscan {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
 
-	case ethgo.Rinkeby:
-		url = "https://rinkeby.etherscan.io"
+	url = strings.TrimSuffix(url, "/")
+	if !strings.HasSuffix(url, "/api") {
+		url += "/api"
+	}
+	return &Etherscan{
+		url:     url,
+		apiKey:  apiKey,
+		config:  config,
+		limiter: newLimiter(config.RateLimit),
+	}
+}
 
-	case ethgo.Goerli:
-		url = "https://goerli.etherscan.io"
+// limiter spaces the requests to stay under a number of requests per second
+type limiter struct {
+	lock     sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
 
-	default:
-		return nil, fmt.Errorf("unknwon network id %d", n)
+func newLimiter(rps float64) *limiter {
+	l := &limiter{}
+	if rps > 0 {
+		l.interval = time.Duration(float64(time.Second) / rps)
 	}
-	return NewEtherscan(url, apiKey), nil
+	return l
 }
 
-// NewEtherscan creates a new Etherscan service from a url
-func NewEtherscan(url, apiKey string) *Etherscan {
-	return &Etherscan{url: url}
+// wait blocks until the next request can be sent
+func (l *limiter) wait() {
+	if l.interval == 0 {
+		return
+	}
+
+	l.lock.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.lock.Unlock()
+
+	time.Sleep(delay)
 }
 
-type proxyResponse struct {
+// response is either an Etherscan api response or
+// a json-rpc response for the proxy module
+type response struct {
 	Status  string
 	Message string
 	Result  json.RawMessage
+	Error   *codec.ErrorObject
+}
+
+// resultStr returns the result if it is a string (i.e. the error messages)
+func (r *response) resultStr() (string, bool) {
+	var str string
+	if err := json.Unmarshal(r.Result, &str); err != nil {
+		return "", false
+	}
+	return str, true
+}
+
+func (r *response) isRateLimited() bool {
+	str, ok := r.resultStr()
+	return r.Status == "0" && ok && strings.Contains(str, rateLimitMsg)
+}
+
+func (r *response) decode(out interface{}) error {
+	if r.Error != nil {
+		return r.Error
+	}
+	if r.Status == "0" {
+		// the errors have a message in the result, the empty
+		// lists (i.e. 'No transactions found') are not errors
+		if str, ok := r.resultStr(); ok {
+			return fmt.Errorf("%s: %s", r.Message, str)
+		}
+	}
+	return json.Unmarshal(r.Result, out)
 }
 
 // Query sends a query to Etherscan
 func (e *Etherscan) Query(module, action string, out interface{}, params map[string]string) error {
-	url := fmt.Sprintf("%s/api?module=%s&action=%s", e.url, module, action)
-	if len(params) != 0 {
-		res := []string{}
-		for k, v := range params {
-			res = append(res, k+"="+v)
-		}
-		url += "&" + strings.Join(res, "&")
+	resp, err := e.query(fasthttp.MethodGet, module, action, params)
+	if err != nil {
+		return err
+	}
+	return resp.decode(out)
+}
+
+// Post sends a query to Etherscan with the params encoded as a form in the body
+func (e *Etherscan) Post(module, action string, out interface{}, params map[string]string) error {
+	resp, err := e.query(fasthttp.MethodPost, module, action, params)
+	if err != nil {
+		return err
+	}
+	return resp.decode(out)
+}
+
+// query sends the request and retries it while it is rate limited
+func (e *Etherscan) query(method, module, action string, params map[string]string) (*response, error) {
+	args := url.Values{}
+	args.Set("module", module)
+	args.Set("action", action)
+	for k, v := range params {
+		args.Set(k, v)
 	}
 	if e.apiKey != "" {
-		url = url + "&apikey=" + e.apiKey
+		args.Set("apikey", e.apiKey)
 	}
 
+	for i := 0; ; i++ {
+		resp, err := e.do(method, args)
+		if err != nil {
+			return nil, err
+		}
+		if !resp.isRateLimited() || i >= e.config.MaxRetries {
+			return resp, nil
+		}
+		time.Sleep(e.config.RetryDelay)
+	}
+}
+
+func (e *Etherscan) do(method string, args url.Values) (*response, error) {
+	e.limiter.wait()
+
 	req := fasthttp.AcquireRequest()
 	res := fasthttp.AcquireResponse()
 
 	defer fasthttp.ReleaseRequest(req)
 	defer fasthttp.ReleaseResponse(res)
 
-	req.SetRequestURI(url)
-	req.Header.SetMethod("GET")
-
-	if err := e.client.Do(req, res); err != nil {
-		return err
+	req.Header.SetMethod(method)
+	if method == fasthttp.MethodGet {
+		req.SetRequestURI(e.url + "?" + args.Encode())
+	} else {
+		req.SetRequestURI(e.url)
+		req.Header.SetContentType("application/x-www-form-urlencoded")
+		req.SetBodyString(args.Encode())
 	}
 
-	// Decode json-rpc response
-	var result json.RawMessage
-
-	if module == "proxy" {
-		var response codec.Response
-		if err := json.Unmarshal(res.Body(), &response); err != nil {
-			return err
-		}
-		if response.Error != nil {
-			return response.Error
-		}
-		result = response.Result
-	} else {
-		var response proxyResponse
-		if err := json.Unmarshal(res.Body(), &response); err != nil {
-			return err
-		}
-		result = response.Result
+	if err := e.client.Do(req, res); err != nil {
+		return nil, err
 	}
 
-	if err := json.Unmarshal(result, out); err != nil {
-		return err
+	var resp response
+	if err := json.Unmarshal(res.Body(), &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response (status code %d): %v", res.StatusCode(), err)
 	}
-	return nil
+	return &resp, nil
 }
 
 // BlockNumber returns the number of most recent block.
@@ -126,28 +275,6 @@ func (e *Etherscan) GetBlockByNumber(i ethgo.BlockNumber, full bool) (*ethgo.Blo
 	return b, nil
 }
 
-type ContractCode struct {
-	SourceCode           string
-	ContractName         string
-	Runs                 string
-	CompilerVersion      string
-	ConstructorArguments string
-}
-
-func (e *Etherscan) GetContractCode(addr ethgo.Address) (*ContractCode, error) {
-	var out []*ContractCode
-	err := e.Query("contract", "getsourcecode", &out, map[string]string{
-		"address": addr.String(),
-	})
-	if err != nil {
-		return nil, err
-	}
-	if len(out) != 1 {
-		return nil, fmt.Errorf("incorrect values")
-	}
-	return out[0], nil
-}
-
 func parseUint64orHex(str string) (uint64, error) {
 	base := 10
 	if strings.HasPrefix(str, "0x") {
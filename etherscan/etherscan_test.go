@@ -1,8 +1,14 @@
 package etherscan
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/git-yongge/ethgo"
 	"github.com/stretchr/testify/assert"
@@ -13,7 +19,7 @@ func testEtherscanMainnet(t *testing.T) *Etherscan {
 	if apiKey == "" {
 		t.Skip("Etherscan APIKey not specified")
 	}
-	return &Etherscan{url: "https://api.etherscan.io", apiKey: apiKey}
+	return NewEtherscan("https://api.etherscan.io", apiKey)
 }
 
 func TestBlockByNumber(t *testing.T) {
@@ -39,3 +45,386 @@ func TestContract(t *testing.T) {
 	assert.Equal(t, code.Runs, "999999")
 
 }
+
+// mockEtherscan is an Etherscan api that answers with the handler of each action
+type mockEtherscan struct {
+	srv      *httptest.Server
+	lock     sync.Mutex
+	requests []url.Values
+	handlers map[string]func(args url.Values) string
+}
+
+func newMockEtherscan(t *testing.T) *mockEtherscan {
+	m := &mockEtherscan{
+		handlers: map[string]func(args url.Values) string{},
+	}
+	m.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api", r.URL.Path)
+		assert.NoError(t, r.ParseForm())
+
+		m.lock.Lock()
+		m.requests = append(m.requests, r.Form)
+		handler, ok := m.handlers[r.Form.Get("action")]
+		m.lock.Unlock()
+
+		if !ok {
+			w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Error! Missing Or invalid Action name"}`))
+			return
+		}
+		w.Write([]byte(handler(r.Form)))
+	}))
+	return m
+}
+
+func (m *mockEtherscan) handle(action string, handler func(args url.Values) string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.handlers[action] = handler
+}
+
+func (m *mockEtherscan) handleResult(action string, result string) {
+	m.handle(action, func(url.Values) string {
+		return `{"status":"1","message":"OK","result":` + result + `}`
+	})
+}
+
+func (m *mockEtherscan) lastRequest() url.Values {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.requests[len(m.requests)-1]
+}
+
+func TestEtherscan_Query(t *testing.T) {
+	m := newMockEtherscan(t)
+	defer m.srv.Close()
+
+	m.handle("eth_blockNumber", func(url.Values) string {
+		return `{"jsonrpc":"2.0","id":83,"result":"0x10"}`
+	})
+
+	// the api path is appended if not set
+	for _, addr := range []string{m.srv.URL, m.srv.URL + "/", m.srv.URL + "/api"} {
+		e := NewEtherscan(addr, "key", WithRateLimit(0))
+
+		num, err := e.BlockNumber()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(16), num)
+
+		req := m.lastRequest()
+		assert.Equal(t, "proxy", req.Get("module"))
+		assert.Equal(t, "key", req.Get("apikey"))
+	}
+
+	// errors in the result
+	e := NewEtherscan(m.srv.URL, "key", WithRateLimit(0))
+
+	_, err := e.GetABI(ethgo.Address{0x1})
+	assert.Error(t, err)
+}
+
+func TestEtherscan_RateLimit(t *testing.T) {
+	m := newMockEtherscan(t)
+	defer m.srv.Close()
+
+	calls := 0
+	m.handle("eth_blockNumber", func(url.Values) string {
+		calls++
+		if calls < 3 {
+			return `{"status":"0","message":"NOTOK","result":"Max rate limit reached, please use API Key for higher rate limit"}`
+		}
+		return `{"jsonrpc":"2.0","id":83,"result":"0x10"}`
+	})
+
+	e := NewEtherscan(m.srv.URL, "", WithRateLimit(0), WithRetries(3, time.Millisecond))
+
+	num, err := e.BlockNumber()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(16), num)
+	assert.Equal(t, 3, calls)
+
+	// the retries are exhausted
+	calls = 0
+	e = NewEtherscan(m.srv.URL, "", WithRateLimit(0), WithRetries(1, time.Millisecond))
+
+	_, err = e.BlockNumber()
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls)
+
+	// the limiter spaces the requests
+	e = NewEtherscan(m.srv.URL, "", WithRateLimit(20))
+
+	now := time.Now()
+	for i := 0; i < 4; i++ {
+		calls = 3
+		_, err := e.BlockNumber()
+		assert.NoError(t, err)
+	}
+	assert.GreaterOrEqual(t, time.Since(now), 150*time.Millisecond)
+}
+
+func TestEtherscan_Account(t *testing.T) {
+	m := newMockEtherscan(t)
+	defer m.srv.Close()
+
+	e := NewEtherscan(m.srv.URL, "", WithRateLimit(0))
+
+	addr := ethgo.HexToAddress("0xde0B295669a9FD93d5F28D9Ec85E40f4cb697BAe")
+
+	m.handleResult("txlist", `[{
+		"blockNumber":"14923678",
+		"timeStamp":"1654646411",
+		"hash":"0xc52783ad354aecc04c670047754f062e3d6d04e8f5b24774472651f9c3882c60",
+		"nonce":"1",
+		"blockHash":"0x7e1638fd2c6bdd05ffd83c1cf06c63e2f67d0f802084bef076d06bdcf86d1bb0",
+		"transactionIndex":"61",
+		"from":"0x9aa99c23f67c81701c772b106b4f83f6e858dd2e",
+		"to":"",
+		"value":"1000000000000000000",
+		"gas":"6385876",
+		"gasPrice":"83271581131",
+		"isError":"0",
+		"txreceipt_status":"1",
+		"input":"0x6080",
+		"contractAddress":"0xc5102fe9359fd9a28f877a67e36b0f050d81a3cc",
+		"cumulativeGasUsed":"10450178",
+		"gasUsed":"6385876",
+		"confirmations":"122485"
+	}]`)
+
+	txns, err := e.GetTransactions(addr, &ListOptions{StartBlock: 10, Page: 1, Offset: 5, Desc: true})
+	assert.NoError(t, err)
+	assert.Len(t, txns, 1)
+
+	txn := txns[0]
+	assert.Equal(t, uint64(14923678), txn.BlockNumber)
+	assert.Equal(t, uint64(61), txn.TransactionIndex)
+	assert.Nil(t, txn.To)
+	assert.Equal(t, ethgo.HexToAddress("0xc5102fe9359fd9a28f877a67e36b0f050d81a3cc"), *txn.ContractAddress)
+	assert.Equal(t, "1000000000000000000", txn.Value.String())
+	assert.Equal(t, []byte{0x60, 0x80}, txn.Input)
+	assert.False(t, txn.IsError)
+
+	req := m.lastRequest()
+	assert.Equal(t, "account", req.Get("module"))
+	assert.Equal(t, addr.String(), req.Get("address"))
+	assert.Equal(t, "10", req.Get("startblock"))
+	assert.Equal(t, "", req.Get("endblock"))
+	assert.Equal(t, "1", req.Get("page"))
+	assert.Equal(t, "5", req.Get("offset"))
+	assert.Equal(t, "desc", req.Get("sort"))
+
+	// an empty list is not an error
+	m.handle("txlistinternal", func(url.Values) string {
+		return `{"status":"0","message":"No transactions found","result":[]}`
+	})
+
+	internal, err := e.GetInternalTransactions(addr, nil)
+	assert.NoError(t, err)
+	assert.Len(t, internal, 0)
+
+	m.handleResult("txlistinternal", `[{
+		"blockNumber":"2535479",
+		"timeStamp":"1477837690",
+		"hash":"0x8a1a9989bda84f80143181a68bc137ecefa64d0d4ebde45dd94fc0cf49e70cb6",
+		"from":"0x20d42f2e99a421147acf198d775395cac2e8b03d",
+		"to":"",
+		"value":"0",
+		"contractAddress":"0x2c1ba59d6f58433fb1eaee7d20b26ed83bda51a3",
+		"input":"",
+		"type":"create",
+		"gas":"254791",
+		"gasUsed":"46750",
+		"traceId":"0",
+		"isError":"1",
+		"errCode":"Out of gas"
+	}]`)
+
+	internal, err = e.GetInternalTransactionsByHash(ethgo.HexToHash("0x8a1a9989bda84f80143181a68bc137ecefa64d0d4ebde45dd94fc0cf49e70cb6"))
+	assert.NoError(t, err)
+	assert.Len(t, internal, 1)
+	assert.Equal(t, "create", internal[0].Type)
+	assert.True(t, internal[0].IsError)
+	assert.Equal(t, "Out of gas", internal[0].ErrCode)
+	assert.Nil(t, internal[0].Input)
+
+	req = m.lastRequest()
+	assert.Equal(t, "0x8a1a9989bda84f80143181a68bc137ecefa64d0d4ebde45dd94fc0cf49e70cb6", req.Get("txhash"))
+
+	m.handleResult("tokennfttx", `[{
+		"blockNumber":"4708120",
+		"timeStamp":"1512907118",
+		"hash":"0x031e6968a8de362e4328d60dcc7f72f0d6fc84284c452f63176632177146de66",
+		"nonce":"0",
+		"blockHash":"0x4be19c278bfaead5cb0bc9476fa632e2447f6e6259e0303af210302d22779a24",
+		"from":"0xb1690c08e213a35ed9bab7b318de14420fb57d8c",
+		"contractAddress":"0x06012c8cf97bead5deae237070f9587f8e7a266d",
+		"to":"0x6975be450864c02b4613023c2152ee0743572325",
+		"tokenID":"202106",
+		"tokenName":"CryptoKitties",
+		"tokenSymbol":"CK",
+		"tokenDecimal":"0",
+		"transactionIndex":"81",
+		"gas":"158820",
+		"gasPrice":"40000000000",
+		"gasUsed":"60508",
+		"cumulativeGasUsed":"4880352",
+		"input":"deprecated",
+		"confirmations":"7990490"
+	}]`)
+
+	token := ethgo.HexToAddress("0x06012c8cf97bead5deae237070f9587f8e7a266d")
+
+	transfers, err := e.GetTokenTransfers(ERC721, &addr, &token, nil)
+	assert.NoError(t, err)
+	assert.Len(t, transfers, 1)
+	assert.Equal(t, "202106", transfers[0].TokenID.String())
+	assert.Equal(t, "CK", transfers[0].TokenSymbol)
+	assert.Nil(t, transfers[0].Value)
+
+	req = m.lastRequest()
+	assert.Equal(t, token.String(), req.Get("contractaddress"))
+
+	_, err = e.GetTokenTransfers(ERC20, nil, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestEtherscan_Logs(t *testing.T) {
+	m := newMockEtherscan(t)
+	defer m.srv.Close()
+
+	e := NewEtherscan(m.srv.URL, "", WithRateLimit(0))
+
+	m.handleResult("getLogs", `[{
+		"address":"0xbd3531da5cf5857e7cfaa92426877b022e612cf8",
+		"topics":[
+			"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+			"0x0000000000000000000000000000000000000000000000000000000000000000"
+		],
+		"data":"0x",
+		"blockNumber":"0xc48174",
+		"blockHash":"0x4d5bd6e6ddb7a42f5a0ad7be0e4d4cb4d8bee2d4db00cfcb7e9e2a6a33e1c0ee",
+		"timeStamp":"0x60f9ce56",
+		"gasPrice":"0x2e90edd000",
+		"gasUsed":"0x247205",
+		"logIndex":"0x",
+		"transactionHash":"0x4ffd22d986913d33927a392fe4319bcd2b62f3afe1c15a2c59f77fc2cc4c20a9",
+		"transactionIndex":"0x1"
+	}]`)
+
+	transfer := ethgo.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	zero := ethgo.Hash{}
+	addr := ethgo.HexToAddress("0xbd3531da5cf5857e7cfaa92426877b022e612cf8")
+
+	filter := &LogFilter{
+		FromBlock: 12878196,
+		Address:   &addr,
+		Topics:    [4]*ethgo.Hash{&transfer, &zero, &zero},
+	}
+	filter.SetOperator(0, 1, And)
+	filter.SetOperator(2, 1, Or)
+
+	logs, err := e.GetLogs(filter)
+	assert.NoError(t, err)
+	assert.Len(t, logs, 1)
+
+	log := logs[0]
+	assert.Equal(t, addr, log.Address)
+	assert.Equal(t, uint64(12878196), log.BlockNumber)
+	assert.Equal(t, uint64(0), log.LogIndex)
+	assert.Equal(t, uint64(1), log.TransactionIndex)
+	assert.Equal(t, []ethgo.Hash{transfer, zero}, log.Topics)
+	assert.Empty(t, log.Data)
+
+	req := m.lastRequest()
+	assert.Equal(t, "12878196", req.Get("fromBlock"))
+	assert.Equal(t, "latest", req.Get("toBlock"))
+	assert.Equal(t, transfer.String(), req.Get("topic0"))
+	assert.Equal(t, "and", req.Get("topic0_1_opr"))
+	assert.Equal(t, "or", req.Get("topic1_2_opr"))
+	assert.Equal(t, "", req.Get("topic3"))
+
+	// the operator requires both topics
+	filter.SetOperator(0, 3, Or)
+	_, err = e.GetLogs(filter)
+	assert.Error(t, err)
+}
+
+func TestEtherscan_Verify(t *testing.T) {
+	m := newMockEtherscan(t)
+	defer m.srv.Close()
+
+	e := NewEtherscan(m.srv.URL, "key", WithRateLimit(0))
+
+	m.handle("verifysourcecode", func(args url.Values) string {
+		return `{"status":"1","message":"OK","result":"ezq878u486pzijkvvmerl6a9mzwhv6sefgvqi5tkwceejc7tvn"}`
+	})
+
+	guid, err := e.VerifySourceCode(&VerifyRequest{
+		Address:              ethgo.Address{0x1},
+		SourceCode:           "contract A {}",
+		ContractName:         "A",
+		CompilerVersion:      "v0.8.19+commit.7dd6d404",
+		OptimizationUsed:     true,
+		Runs:                 200,
+		ConstructorArguments: []byte{0x1, 0x2},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "ezq878u486pzijkvvmerl6a9mzwhv6sefgvqi5tkwceejc7tvn", guid)
+
+	req := m.lastRequest()
+	assert.Equal(t, "contract A {}", req.Get("sourceCode"))
+	assert.Equal(t, "solidity-single-file", req.Get("codeformat"))
+	assert.Equal(t, "0102", req.Get("constructorArguements"))
+	assert.Equal(t, "1", req.Get("optimizationUsed"))
+	assert.Equal(t, "200", req.Get("runs"))
+	assert.Equal(t, "key", req.Get("apikey"))
+
+	polls := 0
+	m.handle("checkverifystatus", func(args url.Values) string {
+		assert.Equal(t, guid, args.Get("guid"))
+
+		polls++
+		if polls < 3 {
+			return `{"status":"0","message":"NOTOK","result":"Pending in queue"}`
+		}
+		return `{"status":"1","message":"OK","result":"Pass - Verified"}`
+	})
+
+	status, err := e.WaitForVerification(context.Background(), guid, time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, VerifyPass, status.State)
+	assert.Equal(t, "Pass - Verified", status.Message)
+	assert.Equal(t, 3, polls)
+
+	m.handle("checkverifystatus", func(args url.Values) string {
+		return `{"status":"0","message":"NOTOK","result":"Fail - Unable to verify"}`
+	})
+
+	status, err = e.CheckVerifyStatus(guid)
+	assert.NoError(t, err)
+	assert.Equal(t, VerifyFail, status.State)
+
+	// the context is cancelled while pending
+	m.handle("checkverifystatus", func(args url.Values) string {
+		return `{"status":"0","message":"NOTOK","result":"Pending in queue"}`
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = e.WaitForVerification(ctx, guid, time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestEtherscan_GetABI(t *testing.T) {
+	m := newMockEtherscan(t)
+	defer m.srv.Close()
+
+	e := NewEtherscan(m.srv.URL, "", WithRateLimit(0))
+
+	m.handleResult("getabi", `"[{\"type\":\"function\",\"name\":\"totalSupply\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}]}]"`)
+
+	contractABI, err := e.GetABI(ethgo.Address{0x1})
+	assert.NoError(t, err)
+	assert.NotNil(t, contractABI.GetMethod("totalSupply"))
+}
@@ -0,0 +1,144 @@
+package etherscan
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/git-yongge/ethgo"
+)
+
+// Operator is the operator between two topics of a logs query
+type Operator string
+
+const (
+	// And requires both topics to match (default)
+	And Operator = "and"
+
+	// Or requires any of the topics to match
+	Or Operator = "or"
+)
+
+// LogFilter is a logs query. Etherscan returns at most 1000 logs per query.
+type LogFilter struct {
+	FromBlock uint64
+
+	// ToBlock is the last block of the range, the latest block if zero
+	ToBlock uint64
+
+	Address *ethgo.Address
+
+	// Topics are the topics to match by position, nil matches any topic
+	Topics [4]*ethgo.Hash
+
+	// Page and Offset paginate the results (Offset items per page)
+	Page   int
+	Offset int
+
+	operators map[[2]int]Operator
+}
+
+// SetOperator sets the operator between the topics i and j (i.e. 0 and 1)
+func (l *LogFilter) SetOperator(i, j int, op Operator) {
+	if l.operators == nil {
+		l.operators = map[[2]int]Operator{}
+	}
+	if i > j {
+		i, j = j, i
+	}
+	l.operators[[2]int{i, j}] = op
+}
+
+func (l *LogFilter) params() (map[string]string, error) {
+	params := map[string]string{
+		"fromBlock": strconv.FormatUint(l.FromBlock, 10),
+		"toBlock":   "latest",
+	}
+	if l.ToBlock != 0 {
+		params["toBlock"] = strconv.FormatUint(l.ToBlock, 10)
+	}
+	if l.Address != nil {
+		params["address"] = l.Address.String()
+	}
+	for i, topic := range l.Topics {
+		if topic != nil {
+			params[fmt.Sprintf("topic%d", i)] = topic.String()
+		}
+	}
+	for pair, op := range l.operators {
+		i, j := pair[0], pair[1]
+		if i < 0 || j > 3 || i == j {
+			return nil, fmt.Errorf("invalid topics %d and %d for the operator", i, j)
+		}
+		if l.Topics[i] == nil || l.Topics[j] == nil {
+			return nil, fmt.Errorf("topics %d and %d are required for the operator", i, j)
+		}
+		if op != And && op != Or {
+			return nil, fmt.Errorf("invalid operator '%s'", op)
+		}
+		params[fmt.Sprintf("topic%d_%d_opr", i, j)] = string(op)
+	}
+	if l.Page != 0 {
+		params["page"] = strconv.Itoa(l.Page)
+	}
+	if l.Offset != 0 {
+		params["offset"] = strconv.Itoa(l.Offset)
+	}
+	return params, nil
+}
+
+type logItem struct {
+	Address          string   `json:"address"`
+	Topics           []string `json:"topics"`
+	Data             string   `json:"data"`
+	BlockNumber      string   `json:"blockNumber"`
+	BlockHash        string   `json:"blockHash"`
+	LogIndex         string   `json:"logIndex"`
+	TransactionHash  string   `json:"transactionHash"`
+	TransactionIndex string   `json:"transactionIndex"`
+}
+
+// GetLogs returns the logs that match the filter
+func (e *Etherscan) GetLogs(filter *LogFilter) ([]*ethgo.Log, error) {
+	params, err := filter.params()
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*logItem
+	if err := e.Query("logs", "getLogs", &items, params); err != nil {
+		return nil, err
+	}
+
+	res := make([]*ethgo.Log, len(items))
+	for i, item := range items {
+		p := &itemParser{item: map[string]string{
+			"address":          item.Address,
+			"data":             item.Data,
+			"blockNumber":      item.BlockNumber,
+			"blockHash":        item.BlockHash,
+			"logIndex":         item.LogIndex,
+			"transactionHash":  item.TransactionHash,
+			"transactionIndex": item.TransactionIndex,
+		}}
+		log := &ethgo.Log{
+			Address:          p.addr("address"),
+			Data:             p.bytes("data"),
+			BlockNumber:      p.uint64("blockNumber"),
+			BlockHash:        p.hash("blockHash"),
+			LogIndex:         p.uint64("logIndex"),
+			TransactionHash:  p.hash("transactionHash"),
+			TransactionIndex: p.uint64("transactionIndex"),
+			Topics:           make([]ethgo.Hash, len(item.Topics)),
+		}
+		for indx, topic := range item.Topics {
+			if err := log.Topics[indx].UnmarshalText([]byte(topic)); err != nil {
+				return nil, fmt.Errorf("failed to parse topic: %v", err)
+			}
+		}
+		if p.err != nil {
+			return nil, p.err
+		}
+		res[i] = log
+	}
+	return res, nil
+}
@@ -0,0 +1,192 @@
+package gasoracle
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/jsonrpc"
+)
+
+// ErrNotSupported is returned when the chain does not have a base fee (EIP-1559)
+var ErrNotSupported = errors.New("chain does not support EIP-1559 fees")
+
+// Provider are the eth1x methods required by the gas oracle
+type Provider interface {
+	FeeHistory(blockCount uint64, newestBlock ethgo.BlockNumber, rewardPercentiles []float64) (*jsonrpc.FeeHistory, error)
+	MaxPriorityFeePerGas() (*big.Int, error)
+}
+
+// Speed is how fast the transaction is expected to be included
+type Speed int
+
+const (
+	// Slow pays a low priority fee and covers a small increase of the base fee
+	Slow Speed = iota
+
+	// Normal pays the median priority fee
+	Normal
+
+	// Fast pays a high priority fee and covers the base fee doubling
+	Fast
+)
+
+type speedPreset struct {
+	// percentile of the priority fees paid in the recent blocks
+	percentile float64
+
+	// baseFeeMultiplier is the percentage of the next base fee covered by the max fee
+	baseFeeMultiplier int64
+}
+
+var presets = map[Speed]speedPreset{
+	Slow:   {percentile: 10, baseFeeMultiplier: 110},
+	Normal: {percentile: 50, baseFeeMultiplier: 125},
+	Fast:   {percentile: 90, baseFeeMultiplier: 200},
+}
+
+// String implements the stringer interface
+func (s Speed) String() string {
+	switch s {
+	case Slow:
+		return "slow"
+	case Normal:
+		return "normal"
+	case Fast:
+		return "fast"
+	default:
+		return fmt.Sprintf("Speed(%d)", int(s))
+	}
+}
+
+// Config is the gas oracle configuration
+type Config struct {
+	// BlockCount is the number of recent blocks sampled for the priority fee
+	BlockCount uint64
+
+	// Speed is the default speed used by Suggest
+	Speed Speed
+}
+
+// DefaultConfig returns the default gas oracle configuration
+func DefaultConfig() *Config {
+	return &Config{
+		BlockCount: 20,
+		Speed:      Normal,
+	}
+}
+
+// ConfigOption is an option to configure the gas oracle
+type ConfigOption func(*Config)
+
+// WithBlockCount sets the number of recent blocks sampled
+func WithBlockCount(n uint64) ConfigOption {
+	return func(c *Config) {
+		c.BlockCount = n
+	}
+}
+
+// WithSpeed sets the default speed
+func WithSpeed(s Speed) ConfigOption {
+	return func(c *Config) {
+		c.Speed = s
+	}
+}
+
+// Fees are the fee market values suggested for a transaction
+type Fees struct {
+	// BaseFee is the base fee per gas of the next block
+	BaseFee *big.Int
+
+	// MaxFeePerGas is the maximum total fee per gas the transaction pays
+	MaxFeePerGas *big.Int
+
+	// MaxPriorityFeePerGas is the maximum fee per gas paid to the block producer
+	MaxPriorityFeePerGas *big.Int
+}
+
+// Oracle suggests EIP-1559 fees from the fee history of the recent blocks
+type Oracle struct {
+	provider Provider
+	config   *Config
+}
+
+// NewOracle creates a new gas oracle
+func NewOracle(provider Provider, opts ...ConfigOption) *Oracle {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	return &Oracle{
+		provider: provider,
+		config:   config,
+	}
+}
+
+// Suggest returns the suggested fees with the default speed
+func (o *Oracle) Suggest() (*Fees, error) {
+	return o.SuggestFees(o.config.Speed)
+}
+
+// SuggestFees returns the suggested fees for the given speed. It returns
+// ErrNotSupported if the latest block does not have a base fee.
+func (o *Oracle) SuggestFees(speed Speed) (*Fees, error) {
+	preset, ok := presets[speed]
+	if !ok {
+		return nil, fmt.Errorf("speed %s not found", speed)
+	}
+
+	history, err := o.provider.FeeHistory(o.config.BlockCount, ethgo.Latest, []float64{preset.percentile})
+	if err != nil {
+		return nil, err
+	}
+	if history == nil || len(history.BaseFee) == 0 {
+		return nil, ErrNotSupported
+	}
+	// the last base fee is the one of the next block
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+	if baseFee == nil || baseFee.Sign() == 0 {
+		return nil, ErrNotSupported
+	}
+
+	tip := averageReward(history)
+	if tip == nil {
+		if tip, err = o.provider.MaxPriorityFeePerGas(); err != nil {
+			return nil, err
+		}
+	}
+
+	maxFee := new(big.Int).Mul(baseFee, big.NewInt(preset.baseFeeMultiplier))
+	maxFee.Div(maxFee, big.NewInt(100))
+	maxFee.Add(maxFee, tip)
+
+	fees := &Fees{
+		BaseFee:              new(big.Int).Set(baseFee),
+		MaxFeePerGas:         maxFee,
+		MaxPriorityFeePerGas: new(big.Int).Set(tip),
+	}
+	return fees, nil
+}
+
+// averageReward returns the average of the rewards of the blocks with
+// transactions or nil if there are none
+func averageReward(history *jsonrpc.FeeHistory) *big.Int {
+	sum := new(big.Int)
+	num := int64(0)
+	for indx, rewards := range history.Reward {
+		if len(rewards) == 0 || rewards[0] == nil {
+			continue
+		}
+		if indx < len(history.GasUsedRatio) && history.GasUsedRatio[indx] == 0 {
+			// empty blocks report a zero reward
+			continue
+		}
+		sum.Add(sum, rewards[0])
+		num++
+	}
+	if num == 0 {
+		return nil
+	}
+	return sum.Div(sum, big.NewInt(num))
+}
@@ -0,0 +1,97 @@
+package gasoracle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/jsonrpc"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockProvider struct {
+	history     *jsonrpc.FeeHistory
+	percentiles []float64
+	priorityFee *big.Int
+}
+
+func (m *mockProvider) FeeHistory(blockCount uint64, newestBlock ethgo.BlockNumber, rewardPercentiles []float64) (*jsonrpc.FeeHistory, error) {
+	m.percentiles = rewardPercentiles
+	return m.history, nil
+}
+
+func (m *mockProvider) MaxPriorityFeePerGas() (*big.Int, error) {
+	return m.priorityFee, nil
+}
+
+func TestOracle_SuggestFees(t *testing.T) {
+	p := &mockProvider{
+		history: &jsonrpc.FeeHistory{
+			OldestBlock:  10,
+			BaseFee:      []*big.Int{big.NewInt(90), big.NewInt(95), big.NewInt(100)},
+			GasUsedRatio: []float64{0.5, 0.7},
+			Reward:       [][]*big.Int{{big.NewInt(2)}, {big.NewInt(4)}},
+		},
+	}
+	o := NewOracle(p)
+
+	cases := []struct {
+		speed      Speed
+		percentile float64
+		maxFee     int64
+	}{
+		{Slow, 10, 110 + 3},
+		{Normal, 50, 125 + 3},
+		{Fast, 90, 200 + 3},
+	}
+	for _, c := range cases {
+		fees, err := o.SuggestFees(c.speed)
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{c.percentile}, p.percentiles)
+		assert.Equal(t, big.NewInt(100), fees.BaseFee)
+		assert.Equal(t, big.NewInt(3), fees.MaxPriorityFeePerGas)
+		assert.Equal(t, big.NewInt(c.maxFee), fees.MaxFeePerGas)
+	}
+
+	// the default speed is normal
+	fees, err := o.Suggest()
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(128), fees.MaxFeePerGas)
+}
+
+func TestOracle_EmptyBlocks(t *testing.T) {
+	// without rewards the priority fee suggested by the node is used
+	p := &mockProvider{
+		history: &jsonrpc.FeeHistory{
+			BaseFee:      []*big.Int{big.NewInt(100), big.NewInt(100)},
+			GasUsedRatio: []float64{0},
+			Reward:       [][]*big.Int{{big.NewInt(0)}},
+		},
+		priorityFee: big.NewInt(7),
+	}
+
+	fees, err := NewOracle(p, WithSpeed(Fast)).Suggest()
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(7), fees.MaxPriorityFeePerGas)
+	assert.Equal(t, big.NewInt(207), fees.MaxFeePerGas)
+}
+
+func TestOracle_NotSupported(t *testing.T) {
+	// pre-London blocks report a zero base fee
+	p := &mockProvider{
+		history: &jsonrpc.FeeHistory{
+			BaseFee:      []*big.Int{big.NewInt(0), big.NewInt(0)},
+			GasUsedRatio: []float64{0.5},
+			Reward:       [][]*big.Int{{big.NewInt(0)}},
+		},
+	}
+
+	_, err := NewOracle(p).Suggest()
+	assert.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestOracle_NoHistory(t *testing.T) {
+	// the node answers null to eth_feeHistory
+	_, err := NewOracle(&mockProvider{}).Suggest()
+	assert.ErrorIs(t, err, ErrNotSupported)
+}
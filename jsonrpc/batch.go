@@ -0,0 +1,71 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/git-yongge/ethgo/jsonrpc/transport"
+)
+
+// Batch queues several jsonrpc calls and sends them in a single request
+type Batch struct {
+	c     *Client
+	elems []*transport.BatchElem
+	calls []*BatchCall
+}
+
+// BatchCall is a call queued in a batch
+type BatchCall struct {
+	elem *transport.BatchElem
+	sent bool
+}
+
+// Err returns the error of the call once the batch has been sent.
+// It is either a *codec.ErrorObject returned by the node or a decoding error.
+func (b *BatchCall) Err() error {
+	if !b.sent {
+		return fmt.Errorf("batch not sent")
+	}
+	return b.elem.Error
+}
+
+// NewBatch creates a new empty batch
+func (c *Client) NewBatch() *Batch {
+	return &Batch{c: c}
+}
+
+// Add queues a call in the batch. The result is decoded into 'out' once the batch is sent.
+func (b *Batch) Add(method string, out interface{}, params ...interface{}) *BatchCall {
+	elem := &transport.BatchElem{
+		Method: method,
+		Params: params,
+		Result: out,
+	}
+	call := &BatchCall{elem: elem}
+
+	b.elems = append(b.elems, elem)
+	b.calls = append(b.calls, call)
+	return call
+}
+
+// Len returns the number of calls in the batch
+func (b *Batch) Len() int {
+	return len(b.elems)
+}
+
+// Do sends all the queued calls
+func (b *Batch) Do() error {
+	return b.DoContext(context.Background())
+}
+
+// DoContext sends all the queued calls. The returned error is only set if the
+// whole batch fails, the errors of each call are available with BatchCall.Err.
+func (b *Batch) DoContext(ctx context.Context) error {
+	if err := b.c.transport.BatchCall(ctx, b.elems); err != nil {
+		return err
+	}
+	for _, call := range b.calls {
+		call.sent = true
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/jsonrpc/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatch_HTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requests []codec.Request
+		if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+			t.Fatal(err)
+		}
+
+		// answer in reverse order to check the routing by id
+		responses := []interface{}{}
+		for i := len(requests) - 1; i >= 0; i-- {
+			req := requests[i]
+			switch req.Method {
+			case "eth_blockNumber":
+				responses = append(responses, map[string]interface{}{"id": req.ID, "result": "0x10"})
+			case "eth_getBlockByNumber":
+				responses = append(responses, map[string]interface{}{"id": req.ID, "result": map[string]interface{}{
+					"number":           "0x1",
+					"hash":             ethgo.Hash{0x1}.String(),
+					"parentHash":       ethgo.Hash{}.String(),
+					"sha3Uncles":       ethgo.Hash{}.String(),
+					"transactionsRoot": ethgo.Hash{}.String(),
+					"stateRoot":        ethgo.Hash{}.String(),
+					"receiptsRoot":     ethgo.Hash{}.String(),
+					"miner":            ethgo.Address{}.String(),
+					"gasLimit":         "0x0",
+					"gasUsed":          "0x0",
+					"timestamp":        "0x0",
+					"difficulty":       "0x0",
+					"extraData":        "0x",
+				}})
+			default:
+				responses = append(responses, map[string]interface{}{"id": req.ID, "error": map[string]interface{}{"code": -32601, "message": "method not found"}})
+			}
+		}
+		json.NewEncoder(w).Encode(responses)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL)
+	assert.NoError(t, err)
+
+	var num string
+	var block *ethgo.Block
+	var unknown string
+
+	batch := c.NewBatch()
+	numCall := batch.Add("eth_blockNumber", &num)
+	blockCall := batch.Add("eth_getBlockByNumber", &block, ethgo.BlockNumber(1).String(), false)
+	unknownCall := batch.Add("eth_unknown", &unknown)
+	assert.Equal(t, 3, batch.Len())
+
+	assert.Error(t, numCall.Err())
+	assert.NoError(t, batch.Do())
+
+	assert.NoError(t, numCall.Err())
+	assert.Equal(t, "0x10", num)
+
+	assert.NoError(t, blockCall.Err())
+	assert.Equal(t, uint64(1), block.Number)
+	assert.Equal(t, ethgo.Hash{0x1}, block.Hash)
+
+	obj, ok := unknownCall.Err().(*codec.ErrorObject)
+	assert.True(t, ok)
+	assert.Equal(t, -32601, obj.Code)
+}
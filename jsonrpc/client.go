@@ -1,6 +1,9 @@
 package jsonrpc
 
 import (
+	"context"
+	"time"
+
 	"github.com/git-yongge/ethgo/jsonrpc/transport"
 )
 
@@ -15,10 +18,12 @@ type endpoints struct {
 	e *Eth
 	n *Net
 	d *Debug
+	p *Personal
 }
 
 type Config struct {
-	headers map[string]string
+	headers   map[string]string
+	websocket []transport.WebsocketOption
 }
 
 type ConfigOption func(*Config)
@@ -31,6 +36,58 @@ func WithHeaders(headers map[string]string) ConfigOption {
 	}
 }
 
+// WithReconnect enables reconnecting a websocket connection that drops, it does not
+// reconnect by default. The backoff between the attempts starts at minBackoff and
+// doubles after every failed attempt up to maxBackoff, it is never shorter than 100ms.
+func WithReconnect(minBackoff, maxBackoff time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.websocket = append(c.websocket, func(w *transport.WebsocketConfig) {
+			w.Reconnect = true
+			w.MinBackoff = minBackoff
+			w.MaxBackoff = maxBackoff
+		})
+	}
+}
+
+// WithoutReconnect disables reconnecting a websocket connection that drops
+func WithoutReconnect() ConfigOption {
+	return func(c *Config) {
+		c.websocket = append(c.websocket, func(w *transport.WebsocketConfig) {
+			w.Reconnect = false
+		})
+	}
+}
+
+// WithKeepAlive sets the interval of the websocket pings and how long to wait for the pong.
+// There are no pings by default, a zero interval disables them.
+func WithKeepAlive(interval, timeout time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.websocket = append(c.websocket, func(w *transport.WebsocketConfig) {
+			w.PingInterval = interval
+			w.PongTimeout = timeout
+		})
+	}
+}
+
+// WithReadLimit sets the maximum size in bytes of a websocket message
+func WithReadLimit(limit int64) ConfigOption {
+	return func(c *Config) {
+		c.websocket = append(c.websocket, func(w *transport.WebsocketConfig) {
+			w.ReadLimit = limit
+		})
+	}
+}
+
+// WithConnectionState sets a handler for the state changes of a websocket connection.
+// The changes are notified in order from a goroutine of their own.
+func WithConnectionState(handler func(state transport.ConnState, err error)) ConfigOption {
+	return func(c *Config) {
+		c.websocket = append(c.websocket, func(w *transport.WebsocketConfig) {
+			w.OnStateChange = handler
+		})
+	}
+}
+
 func NewClient(addr string, opts ...ConfigOption) (*Client, error) {
 	config := &Config{headers: map[string]string{}}
 	for _, opt := range opts {
@@ -42,8 +99,9 @@ func NewClient(addr string, opts ...ConfigOption) (*Client, error) {
 	c.endpoints.e = &Eth{c}
 	c.endpoints.n = &Net{c}
 	c.endpoints.d = &Debug{c}
+	c.endpoints.p = &Personal{c}
 
-	t, err := transport.NewTransport(addr, config.headers)
+	t, err := transport.NewTransport(addr, config.headers, config.websocket...)
 	if err != nil {
 		return nil, err
 	}
@@ -61,6 +119,12 @@ func (c *Client) Call(method string, out interface{}, params ...interface{}) err
 	return c.transport.Call(method, out, params...)
 }
 
+// CallContext makes a jsonrpc call that is aborted if the context is cancelled
+// or its deadline expires before the response arrives
+func (c *Client) CallContext(ctx context.Context, method string, out interface{}, params ...interface{}) error {
+	return c.transport.CallContext(ctx, method, out, params...)
+}
+
 // SetMaxConnsLimit sets the maximum number of connections that can be established with a host
 func (c *Client) SetMaxConnsLimit(count int) {
 	c.transport.SetMaxConnsPerHost(count)
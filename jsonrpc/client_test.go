@@ -0,0 +1,70 @@
+package jsonrpc
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_CallContextCancel(t *testing.T) {
+	// the server never answers before the client gives up
+	doneCh := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-doneCh
+	}))
+	defer srv.Close()
+	defer close(doneCh)
+
+	c, err := NewClient(srv.URL)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = c.Eth().BlockNumberContext(ctx)
+	assert.Error(t, err)
+
+	// a context that is already cancelled does not reach the server
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	_, err = c.Eth().BlockNumberContext(ctx)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestClient_CallContextCancelClosesRequest(t *testing.T) {
+	// the request of the server is cancelled once the client gives up
+	reqCh := make(chan struct{})
+	closedCh := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the server notices the closed connection once the body is read
+		ioutil.ReadAll(r.Body)
+		close(reqCh)
+		<-r.Context().Done()
+		close(closedCh)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-reqCh
+		cancel()
+	}()
+
+	_, err = c.Eth().BlockNumberContext(ctx)
+	assert.Equal(t, context.Canceled, err)
+
+	select {
+	case <-closedCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the request is still running")
+	}
+}
@@ -1,6 +1,10 @@
 package jsonrpc
 
-import "github.com/git-yongge/ethgo"
+import (
+	"context"
+
+	"github.com/git-yongge/ethgo"
+)
 
 type Debug struct {
 	c *Client
@@ -29,7 +33,11 @@ type StructLogs struct {
 }
 
 func (d *Debug) TraceTransaction(hash ethgo.Hash) (*TransactionTrace, error) {
+	return d.TraceTransactionContext(context.Background(), hash)
+}
+
+func (d *Debug) TraceTransactionContext(ctx context.Context, hash ethgo.Hash) (*TransactionTrace, error) {
 	var res *TransactionTrace
-	err := d.c.Call("debug_traceTransaction", &res, hash)
+	err := d.c.CallContext(ctx, "debug_traceTransaction", &res, hash)
 	return res, err
 }
@@ -1,6 +1,7 @@
 package jsonrpc
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -21,8 +22,13 @@ func (c *Client) Eth() *Eth {
 
 // GetCode returns the code of a contract
 func (e *Eth) GetCode(addr ethgo.Address, block ethgo.BlockNumberOrHash) (string, error) {
+	return e.GetCodeContext(context.Background(), addr, block)
+}
+
+// GetCodeContext returns the code of a contract
+func (e *Eth) GetCodeContext(ctx context.Context, addr ethgo.Address, block ethgo.BlockNumberOrHash) (string, error) {
 	var res string
-	if err := e.c.Call("eth_getCode", &res, addr, block.Location()); err != nil {
+	if err := e.c.CallContext(ctx, "eth_getCode", &res, addr, encodeBlock(block)); err != nil {
 		return "", err
 	}
 	return res, nil
@@ -30,8 +36,13 @@ func (e *Eth) GetCode(addr ethgo.Address, block ethgo.BlockNumberOrHash) (string
 
 // Accounts returns a list of addresses owned by client.
 func (e *Eth) Accounts() ([]ethgo.Address, error) {
+	return e.AccountsContext(context.Background())
+}
+
+// AccountsContext returns a list of addresses owned by client.
+func (e *Eth) AccountsContext(ctx context.Context) ([]ethgo.Address, error) {
 	var out []ethgo.Address
-	if err := e.c.Call("eth_accounts", &out); err != nil {
+	if err := e.c.CallContext(ctx, "eth_accounts", &out); err != nil {
 		return nil, err
 	}
 	return out, nil
@@ -39,15 +50,25 @@ func (e *Eth) Accounts() ([]ethgo.Address, error) {
 
 // GetStorageAt returns the value from a storage position at a given address.
 func (e *Eth) GetStorageAt(addr ethgo.Address, slot ethgo.Hash, block ethgo.BlockNumberOrHash) (ethgo.Hash, error) {
+	return e.GetStorageAtContext(context.Background(), addr, slot, block)
+}
+
+// GetStorageAtContext returns the value from a storage position at a given address.
+func (e *Eth) GetStorageAtContext(ctx context.Context, addr ethgo.Address, slot ethgo.Hash, block ethgo.BlockNumberOrHash) (ethgo.Hash, error) {
 	var hash ethgo.Hash
-	err := e.c.Call("eth_getStorageAt", &hash, addr, slot, block.Location())
+	err := e.c.CallContext(ctx, "eth_getStorageAt", &hash, addr, slot, encodeBlock(block))
 	return hash, err
 }
 
 // BlockNumber returns the number of most recent block.
 func (e *Eth) BlockNumber() (uint64, error) {
+	return e.BlockNumberContext(context.Background())
+}
+
+// BlockNumberContext returns the number of most recent block.
+func (e *Eth) BlockNumberContext(ctx context.Context) (uint64, error) {
 	var out string
-	if err := e.c.Call("eth_blockNumber", &out); err != nil {
+	if err := e.c.CallContext(ctx, "eth_blockNumber", &out); err != nil {
 		return 0, err
 	}
 	return parseUint64orHex(out)
@@ -55,8 +76,13 @@ func (e *Eth) BlockNumber() (uint64, error) {
 
 // GetBlockByNumber returns information about a block by block number.
 func (e *Eth) GetBlockByNumber(i ethgo.BlockNumber, full bool) (*ethgo.Block, error) {
+	return e.GetBlockByNumberContext(context.Background(), i, full)
+}
+
+// GetBlockByNumberContext returns information about a block by block number.
+func (e *Eth) GetBlockByNumberContext(ctx context.Context, i ethgo.BlockNumber, full bool) (*ethgo.Block, error) {
 	var b *ethgo.Block
-	if err := e.c.Call("eth_getBlockByNumber", &b, i.String(), full); err != nil {
+	if err := e.c.CallContext(ctx, "eth_getBlockByNumber", &b, i.String(), full); err != nil {
 		return nil, err
 	}
 	return b, nil
@@ -64,8 +90,13 @@ func (e *Eth) GetBlockByNumber(i ethgo.BlockNumber, full bool) (*ethgo.Block, er
 
 // GetBlockByHash returns information about a block by hash.
 func (e *Eth) GetBlockByHash(hash ethgo.Hash, full bool) (*ethgo.Block, error) {
+	return e.GetBlockByHashContext(context.Background(), hash, full)
+}
+
+// GetBlockByHashContext returns information about a block by hash.
+func (e *Eth) GetBlockByHashContext(ctx context.Context, hash ethgo.Hash, full bool) (*ethgo.Block, error) {
 	var b *ethgo.Block
-	if err := e.c.Call("eth_getBlockByHash", &b, hash, full); err != nil {
+	if err := e.c.CallContext(ctx, "eth_getBlockByHash", &b, hash, full); err != nil {
 		return nil, err
 	}
 	return b, nil
@@ -73,8 +104,13 @@ func (e *Eth) GetBlockByHash(hash ethgo.Hash, full bool) (*ethgo.Block, error) {
 
 // GetFilterChanges returns the filter changes for log filters
 func (e *Eth) GetFilterChanges(id string) ([]*ethgo.Log, error) {
+	return e.GetFilterChangesContext(context.Background(), id)
+}
+
+// GetFilterChangesContext returns the filter changes for log filters
+func (e *Eth) GetFilterChangesContext(ctx context.Context, id string) ([]*ethgo.Log, error) {
 	var raw string
-	err := e.c.Call("eth_getFilterChanges", &raw, id)
+	err := e.c.CallContext(ctx, "eth_getFilterChanges", &raw, id)
 	if err != nil {
 		return nil, err
 	}
@@ -87,15 +123,25 @@ func (e *Eth) GetFilterChanges(id string) ([]*ethgo.Log, error) {
 
 // GetTransactionByHash returns a transaction by his hash
 func (e *Eth) GetTransactionByHash(hash ethgo.Hash) (*ethgo.Transaction, error) {
+	return e.GetTransactionByHashContext(context.Background(), hash)
+}
+
+// GetTransactionByHashContext returns a transaction by his hash
+func (e *Eth) GetTransactionByHashContext(ctx context.Context, hash ethgo.Hash) (*ethgo.Transaction, error) {
 	var txn *ethgo.Transaction
-	err := e.c.Call("eth_getTransactionByHash", &txn, hash)
+	err := e.c.CallContext(ctx, "eth_getTransactionByHash", &txn, hash)
 	return txn, err
 }
 
 // GetFilterChangesBlock returns the filter changes for block filters
 func (e *Eth) GetFilterChangesBlock(id string) ([]ethgo.Hash, error) {
+	return e.GetFilterChangesBlockContext(context.Background(), id)
+}
+
+// GetFilterChangesBlockContext returns the filter changes for block filters
+func (e *Eth) GetFilterChangesBlockContext(ctx context.Context, id string) ([]ethgo.Hash, error) {
 	var raw string
-	err := e.c.Call("eth_getFilterChanges", &raw, id)
+	err := e.c.CallContext(ctx, "eth_getFilterChanges", &raw, id)
 	if err != nil {
 		return nil, err
 	}
@@ -108,51 +154,116 @@ func (e *Eth) GetFilterChangesBlock(id string) ([]ethgo.Hash, error) {
 
 // NewFilter creates a new log filter
 func (e *Eth) NewFilter(filter *ethgo.LogFilter) (string, error) {
+	return e.NewFilterContext(context.Background(), filter)
+}
+
+// NewFilterContext creates a new log filter
+func (e *Eth) NewFilterContext(ctx context.Context, filter *ethgo.LogFilter) (string, error) {
 	var id string
-	err := e.c.Call("eth_newFilter", &id, filter)
+	err := e.c.CallContext(ctx, "eth_newFilter", &id, filter)
 	return id, err
 }
 
 // NewBlockFilter creates a new block filter
 func (e *Eth) NewBlockFilter() (string, error) {
+	return e.NewBlockFilterContext(context.Background())
+}
+
+// NewBlockFilterContext creates a new block filter
+func (e *Eth) NewBlockFilterContext(ctx context.Context) (string, error) {
 	var id string
-	err := e.c.Call("eth_newBlockFilter", &id, nil)
+	err := e.c.CallContext(ctx, "eth_newBlockFilter", &id, nil)
 	return id, err
 }
 
 // UninstallFilter uninstalls a filter
 func (e *Eth) UninstallFilter(id string) (bool, error) {
+	return e.UninstallFilterContext(context.Background(), id)
+}
+
+// UninstallFilterContext uninstalls a filter
+func (e *Eth) UninstallFilterContext(ctx context.Context, id string) (bool, error) {
 	var res bool
-	err := e.c.Call("eth_uninstallFilter", &res, id)
+	err := e.c.CallContext(ctx, "eth_uninstallFilter", &res, id)
 	return res, err
 }
 
 // SendRawTransaction sends a signed transaction in rlp format.
 func (e *Eth) SendRawTransaction(data []byte) (ethgo.Hash, error) {
+	return e.SendRawTransactionContext(context.Background(), data)
+}
+
+// SendRawTransactionContext sends a signed transaction in rlp format.
+func (e *Eth) SendRawTransactionContext(ctx context.Context, data []byte) (ethgo.Hash, error) {
 	var hash ethgo.Hash
 	hexData := "0x" + hex.EncodeToString(data)
-	err := e.c.Call("eth_sendRawTransaction", &hash, hexData)
+	err := e.c.CallContext(ctx, "eth_sendRawTransaction", &hash, hexData)
 	return hash, err
 }
 
+// Sign signs the data with the key of the account in the node (eth_sign). The node
+// signs the EIP-191 personal message hash of the data and v is 27 or 28.
+func (e *Eth) Sign(addr ethgo.Address, data []byte) ([]byte, error) {
+	return e.SignContext(context.Background(), addr, data)
+}
+
+// SignContext signs the data with the key of the account in the node (eth_sign).
+func (e *Eth) SignContext(ctx context.Context, addr ethgo.Address, data []byte) ([]byte, error) {
+	var out string
+	if err := e.c.CallContext(ctx, "eth_sign", &out, addr, encodeToHex(data)); err != nil {
+		return nil, err
+	}
+	return parseHexBytes(out)
+}
+
 // SendTransaction creates new message call transaction or a contract creation.
 func (e *Eth) SendTransaction(txn *ethgo.Transaction) (ethgo.Hash, error) {
+	return e.SendTransactionContext(context.Background(), txn)
+}
+
+// SendTransactionContext creates new message call transaction or a contract creation.
+func (e *Eth) SendTransactionContext(ctx context.Context, txn *ethgo.Transaction) (ethgo.Hash, error) {
+	var param interface{} = txn
+	if txn.Type == ethgo.TransactionDynamicFee && txn.GasPrice == 0 {
+		// the nodes reject a dynamic fee transaction with a gas price
+		raw, err := txn.MarshalJSON()
+		if err != nil {
+			return ethgo.Hash{}, err
+		}
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return ethgo.Hash{}, err
+		}
+		delete(obj, "gasPrice")
+		param = obj
+	}
+
 	var hash ethgo.Hash
-	err := e.c.Call("eth_sendTransaction", &hash, txn)
+	err := e.c.CallContext(ctx, "eth_sendTransaction", &hash, param)
 	return hash, err
 }
 
 // GetTransactionReceipt returns the receipt of a transaction by transaction hash.
 func (e *Eth) GetTransactionReceipt(hash ethgo.Hash) (*ethgo.Receipt, error) {
+	return e.GetTransactionReceiptContext(context.Background(), hash)
+}
+
+// GetTransactionReceiptContext returns the receipt of a transaction by transaction hash.
+func (e *Eth) GetTransactionReceiptContext(ctx context.Context, hash ethgo.Hash) (*ethgo.Receipt, error) {
 	var receipt *ethgo.Receipt
-	err := e.c.Call("eth_getTransactionReceipt", &receipt, hash)
+	err := e.c.CallContext(ctx, "eth_getTransactionReceipt", &receipt, hash)
 	return receipt, err
 }
 
 // GetNonce returns the nonce of the account
 func (e *Eth) GetNonce(addr ethgo.Address, blockNumber ethgo.BlockNumberOrHash) (uint64, error) {
+	return e.GetNonceContext(context.Background(), addr, blockNumber)
+}
+
+// GetNonceContext returns the nonce of the account
+func (e *Eth) GetNonceContext(ctx context.Context, addr ethgo.Address, blockNumber ethgo.BlockNumberOrHash) (uint64, error) {
 	var nonce string
-	if err := e.c.Call("eth_getTransactionCount", &nonce, addr, blockNumber.Location()); err != nil {
+	if err := e.c.CallContext(ctx, "eth_getTransactionCount", &nonce, addr, encodeBlock(blockNumber)); err != nil {
 		return 0, err
 	}
 	return parseUint64orHex(nonce)
@@ -160,8 +271,13 @@ func (e *Eth) GetNonce(addr ethgo.Address, blockNumber ethgo.BlockNumberOrHash)
 
 // GetBalance returns the balance of the account of given address.
 func (e *Eth) GetBalance(addr ethgo.Address, blockNumber ethgo.BlockNumberOrHash) (*big.Int, error) {
+	return e.GetBalanceContext(context.Background(), addr, blockNumber)
+}
+
+// GetBalanceContext returns the balance of the account of given address.
+func (e *Eth) GetBalanceContext(ctx context.Context, addr ethgo.Address, blockNumber ethgo.BlockNumberOrHash) (*big.Int, error) {
 	var out string
-	if err := e.c.Call("eth_getBalance", &out, addr, blockNumber.Location()); err != nil {
+	if err := e.c.CallContext(ctx, "eth_getBalance", &out, addr, encodeBlock(blockNumber)); err != nil {
 		return nil, err
 	}
 	b, ok := new(big.Int).SetString(out[2:], 16)
@@ -171,19 +287,48 @@ func (e *Eth) GetBalance(addr ethgo.Address, blockNumber ethgo.BlockNumberOrHash
 	return b, nil
 }
 
+// GetProof returns the proof of an account and of some of its storage slots.
+// Use trie.VerifyAccountProof to check it against the state root of the block.
+func (e *Eth) GetProof(addr ethgo.Address, slots []ethgo.Hash, block ethgo.BlockNumberOrHash) (*ethgo.AccountProof, error) {
+	return e.GetProofContext(context.Background(), addr, slots, block)
+}
+
+// GetProofContext returns the proof of an account and of some of its storage slots.
+// Use trie.VerifyAccountProof to check it against the state root of the block.
+func (e *Eth) GetProofContext(ctx context.Context, addr ethgo.Address, slots []ethgo.Hash, block ethgo.BlockNumberOrHash) (*ethgo.AccountProof, error) {
+	if slots == nil {
+		slots = []ethgo.Hash{}
+	}
+	var proof *ethgo.AccountProof
+	if err := e.c.CallContext(ctx, "eth_getProof", &proof, addr, slots, encodeBlock(block)); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
 // GasPrice returns the current price per gas in wei.
 func (e *Eth) GasPrice() (uint64, error) {
+	return e.GasPriceContext(context.Background())
+}
+
+// GasPriceContext returns the current price per gas in wei.
+func (e *Eth) GasPriceContext(ctx context.Context) (uint64, error) {
 	var out string
-	if err := e.c.Call("eth_gasPrice", &out); err != nil {
+	if err := e.c.CallContext(ctx, "eth_gasPrice", &out); err != nil {
 		return 0, err
 	}
 	return parseUint64orHex(out)
 }
 
 // Call executes a new message call immediately without creating a transaction on the block chain.
-func (e *Eth) Call(msg *ethgo.CallMsg, block ethgo.BlockNumber) (string, error) {
+func (e *Eth) Call(msg *ethgo.CallMsg, block ethgo.BlockNumberOrHash) (string, error) {
+	return e.CallContext(context.Background(), msg, block)
+}
+
+// CallContext executes a new message call immediately without creating a transaction on the block chain.
+func (e *Eth) CallContext(ctx context.Context, msg *ethgo.CallMsg, block ethgo.BlockNumberOrHash) (string, error) {
 	var out string
-	if err := e.c.Call("eth_call", &out, msg, block.String()); err != nil {
+	if err := e.c.CallContext(ctx, "eth_call", &out, msg, encodeBlock(block)); err != nil {
 		return "", err
 	}
 	return out, nil
@@ -191,11 +336,16 @@ func (e *Eth) Call(msg *ethgo.CallMsg, block ethgo.BlockNumber) (string, error)
 
 // EstimateGasContract estimates the gas to deploy a contract
 func (e *Eth) EstimateGasContract(bin []byte) (uint64, error) {
+	return e.EstimateGasContractContext(context.Background(), bin)
+}
+
+// EstimateGasContractContext estimates the gas to deploy a contract
+func (e *Eth) EstimateGasContractContext(ctx context.Context, bin []byte) (uint64, error) {
 	var out string
 	msg := map[string]interface{}{
 		"data": "0x" + hex.EncodeToString(bin),
 	}
-	if err := e.c.Call("eth_estimateGas", &out, msg); err != nil {
+	if err := e.c.CallContext(ctx, "eth_estimateGas", &out, msg); err != nil {
 		return 0, err
 	}
 	return parseUint64orHex(out)
@@ -203,8 +353,13 @@ func (e *Eth) EstimateGasContract(bin []byte) (uint64, error) {
 
 // EstimateGas generates and returns an estimate of how much gas is necessary to allow the transaction to complete.
 func (e *Eth) EstimateGas(msg *ethgo.CallMsg) (uint64, error) {
+	return e.EstimateGasContext(context.Background(), msg)
+}
+
+// EstimateGasContext generates and returns an estimate of how much gas is necessary to allow the transaction to complete.
+func (e *Eth) EstimateGasContext(ctx context.Context, msg *ethgo.CallMsg) (uint64, error) {
 	var out string
-	if err := e.c.Call("eth_estimateGas", &out, msg); err != nil {
+	if err := e.c.CallContext(ctx, "eth_estimateGas", &out, msg); err != nil {
 		return 0, err
 	}
 	return parseUint64orHex(out)
@@ -212,8 +367,13 @@ func (e *Eth) EstimateGas(msg *ethgo.CallMsg) (uint64, error) {
 
 // GetLogs returns an array of all logs matching a given filter object
 func (e *Eth) GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error) {
+	return e.GetLogsContext(context.Background(), filter)
+}
+
+// GetLogsContext returns an array of all logs matching a given filter object
+func (e *Eth) GetLogsContext(ctx context.Context, filter *ethgo.LogFilter) ([]*ethgo.Log, error) {
 	var out []*ethgo.Log
-	if err := e.c.Call("eth_getLogs", &out, filter); err != nil {
+	if err := e.c.CallContext(ctx, "eth_getLogs", &out, filter); err != nil {
 		return nil, err
 	}
 	return out, nil
@@ -221,8 +381,97 @@ func (e *Eth) GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error) {
 
 // ChainID returns the id of the chain
 func (e *Eth) ChainID() (*big.Int, error) {
+	return e.ChainIDContext(context.Background())
+}
+
+// ChainIDContext returns the id of the chain
+func (e *Eth) ChainIDContext(ctx context.Context) (*big.Int, error) {
+	var out string
+	if err := e.c.CallContext(ctx, "eth_chainId", &out); err != nil {
+		return nil, err
+	}
+	return parseBigInt(out), nil
+}
+
+// FeeHistory is the result of the eth_feeHistory endpoint
+type FeeHistory struct {
+	// OldestBlock is the first block of the range
+	OldestBlock uint64
+
+	// BaseFee are the base fees per gas of each block in the range plus
+	// the base fee of the next block after the newest one
+	BaseFee []*big.Int
+
+	// GasUsedRatio are the ratios of gas used by each block in the range
+	GasUsedRatio []float64
+
+	// Reward are the effective priority fees per gas at the requested
+	// percentiles for each block in the range
+	Reward [][]*big.Int
+}
+
+// UnmarshalJSON implements the unmarshal interface
+func (f *FeeHistory) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		OldestBlock  string     `json:"oldestBlock"`
+		BaseFee      []string   `json:"baseFeePerGas"`
+		GasUsedRatio []float64  `json:"gasUsedRatio"`
+		Reward       [][]string `json:"reward"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var err error
+	if f.OldestBlock, err = parseUint64orHex(raw.OldestBlock); err != nil {
+		return err
+	}
+	f.BaseFee = make([]*big.Int, len(raw.BaseFee))
+	for indx, str := range raw.BaseFee {
+		f.BaseFee[indx] = parseBigInt(str)
+	}
+	f.GasUsedRatio = raw.GasUsedRatio
+	f.Reward = make([][]*big.Int, len(raw.Reward))
+	for indx, rewards := range raw.Reward {
+		f.Reward[indx] = make([]*big.Int, len(rewards))
+		for j, str := range rewards {
+			f.Reward[indx][j] = parseBigInt(str)
+		}
+	}
+	return nil
+}
+
+// FeeHistory returns the base fees and the priority fees at the given percentiles
+// for a range of blocks that ends at the newest block.
+func (e *Eth) FeeHistory(blockCount uint64, newestBlock ethgo.BlockNumber, rewardPercentiles []float64) (*FeeHistory, error) {
+	return e.FeeHistoryContext(context.Background(), blockCount, newestBlock, rewardPercentiles)
+}
+
+// FeeHistoryContext returns the base fees and the priority fees at the given percentiles
+// for a range of blocks that ends at the newest block.
+func (e *Eth) FeeHistoryContext(ctx context.Context, blockCount uint64, newestBlock ethgo.BlockNumber, rewardPercentiles []float64) (*FeeHistory, error) {
+	if rewardPercentiles == nil {
+		rewardPercentiles = []float64{}
+	}
+	var out *FeeHistory
+	if err := e.c.CallContext(ctx, "eth_feeHistory", &out, encodeUintToHex(blockCount), newestBlock.String(), rewardPercentiles); err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, fmt.Errorf("fee history not found")
+	}
+	return out, nil
+}
+
+// MaxPriorityFeePerGas returns the priority fee per gas suggested by the node
+func (e *Eth) MaxPriorityFeePerGas() (*big.Int, error) {
+	return e.MaxPriorityFeePerGasContext(context.Background())
+}
+
+// MaxPriorityFeePerGasContext returns the priority fee per gas suggested by the node
+func (e *Eth) MaxPriorityFeePerGasContext(ctx context.Context) (*big.Int, error) {
 	var out string
-	if err := e.c.Call("eth_chainId", &out); err != nil {
+	if err := e.c.CallContext(ctx, "eth_maxPriorityFeePerGas", &out); err != nil {
 		return nil, err
 	}
 	return parseBigInt(out), nil
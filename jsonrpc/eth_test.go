@@ -3,12 +3,15 @@ package jsonrpc
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"math/big"
 	"strings"
 	"testing"
 
 	"github.com/git-yongge/ethgo"
 	"github.com/git-yongge/ethgo/testutil"
+	"github.com/git-yongge/ethgo/trie"
+	"github.com/git-yongge/ethgo/wallet"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -363,3 +366,162 @@ func TestEthGetStorageAt(t *testing.T) {
 		assert.True(t, strings.HasSuffix(res.String(), "a"))
 	}
 }
+
+func TestEthFeeHistory(t *testing.T) {
+	s := testutil.NewTestServer(t, nil)
+	defer s.Close()
+
+	c, _ := NewClient(s.HTTPAddr())
+	defer c.Close()
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, s.ProcessBlock())
+	}
+
+	history, err := c.Eth().FeeHistory(2, ethgo.Latest, []float64{10, 90})
+	assert.NoError(t, err)
+	assert.Len(t, history.BaseFee, 3)
+	assert.Len(t, history.GasUsedRatio, 2)
+	assert.Len(t, history.Reward, 2)
+
+	_, err = c.Eth().MaxPriorityFeePerGas()
+	assert.NoError(t, err)
+}
+
+func TestFeeHistory_Unmarshal(t *testing.T) {
+	raw := `{
+		"oldestBlock": "0x10",
+		"baseFeePerGas": ["0x3b9aca00", "0x3b9aca01"],
+		"gasUsedRatio": [0.5],
+		"reward": [["0x1", "0x2"]]
+	}`
+
+	var history *FeeHistory
+	assert.NoError(t, json.Unmarshal([]byte(raw), &history))
+	assert.Equal(t, uint64(0x10), history.OldestBlock)
+	assert.Equal(t, []*big.Int{big.NewInt(1000000000), big.NewInt(1000000001)}, history.BaseFee)
+	assert.Equal(t, []float64{0.5}, history.GasUsedRatio)
+	assert.Equal(t, [][]*big.Int{{big.NewInt(1), big.NewInt(2)}}, history.Reward)
+}
+
+func TestEthGetProof(t *testing.T) {
+	s := testutil.NewTestServer(t, nil)
+	defer s.Close()
+
+	c, _ := NewClient(s.HTTPAddr())
+	defer c.Close()
+
+	receipt := s.Transfer(addr0, big.NewInt(1000))
+
+	block, err := c.Eth().GetBlockByNumber(ethgo.BlockNumber(receipt.BlockNumber), false)
+	assert.NoError(t, err)
+
+	proof, err := c.Eth().GetProof(addr0, []ethgo.Hash{{}}, ethgo.BlockNumber(receipt.BlockNumber))
+	assert.NoError(t, err)
+	assert.Equal(t, addr0, proof.Address)
+	assert.Len(t, proof.StorageProof, 1)
+
+	assert.NoError(t, trie.VerifyAccountProof(block.StateRoot, proof))
+}
+
+func TestEthSign(t *testing.T) {
+	s := testutil.NewTestServer(t, nil)
+	defer s.Close()
+
+	c, _ := NewClient(s.HTTPAddr())
+	defer c.Close()
+
+	msg := []byte("hello world")
+
+	sig, err := c.Eth().Sign(s.Account(0), msg)
+	assert.NoError(t, err)
+
+	// the node signs the personal message hash
+	addr, err := wallet.RecoverPersonalMessage(msg, sig)
+	assert.NoError(t, err)
+	assert.Equal(t, s.Account(0), addr)
+}
+
+func TestEthBlockParams(t *testing.T) {
+	s := testutil.NewMockServer()
+	defer s.Close()
+
+	paramsCh := make(chan string, 1)
+	handler := func(result interface{}) testutil.MockHandler {
+		return func(params []json.RawMessage) (interface{}, error) {
+			paramsCh <- string(params[len(params)-1])
+			return result, nil
+		}
+	}
+	s.Handle("eth_call", handler("0x"))
+	s.Handle("eth_getBalance", handler("0x1"))
+
+	c, _ := NewClient(s.HTTPAddr())
+	defer c.Close()
+
+	hash := ethgo.HexToHash("0x1")
+
+	cases := []struct {
+		block ethgo.BlockNumberOrHash
+		param string
+	}{
+		{ethgo.Latest, `"latest"`},
+		{ethgo.Safe, `"safe"`},
+		{ethgo.Finalized, `"finalized"`},
+		{ethgo.BlockNumber(10), `"0xa"`},
+		{hash, `"` + hash.String() + `"`},
+		{ethgo.BlockHashParam{Hash: hash, RequireCanonical: true}, `{"blockHash":"` + hash.String() + `","requireCanonical":true}`},
+	}
+	for _, c0 := range cases {
+		_, err := c.Eth().Call(&ethgo.CallMsg{To: &addr0}, c0.block)
+		assert.NoError(t, err)
+		assert.Equal(t, c0.param, <-paramsCh)
+
+		_, err = c.Eth().GetBalance(addr0, c0.block)
+		assert.NoError(t, err)
+		assert.Equal(t, c0.param, <-paramsCh)
+	}
+}
+
+func TestEthFeeHistory_Null(t *testing.T) {
+	s := testutil.NewMockServer()
+	defer s.Close()
+
+	s.HandleResult("eth_feeHistory", nil)
+
+	c, _ := NewClient(s.HTTPAddr())
+	defer c.Close()
+
+	history, err := c.Eth().FeeHistory(2, ethgo.Latest, nil)
+	assert.Error(t, err)
+	assert.Nil(t, history)
+}
+
+func TestEthSendTransaction_DynamicFee(t *testing.T) {
+	s := testutil.NewMockServer()
+	defer s.Close()
+
+	paramsCh := make(chan map[string]interface{}, 1)
+	s.Handle("eth_sendTransaction", func(params []json.RawMessage) (interface{}, error) {
+		var obj map[string]interface{}
+		if err := json.Unmarshal(params[0], &obj); err != nil {
+			return nil, err
+		}
+		paramsCh <- obj
+		return ethgo.Hash{0x1}, nil
+	})
+
+	c, _ := NewClient(s.HTTPAddr())
+	defer c.Close()
+
+	_, err := c.Eth().SendTransaction(&ethgo.Transaction{
+		Type:                 ethgo.TransactionDynamicFee,
+		MaxFeePerGas:         big.NewInt(100),
+		MaxPriorityFeePerGas: big.NewInt(2),
+	})
+	assert.NoError(t, err)
+
+	obj := <-paramsCh
+	assert.NotContains(t, obj, "gasPrice")
+	assert.Equal(t, "0x64", obj["maxFeePerGas"])
+}
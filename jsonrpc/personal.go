@@ -0,0 +1,45 @@
+package jsonrpc
+
+import (
+	"context"
+
+	"github.com/git-yongge/ethgo"
+)
+
+// Personal is the personal namespace
+type Personal struct {
+	c *Client
+}
+
+// Personal returns the reference to the personal namespace
+func (c *Client) Personal() *Personal {
+	return c.endpoints.p
+}
+
+// Sign signs the data with the key of the account in the node unlocked with
+// the password (personal_sign). The node signs the EIP-191 personal message
+// hash of the data and v is 27 or 28.
+func (p *Personal) Sign(data []byte, addr ethgo.Address, password string) ([]byte, error) {
+	return p.SignContext(context.Background(), data, addr, password)
+}
+
+// SignContext signs the data with the key of the account in the node (personal_sign).
+func (p *Personal) SignContext(ctx context.Context, data []byte, addr ethgo.Address, password string) ([]byte, error) {
+	var out string
+	if err := p.c.CallContext(ctx, "personal_sign", &out, encodeToHex(data), addr, password); err != nil {
+		return nil, err
+	}
+	return parseHexBytes(out)
+}
+
+// EcRecover returns the address that signed the data with personal_sign
+func (p *Personal) EcRecover(data, signature []byte) (ethgo.Address, error) {
+	return p.EcRecoverContext(context.Background(), data, signature)
+}
+
+// EcRecoverContext returns the address that signed the data with personal_sign
+func (p *Personal) EcRecoverContext(ctx context.Context, data, signature []byte) (ethgo.Address, error) {
+	var addr ethgo.Address
+	err := p.c.CallContext(ctx, "personal_ecRecover", &addr, encodeToHex(data), encodeToHex(signature))
+	return addr, err
+}
@@ -0,0 +1,51 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/testutil"
+	"github.com/git-yongge/ethgo/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersonalSign(t *testing.T) {
+	s := testutil.NewMockServer()
+	defer s.Close()
+
+	key, err := wallet.GenerateKey()
+	assert.NoError(t, err)
+
+	// the node signs the personal message hash of the data
+	s.Handle("personal_sign", func(params []json.RawMessage) (interface{}, error) {
+		var data, password string
+		var addr ethgo.Address
+		assert.NoError(t, json.Unmarshal(params[0], &data))
+		assert.NoError(t, json.Unmarshal(params[1], &addr))
+		assert.NoError(t, json.Unmarshal(params[2], &password))
+
+		assert.Equal(t, "0x68656c6c6f", data)
+		assert.Equal(t, key.Address(), addr)
+		assert.Equal(t, "secret", password)
+
+		sig, err := key.SignPersonalMessage([]byte("hello"))
+		assert.NoError(t, err)
+		return encodeToHex(sig), nil
+	})
+	s.HandleResult("personal_ecRecover", key.Address())
+
+	c, _ := NewClient(s.HTTPAddr())
+	defer c.Close()
+
+	sig, err := c.Personal().Sign([]byte("hello"), key.Address(), "secret")
+	assert.NoError(t, err)
+
+	addr, err := wallet.RecoverPersonalMessage([]byte("hello"), sig)
+	assert.NoError(t, err)
+	assert.Equal(t, key.Address(), addr)
+
+	addr, err = c.Personal().EcRecover([]byte("hello"), sig)
+	assert.NoError(t, err)
+	assert.Equal(t, key.Address(), addr)
+}
@@ -1,8 +1,11 @@
 package jsonrpc
 
 import (
+	"encoding/json"
 	"fmt"
+	"sync"
 
+	"github.com/git-yongge/ethgo"
 	"github.com/git-yongge/ethgo/jsonrpc/transport"
 )
 
@@ -12,12 +15,242 @@ func (c *Client) SubscriptionEnabled() bool {
 	return ok
 }
 
-// Subscribe starts a new subscription
-func (c *Client) Subscribe(method string, callback func(b []byte)) (func() error, error) {
+// Subscribe starts a new subscription with optional params
+func (c *Client) Subscribe(method string, callback func(b []byte), params ...interface{}) (func() error, error) {
 	pub, ok := c.transport.(transport.PubSubTransport)
 	if !ok {
 		return nil, fmt.Errorf("Transport does not support the subscribe method")
 	}
-	close, err := pub.Subscribe(method, callback)
+	close, err := pub.Subscribe(method, callback, params...)
 	return close, err
 }
+
+// subscription is the state shared by the typed subscriptions
+type subscription struct {
+	// ErrCh receives at most one error, the one that ends the subscription: a
+	// message that cannot be decoded or the connection drops and it is not
+	// restored. The channels are closed after it. Unsubscribe closes them
+	// without an error.
+	ErrCh chan error
+
+	cancel  func() error
+	started chan struct{}
+	closeCh chan struct{}
+	closeFn func()
+
+	// lock guards the sends on the channels once they are closed
+	lock      sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+}
+
+func newSubscription(closeFn func()) *subscription {
+	return &subscription{
+		ErrCh:   make(chan error, 1),
+		started: make(chan struct{}),
+		closeCh: make(chan struct{}),
+		closeFn: closeFn,
+	}
+}
+
+func (s *subscription) start(c *Client, method string, callback func(b []byte), params ...interface{}) error {
+	defer close(s.started)
+
+	var cancel func() error
+	var err error
+
+	if pub, ok := c.transport.(transport.PubSubErrTransport); ok {
+		cancel, err = pub.SubscribeWithError(method, callback, s.end, params...)
+	} else {
+		cancel, err = c.Subscribe(method, callback, params...)
+	}
+	if err != nil {
+		return err
+	}
+	s.cancel = cancel
+	return nil
+}
+
+// emit sends the value with send unless the subscription is closed
+func (s *subscription) emit(send func()) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return
+	}
+	send()
+}
+
+// end sends the error that ended the subscription in the transport and closes the channels
+func (s *subscription) end(err error) {
+	s.closeOnce.Do(func() {
+		s.ErrCh <- err
+		s.close()
+	})
+}
+
+// fail ends the subscription with a message that cannot be decoded
+func (s *subscription) fail(err error) {
+	s.closeOnce.Do(func() {
+		s.ErrCh <- err
+		s.close()
+
+		// the transport is still subscribed. The callback may run before
+		// start returns and the transport may call it from its read loop,
+		// so the cancel cannot be waited for here.
+		go func() {
+			<-s.started
+			if s.cancel != nil {
+				s.cancel()
+			}
+		}()
+	})
+}
+
+func (s *subscription) close() {
+	close(s.closeCh)
+
+	s.lock.Lock()
+	s.closed = true
+	close(s.ErrCh)
+	s.closeFn()
+	s.lock.Unlock()
+}
+
+// Unsubscribe ends the subscription and closes its channels
+func (s *subscription) Unsubscribe() error {
+	var err error
+	s.closeOnce.Do(func() {
+		err = s.cancel()
+		s.close()
+	})
+	return err
+}
+
+// BlockSubscription is a subscription to the headers of the new blocks
+type BlockSubscription struct {
+	*subscription
+
+	// BlockCh receives the headers of the new blocks
+	BlockCh chan *ethgo.Block
+}
+
+// SubscribeNewHeads subscribes to the headers of the blocks added to the chain
+func (c *Client) SubscribeNewHeads() (*BlockSubscription, error) {
+	sub := &BlockSubscription{
+		BlockCh: make(chan *ethgo.Block),
+	}
+	sub.subscription = newSubscription(func() {
+		close(sub.BlockCh)
+	})
+
+	callback := func(b []byte) {
+		block := new(ethgo.Block)
+		if err := block.UnmarshalJSON(b); err != nil {
+			sub.fail(err)
+			return
+		}
+		sub.emit(func() {
+			select {
+			case sub.BlockCh <- block:
+			case <-sub.closeCh:
+			}
+		})
+	}
+	if err := sub.start(c, "newHeads", callback); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// LogSubscription is a subscription to the logs of a filter
+type LogSubscription struct {
+	*subscription
+
+	// LogCh receives the logs that match the filter
+	LogCh chan *ethgo.Log
+}
+
+// SubscribeLogs subscribes to the logs of the new blocks that match the filter.
+// The logs of a block removed by a reorg are sent again with Removed set.
+func (c *Client) SubscribeLogs(filter *ethgo.LogFilter) (*LogSubscription, error) {
+	if filter == nil {
+		filter = &ethgo.LogFilter{}
+	}
+
+	sub := &LogSubscription{
+		LogCh: make(chan *ethgo.Log),
+	}
+	sub.subscription = newSubscription(func() {
+		close(sub.LogCh)
+	})
+
+	callback := func(b []byte) {
+		log := new(ethgo.Log)
+		if err := log.UnmarshalJSON(b); err != nil {
+			sub.fail(err)
+			return
+		}
+		sub.emit(func() {
+			select {
+			case sub.LogCh <- log:
+			case <-sub.closeCh:
+			}
+		})
+	}
+	if err := sub.start(c, "logs", callback, filter); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// TransactionSubscription is a subscription to the pending transactions
+type TransactionSubscription struct {
+	*subscription
+
+	// TxCh receives the pending transactions. Only the hash
+	// is set unless the subscription includes full transactions
+	TxCh chan *ethgo.Transaction
+}
+
+// SubscribePendingTransactions subscribes to the transactions added to the pending pool
+// of the node. With full, the node sends the whole transactions instead of their hashes.
+func (c *Client) SubscribePendingTransactions(full bool) (*TransactionSubscription, error) {
+	sub := &TransactionSubscription{
+		TxCh: make(chan *ethgo.Transaction),
+	}
+	sub.subscription = newSubscription(func() {
+		close(sub.TxCh)
+	})
+
+	callback := func(b []byte) {
+		txn := new(ethgo.Transaction)
+		if full {
+			if err := txn.UnmarshalJSON(b); err != nil {
+				sub.fail(err)
+				return
+			}
+		} else {
+			if err := json.Unmarshal(b, &txn.Hash); err != nil {
+				sub.fail(err)
+				return
+			}
+		}
+		sub.emit(func() {
+			select {
+			case sub.TxCh <- txn:
+			case <-sub.closeCh:
+			}
+		})
+	}
+
+	var params []interface{}
+	if full {
+		params = append(params, true)
+	}
+	if err := sub.start(c, "newPendingTransactions", callback, params...); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
@@ -1,11 +1,16 @@
 package jsonrpc
 
 import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/jsonrpc/transport"
 	"github.com/git-yongge/ethgo/testutil"
 	"github.com/stretchr/testify/assert"
 )
@@ -70,3 +75,220 @@ func TestSubscribeNewHead(t *testing.T) {
 		assert.Error(t, cancel())
 	})
 }
+
+// subscribeServer is a mock server that accepts the subscriptions and
+// sends the params of each eth_subscribe call on the returned channel
+func subscribeServer(t *testing.T) (*testutil.MockServer, chan []json.RawMessage) {
+	paramsCh := make(chan []json.RawMessage, 1)
+
+	s := testutil.NewMockServer()
+	s.Handle("eth_subscribe", func(params []json.RawMessage) (interface{}, error) {
+		paramsCh <- params
+		return "0x1", nil
+	})
+	s.HandleResult("eth_unsubscribe", true)
+	return s, paramsCh
+}
+
+func TestSubscribeNewHeads_Typed(t *testing.T) {
+	s, paramsCh := subscribeServer(t)
+	defer s.Close()
+
+	c, err := NewClient(s.WsAddr())
+	assert.NoError(t, err)
+	defer c.Close()
+
+	sub, err := c.SubscribeNewHeads()
+	assert.NoError(t, err)
+	assert.Equal(t, []json.RawMessage{json.RawMessage(`"newHeads"`)}, <-paramsCh)
+
+	// the blocks are received in order
+	for i := 1; i <= 10; i++ {
+		assert.NoError(t, s.Notify("0x1", mockHeader(i)))
+	}
+	for i := 1; i <= 10; i++ {
+		block := <-sub.BlockCh
+		assert.Equal(t, uint64(i), block.Number)
+		assert.Equal(t, ethgo.Hash{byte(i)}, block.Hash)
+	}
+
+	assert.NoError(t, sub.Unsubscribe())
+	assert.NoError(t, sub.Unsubscribe())
+
+	// the channels are closed without an error
+	_, ok := <-sub.BlockCh
+	assert.False(t, ok)
+	_, ok = <-sub.ErrCh
+	assert.False(t, ok)
+}
+
+func TestSubscribeNewHeads_DecodeError(t *testing.T) {
+	s, paramsCh := subscribeServer(t)
+	defer s.Close()
+
+	c, err := NewClient(s.WsAddr())
+	assert.NoError(t, err)
+	defer c.Close()
+
+	sub, err := c.SubscribeNewHeads()
+	assert.NoError(t, err)
+	<-paramsCh
+
+	// a message that cannot be decoded ends the subscription
+	assert.NoError(t, s.Notify("0x1", "a"))
+	assert.NoError(t, s.Notify("0x1", "b"))
+
+	_, ok := <-sub.BlockCh
+	assert.False(t, ok)
+
+	assert.Error(t, <-sub.ErrCh)
+	_, ok = <-sub.ErrCh
+	assert.False(t, ok)
+
+	// and it is cancelled in the node
+	assert.Eventually(t, func() bool {
+		return s.Calls("eth_unsubscribe") == 1
+	}, time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, sub.Unsubscribe())
+}
+
+func TestSubscribeNewHeads_Ends(t *testing.T) {
+	s, paramsCh := subscribeServer(t)
+
+	c, err := NewClient(s.WsAddr())
+	assert.NoError(t, err)
+	defer c.Close()
+
+	sub, err := c.SubscribeNewHeads()
+	assert.NoError(t, err)
+	<-paramsCh
+
+	// the connection drops and the client does not reconnect
+	assert.NoError(t, s.Notify("0x1", mockHeader(1)))
+	s.Close()
+
+	assert.Equal(t, uint64(1), (<-sub.BlockCh).Number)
+
+	var dErr *transport.DisconnectedError
+	assert.True(t, errors.As(<-sub.ErrCh, &dErr))
+
+	_, ok := <-sub.BlockCh
+	assert.False(t, ok)
+	_, ok = <-sub.ErrCh
+	assert.False(t, ok)
+
+	assert.NoError(t, sub.Unsubscribe())
+}
+
+func TestSubscribeLogs(t *testing.T) {
+	s, paramsCh := subscribeServer(t)
+	defer s.Close()
+
+	c, err := NewClient(s.WsAddr())
+	assert.NoError(t, err)
+	defer c.Close()
+
+	addr := ethgo.Address{0x1}
+	sub, err := c.SubscribeLogs(&ethgo.LogFilter{Address: []ethgo.Address{addr}})
+	assert.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	params := <-paramsCh
+	assert.Len(t, params, 2)
+	assert.JSONEq(t, `"logs"`, string(params[0]))
+	assert.JSONEq(t, `{"address": "`+addr.String()+`", "topics": []}`, string(params[1]))
+
+	assert.NoError(t, s.Notify("0x1", map[string]interface{}{
+		"address":          addr,
+		"blockNumber":      "0x5",
+		"blockHash":        ethgo.Hash{0x5},
+		"logIndex":         "0x0",
+		"transactionIndex": "0x0",
+		"transactionHash":  ethgo.Hash{},
+		"data":             "0x",
+		"topics":           []ethgo.Hash{},
+		"removed":          true,
+	}))
+	log := <-sub.LogCh
+	assert.Equal(t, addr, log.Address)
+	assert.Equal(t, uint64(5), log.BlockNumber)
+	assert.True(t, log.Removed)
+}
+
+func TestSubscribePendingTransactions(t *testing.T) {
+	s, paramsCh := subscribeServer(t)
+	defer s.Close()
+
+	c, err := NewClient(s.WsAddr())
+	assert.NoError(t, err)
+	defer c.Close()
+
+	t.Run("hashes", func(t *testing.T) {
+		sub, err := c.SubscribePendingTransactions(false)
+		assert.NoError(t, err)
+		defer sub.Unsubscribe()
+
+		assert.Len(t, <-paramsCh, 1)
+
+		hash := ethgo.Hash{0x1}
+		assert.NoError(t, s.Notify("0x1", hash))
+		assert.Equal(t, hash, (<-sub.TxCh).Hash)
+	})
+
+	t.Run("full", func(t *testing.T) {
+		sub, err := c.SubscribePendingTransactions(true)
+		assert.NoError(t, err)
+		defer sub.Unsubscribe()
+
+		params := <-paramsCh
+		assert.Len(t, params, 2)
+		assert.JSONEq(t, "true", string(params[1]))
+
+		hash := ethgo.Hash{0x2}
+		assert.NoError(t, s.Notify("0x1", &ethgo.Transaction{
+			Hash:  hash,
+			Nonce: 3,
+			Input: []byte{0x1},
+			Value: big.NewInt(1),
+			Gas:   21000,
+			V:     []byte{0x1},
+			R:     []byte{0x1},
+			S:     []byte{0x1},
+		}))
+		txn := <-sub.TxCh
+		assert.Equal(t, hash, txn.Hash)
+		assert.Equal(t, uint64(3), txn.Nonce)
+	})
+}
+
+func TestSubscribe_HTTP(t *testing.T) {
+	s, _ := subscribeServer(t)
+	defer s.Close()
+
+	c, err := NewClient(s.HTTPAddr())
+	assert.NoError(t, err)
+	defer c.Close()
+
+	_, err = c.SubscribeNewHeads()
+	assert.Error(t, err)
+}
+
+// mockHeader returns the header of the block i as sent by the node
+func mockHeader(i int) map[string]interface{} {
+	return map[string]interface{}{
+		"hash":             ethgo.Hash{byte(i)},
+		"parentHash":       ethgo.Hash{byte(i - 1)},
+		"sha3Uncles":       ethgo.Hash{},
+		"transactionsRoot": ethgo.Hash{},
+		"stateRoot":        ethgo.Hash{},
+		"receiptsRoot":     ethgo.Hash{},
+		"miner":            ethgo.Address{},
+		"number":           "0x" + strconv.FormatInt(int64(i), 16),
+		"gasLimit":         "0x0",
+		"gasUsed":          "0x0",
+		"timestamp":        "0x0",
+		"difficulty":       "0x0",
+		"extraData":        "0x",
+	}
+}
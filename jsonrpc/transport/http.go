@@ -1,24 +1,30 @@
 package transport
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"io/ioutil"
+	"net/http"
 
 	"github.com/git-yongge/ethgo/jsonrpc/codec"
-	"github.com/valyala/fasthttp"
 )
 
 // HTTP is an http transport
 type HTTP struct {
-	addr    string
-	client  *fasthttp.Client
-	headers map[string]string
+	addr      string
+	client    *http.Client
+	transport *http.Transport
+	headers   map[string]string
 }
 
 func newHTTP(addr string, headers map[string]string) *HTTP {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
 	return &HTTP{
-		addr:    addr,
-		client:  &fasthttp.Client{},
-		headers: headers,
+		addr:      addr,
+		client:    &http.Client{Transport: transport},
+		transport: transport,
+		headers:   headers,
 	}
 }
 
@@ -29,6 +35,11 @@ func (h *HTTP) Close() error {
 
 // Call implements the transport interface
 func (h *HTTP) Call(method string, out interface{}, params ...interface{}) error {
+	return h.CallContext(context.Background(), method, out, params...)
+}
+
+// CallContext implements the transport interface
+func (h *HTTP) CallContext(ctx context.Context, method string, out interface{}, params ...interface{}) error {
 	// Encode json-rpc request
 	request := codec.Request{
 		JsonRPC: "2.0",
@@ -46,27 +57,14 @@ func (h *HTTP) Call(method string, out interface{}, params ...interface{}) error
 		return err
 	}
 
-	req := fasthttp.AcquireRequest()
-	res := fasthttp.AcquireResponse()
-
-	defer fasthttp.ReleaseRequest(req)
-	defer fasthttp.ReleaseResponse(res)
-
-	req.SetRequestURI(h.addr)
-	req.Header.SetMethod("POST")
-	req.Header.SetContentType("application/json")
-	for k, v := range h.headers {
-		req.Header.Add(k, v)
-	}
-	req.SetBody(raw)
-
-	if err := h.client.Do(req, res); err != nil {
+	body, err := h.do(ctx, raw)
+	if err != nil {
 		return err
 	}
 
 	// Decode json-rpc response
 	var response codec.Response
-	if err := json.Unmarshal(res.Body(), &response); err != nil {
+	if err := json.Unmarshal(body, &response); err != nil {
 		return err
 	}
 	if response.Error != nil {
@@ -79,7 +77,79 @@ func (h *HTTP) Call(method string, out interface{}, params ...interface{}) error
 	return nil
 }
 
+// BatchCall implements the transport interface
+func (h *HTTP) BatchCall(ctx context.Context, elems []*BatchElem) error {
+	if len(elems) == 0 {
+		return nil
+	}
+
+	requests, err := newBatchRequests(elems)
+	if err != nil {
+		return err
+	}
+	for indx := range requests {
+		requests[indx].ID = uint64(indx + 1)
+	}
+	raw, err := json.Marshal(requests)
+	if err != nil {
+		return err
+	}
+
+	body, err := h.do(ctx, raw)
+	if err != nil {
+		return err
+	}
+
+	var responses []*codec.Response
+	if err := json.Unmarshal(body, &responses); err != nil {
+		// the whole batch might have been rejected with a single error object
+		var response codec.Response
+		if err2 := json.Unmarshal(body, &response); err2 == nil && response.Error != nil {
+			return response.Error
+		}
+		return err
+	}
+
+	// responses can arrive in any order, route them by id
+	for _, elem := range elems {
+		elem.Error = errBatchMissing
+	}
+	for _, response := range responses {
+		if response.ID == 0 || response.ID > uint64(len(elems)) {
+			continue
+		}
+		elems[response.ID-1].setResult(response)
+	}
+	return nil
+}
+
+// do posts the raw request and returns the body of the response. The
+// connection is closed if the context is done before the response arrives.
+func (h *HTTP) do(ctx context.Context, raw []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.addr, bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range h.headers {
+		req.Header.Add(k, v)
+	}
+
+	res, err := h.client.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	// the body is decoded even for error status codes since
+	// the nodes return the jsonrpc errors with them
+	return ioutil.ReadAll(res.Body)
+}
+
 // SetMaxConnsPerHost sets the maximum number of connections that can be established with a host
 func (h *HTTP) SetMaxConnsPerHost(count int) {
-	h.client.MaxConnsPerHost = count
+	h.transport.MaxConnsPerHost = count
 }
@@ -1,8 +1,13 @@
 package transport
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"os"
 	"strings"
+
+	"github.com/git-yongge/ethgo/jsonrpc/codec"
 )
 
 // Transport is an inteface for transport methods to send jsonrpc requests
@@ -10,6 +15,13 @@ type Transport interface {
 	// Call makes a jsonrpc request
 	Call(method string, out interface{}, params ...interface{}) error
 
+	// CallContext makes a jsonrpc request that can be cancelled with the context
+	CallContext(ctx context.Context, method string, out interface{}, params ...interface{}) error
+
+	// BatchCall sends all the requests in a single jsonrpc batch. The error of
+	// each individual call is set in its element
+	BatchCall(ctx context.Context, elems []*BatchElem) error
+
 	// SetMaxConnsPerHost sets the maximum number of connections that can be established with a host
 	SetMaxConnsPerHost(count int)
 
@@ -17,10 +29,70 @@ type Transport interface {
 	Close() error
 }
 
+// BatchElem is a single call of a jsonrpc batch request
+type BatchElem struct {
+	// Method is the jsonrpc method to call
+	Method string
+
+	// Params are the parameters of the call
+	Params []interface{}
+
+	// Result is where the result of the call is decoded
+	Result interface{}
+
+	// Error is the error of this specific call (if any) once the batch is sent
+	Error error
+}
+
+func newBatchRequests(elems []*BatchElem) ([]codec.Request, error) {
+	requests := make([]codec.Request, len(elems))
+	for indx, elem := range elems {
+		request := codec.Request{
+			JsonRPC: "2.0",
+			Method:  elem.Method,
+		}
+		if len(elem.Params) > 0 {
+			data, err := json.Marshal(elem.Params)
+			if err != nil {
+				return nil, err
+			}
+			request.Params = data
+		}
+		requests[indx] = request
+	}
+	return requests, nil
+}
+
+// errBatchMissing is the error of a batch element without a response
+var errBatchMissing = fmt.Errorf("response not found in batch")
+
+// setResult decodes the response of a call into its batch element
+func (b *BatchElem) setResult(response *codec.Response) {
+	b.Error = nil
+	if response.Error != nil {
+		b.Error = response.Error
+		return
+	}
+	if b.Result != nil {
+		b.Error = json.Unmarshal(response.Result, b.Result)
+	}
+}
+
 // PubSubTransport is a transport that allows subscriptions
 type PubSubTransport interface {
-	// Subscribe starts a subscription to a new event
-	Subscribe(method string, callback func(b []byte)) (func() error, error)
+	// Subscribe starts a subscription to a new event with optional params.
+	// The callback gets the messages of the subscription in order.
+	Subscribe(method string, callback func(b []byte), params ...interface{}) (func() error, error)
+}
+
+// PubSubErrTransport is a PubSubTransport that reports why a subscription ends
+type PubSubErrTransport interface {
+	PubSubTransport
+
+	// SubscribeWithError is like Subscribe but onErr is called once, after the queued
+	// messages, if the subscription ends without being cancelled (i.e. the connection
+	// drops and it is not restored or the subscription falls behind its messages).
+	SubscribeWithError(method string, callback func(b []byte), onErr func(err error), params ...interface{}) (func() error, error)
 }
 
 const (
@@ -28,10 +100,11 @@ const (
 	wssPrefix = "wss://"
 )
 
-// NewTransport creates a new transport object
-func NewTransport(url string, headers map[string]string) (Transport, error) {
+// NewTransport creates a new transport object. The websocket
+// options are only used by websocket endpoints.
+func NewTransport(url string, headers map[string]string, opts ...WebsocketOption) (Transport, error) {
 	if strings.HasPrefix(url, wsPrefix) || strings.HasPrefix(url, wssPrefix) {
-		t, err := newWebsocket(url, headers)
+		t, err := newWebsocket(url, headers, opts...)
 		if err != nil {
 			return nil, err
 		}
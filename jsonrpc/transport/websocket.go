@@ -1,6 +1,7 @@
 package transport
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -12,24 +13,132 @@ import (
 	"github.com/gorilla/websocket"
 )
 
-func newWebsocket(url string, headers map[string]string) (Transport, error) {
+// WebsocketConfig is the configuration of the websocket transport
+type WebsocketConfig struct {
+	// Reconnect enables to reconnect when the connection drops. The active
+	// subscriptions are restored once the connection is established again
+	Reconnect bool
+
+	// MinBackoff and MaxBackoff are the bounds of the wait between reconnection
+	// attempts. The wait doubles after every failed attempt and it is never
+	// shorter than 100ms
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// PingInterval is the interval between keepalive pings, zero disables them.
+	// The connection is considered lost if no pong arrives within PongTimeout
+	PingInterval time.Duration
+	PongTimeout  time.Duration
+
+	// ReadLimit is the maximum size in bytes of a message, zero means no limit
+	ReadLimit int64
+
+	// OnStateChange is called when the state of the connection changes. The
+	// changes are notified in order from a goroutine of their own, the next
+	// change waits until the call returns
+	OnStateChange func(state ConnState, err error)
+}
+
+// DefaultWebsocketConfig returns the default websocket config. The transport does
+// not reconnect nor send pings unless they are enabled, the backoff and the pong
+// timeout are the values used once they are.
+func DefaultWebsocketConfig() *WebsocketConfig {
+	return &WebsocketConfig{
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+		PongTimeout: 10 * time.Second,
+	}
+}
+
+// WebsocketOption is an option of the websocket transport
+type WebsocketOption func(*WebsocketConfig)
+
+// ConnState is the state of the connection of a websocket transport
+type ConnState int
+
+const (
+	// ConnStateConnected happens when the connection is established again
+	// after a disconnection and the subscriptions are restored
+	ConnStateConnected ConnState = iota
+	// ConnStateDisconnected happens when the connection drops
+	ConnStateDisconnected
+	// ConnStateClosed happens when the transport is closed
+	ConnStateClosed
+)
+
+func (c ConnState) String() string {
+	switch c {
+	case ConnStateConnected:
+		return "connected"
+	case ConnStateDisconnected:
+		return "disconnected"
+	case ConnStateClosed:
+		return "closed"
+	default:
+		return fmt.Sprintf("ConnState(%d)", int(c))
+	}
+}
+
+// DisconnectedError is the error of the calls in flight when the connection
+// drops and of the calls made while the transport is not connected
+type DisconnectedError struct {
+	Err error
+}
+
+func (d *DisconnectedError) Error() string {
+	return fmt.Sprintf("connection lost: %v", d.Err)
+}
+
+func (d *DisconnectedError) Unwrap() error {
+	return d.Err
+}
+
+// ErrClosed happens when the transport is closed
+var ErrClosed = fmt.Errorf("transport closed")
+
+// ErrSubscriptionOverflow ends a subscription whose callback falls more than
+// maxSubscriptionQueue messages behind the node
+var ErrSubscriptionOverflow = fmt.Errorf("subscription queue overflow")
+
+// maxSubscriptionQueue is the number of messages queued for the callback of a subscription
+const maxSubscriptionQueue = 4096
+
+var errNotConnected = fmt.Errorf("not connected")
+
+func newWebsocket(url string, headers map[string]string, opts ...WebsocketOption) (Transport, error) {
+	config := DefaultWebsocketConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+
 	wsHeaders := http.Header{}
 	for k, v := range headers {
 		wsHeaders.Add(k, v)
 	}
-	wsConn, _, err := websocket.DefaultDialer.Dial(url, wsHeaders)
+	dial := func() (Codec, error) {
+		wsConn, _, err := websocket.DefaultDialer.Dial(url, wsHeaders)
+		if err != nil {
+			return nil, err
+		}
+		return newWebsocketCodec(wsConn, config), nil
+	}
+
+	codec, err := dial()
 	if err != nil {
 		return nil, err
 	}
-	codec := &websocketCodec{
-		conn: wsConn,
+	if !config.Reconnect {
+		dial = nil
 	}
-	return newStream(codec)
+	return newReconnectStream(codec, dial, config)
 }
 
 // ErrTimeout happens when the websocket requests times out
 var ErrTimeout = fmt.Errorf("timeout")
 
+// defaultCallTimeout is the timeout for calls whose context does not have a deadline
+const defaultCallTimeout = 5 * time.Second
+
 type ackMessage struct {
 	buf []byte
 	err error
@@ -37,38 +146,239 @@ type ackMessage struct {
 
 type callback func(b []byte, err error)
 
+type subscription struct {
+	id       string
+	method   string
+	params   []interface{}
+	callback func(b []byte)
+	onErr    func(err error)
+
+	// queue of messages delivered in order to the callback
+	lock     sync.Mutex
+	queue    [][]byte
+	closed   bool
+	err      error
+	notifyCh chan struct{}
+}
+
+func (s *subscription) notify() {
+	select {
+	case s.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+// push queues the message, it returns false if the queue is full
+func (s *subscription) push(b []byte) bool {
+	s.lock.Lock()
+	if len(s.queue) >= maxSubscriptionQueue {
+		s.lock.Unlock()
+		return false
+	}
+	s.queue = append(s.queue, b)
+	s.lock.Unlock()
+
+	s.notify()
+	return true
+}
+
+func (s *subscription) close() {
+	s.closeWithErr(nil)
+}
+
+// closeWithErr ends the subscription. With an error, the queued messages
+// are delivered before the error is reported, unless the queue overflowed.
+func (s *subscription) closeWithErr(err error) {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return
+	}
+	s.closed = true
+	s.err = err
+	if err == ErrSubscriptionOverflow {
+		s.queue = nil
+	}
+	s.lock.Unlock()
+
+	s.notify()
+}
+
+// run calls the callback with the queued messages until the subscription is closed
+func (s *subscription) run() {
+	for {
+		s.lock.Lock()
+		if s.closed && s.err == nil {
+			// cancelled
+			s.lock.Unlock()
+			return
+		}
+		if len(s.queue) == 0 {
+			closed, err := s.closed, s.err
+			s.lock.Unlock()
+
+			if closed {
+				if s.onErr != nil {
+					s.onErr(err)
+				}
+				return
+			}
+			<-s.notifyCh
+			continue
+		}
+		b := s.queue[0]
+		s.queue = s.queue[1:]
+		s.lock.Unlock()
+
+		s.callback(b)
+	}
+}
+
+type stateChange struct {
+	state ConnState
+	err   error
+}
+
+// stateQueue notifies the changes of state in the order they are
+// pushed from a goroutine of its own
+type stateQueue struct {
+	onChange func(state ConnState, err error)
+
+	lock     sync.Mutex
+	queue    []stateChange
+	stopped  bool
+	notifyCh chan struct{}
+}
+
+func newStateQueue(onChange func(state ConnState, err error)) *stateQueue {
+	q := &stateQueue{
+		onChange: onChange,
+		notifyCh: make(chan struct{}, 1),
+	}
+	if onChange != nil {
+		go q.run()
+	}
+	return q
+}
+
+func (q *stateQueue) notify() {
+	select {
+	case q.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+func (q *stateQueue) push(state ConnState, err error) {
+	if q.onChange == nil {
+		return
+	}
+	q.lock.Lock()
+	q.queue = append(q.queue, stateChange{state: state, err: err})
+	q.lock.Unlock()
+
+	q.notify()
+}
+
+// stop ends the goroutine once the queued changes are notified
+func (q *stateQueue) stop() {
+	q.lock.Lock()
+	q.stopped = true
+	q.lock.Unlock()
+
+	q.notify()
+}
+
+func (q *stateQueue) run() {
+	for {
+		q.lock.Lock()
+		if len(q.queue) == 0 {
+			stopped := q.stopped
+			q.lock.Unlock()
+
+			if stopped {
+				return
+			}
+			<-q.notifyCh
+			continue
+		}
+		c := q.queue[0]
+		q.queue = q.queue[1:]
+		q.lock.Unlock()
+
+		q.onChange(c.state, c.err)
+	}
+}
+
 type stream struct {
-	seq   uint64
-	codec Codec
+	seq uint64
+
+	// connLock guards the codec of the current connection
+	connLock  sync.RWMutex
+	codec     Codec
+	connected bool
+
+	// dial opens a new connection, the stream does not reconnect if it is nil
+	dial   func() (Codec, error)
+	config *WebsocketConfig
 
 	// call handlers
 	handlerLock sync.Mutex
 	handler     map[uint64]callback
 
-	// subscriptions
+	// batches in flight, they fail if the node rejects a batch as a whole
+	batchLock sync.Mutex
+	batches   map[uint64]chan error
+
+	// subscriptions and their ids in the current connection
 	subsLock sync.Mutex
-	subs     map[string]func(b []byte)
+	subs     map[*subscription]struct{}
+	subIDs   map[string]*subscription
+
+	// resubLock runs the passes that restore the subscriptions one at a time
+	resubLock sync.Mutex
 
-	closeCh chan struct{}
-	timer   *time.Timer
+	// states are pushed while connLock is held so that they are
+	// notified in the same order the connection changes
+	states *stateQueue
+
+	closeCh   chan struct{}
+	closeOnce sync.Once
 }
 
 func newStream(codec Codec) (*stream, error) {
+	return newReconnectStream(codec, nil, &WebsocketConfig{})
+}
+
+func newReconnectStream(codec Codec, dial func() (Codec, error), config *WebsocketConfig) (*stream, error) {
 	w := &stream{
-		codec:   codec,
-		closeCh: make(chan struct{}),
-		handler: map[uint64]callback{},
-		subs:    map[string]func(b []byte){},
+		codec:     codec,
+		connected: true,
+		dial:      dial,
+		config:    config,
+		closeCh:   make(chan struct{}),
+		handler:   map[uint64]callback{},
+		batches:   map[uint64]chan error{},
+		subs:      map[*subscription]struct{}{},
+		subIDs:    map[string]*subscription{},
+		states:    newStateQueue(config.OnStateChange),
 	}
 
-	go w.listen()
+	go w.run()
 	return w, nil
 }
 
 // Close implements the the transport interface
 func (s *stream) Close() error {
-	close(s.closeCh)
-	return s.codec.Close()
+	var err error
+	s.closeOnce.Do(func() {
+		s.connLock.Lock()
+		close(s.closeCh)
+		codec := s.codec
+		s.connLock.Unlock()
+
+		err = codec.Close()
+	})
+	return err
 }
 
 func (s *stream) incSeq() uint64 {
@@ -84,35 +394,260 @@ func (s *stream) isClosed() bool {
 	}
 }
 
-func (s *stream) listen() {
+// write writes the message in the current connection
+func (s *stream) write(b []byte) error {
+	if s.isClosed() {
+		return &DisconnectedError{Err: ErrClosed}
+	}
+
+	s.connLock.RLock()
+	codec, connected := s.codec, s.connected
+	s.connLock.RUnlock()
+
+	if !connected {
+		return &DisconnectedError{Err: errNotConnected}
+	}
+	if err := codec.Write(b); err != nil {
+		return &DisconnectedError{Err: err}
+	}
+	return nil
+}
+
+// isConnected returns true if the codec is the one of the current connection
+func (s *stream) isConnected(codec Codec) bool {
+	s.connLock.RLock()
+	defer s.connLock.RUnlock()
+
+	return s.connected && s.codec == codec
+}
+
+// setCodec sets the codec of a new connection. It returns false if
+// the stream was closed in the meantime.
+func (s *stream) setCodec(codec Codec) bool {
+	s.connLock.Lock()
+	defer s.connLock.Unlock()
+
+	if s.isClosed() {
+		codec.Close()
+		return false
+	}
+	s.codec = codec
+	s.connected = true
+	return true
+}
+
+// run reads the connection and, if the stream reconnects, opens a new
+// connection every time the current one drops
+func (s *stream) run() {
+	defer s.states.stop()
+
+	for {
+		s.connLock.RLock()
+		codec := s.codec
+		s.connLock.RUnlock()
+
+		err := s.listen(codec)
+
+		s.connLock.Lock()
+		s.connected = false
+		closed := s.isClosed()
+		if closed {
+			s.states.push(ConnStateClosed, nil)
+		} else {
+			s.states.push(ConnStateDisconnected, err)
+		}
+		s.connLock.Unlock()
+
+		if closed {
+			s.failHandlers(ErrClosed)
+			s.endSubscriptions(ErrClosed)
+			return
+		}
+
+		codec.Close()
+		s.failHandlers(err)
+		s.resetSubscriptions()
+
+		if s.dial == nil {
+			s.endSubscriptions(err)
+			return
+		}
+		if !s.reconnect() {
+			// closed while reconnecting
+			s.endSubscriptions(ErrClosed)
+			return
+		}
+
+		s.connLock.RLock()
+		codec = s.codec
+		s.connLock.RUnlock()
+
+		go s.resubscribe(codec)
+	}
+}
+
+// minBackoff is the shortest wait between reconnection attempts, it
+// avoids dialing in a tight loop with a zero backoff
+const minBackoff = 100 * time.Millisecond
+
+// reconnect dials with backoff until the connection is established
+// or the stream is closed
+func (s *stream) reconnect() bool {
+	backoff := s.config.MinBackoff
+	if backoff < minBackoff {
+		backoff = minBackoff
+	}
+	for {
+		select {
+		case <-time.After(backoff):
+		case <-s.closeCh:
+			return false
+		}
+
+		codec, err := s.dial()
+		if err == nil {
+			return s.setCodec(codec)
+		}
+
+		backoff *= 2
+		if backoff > s.config.MaxBackoff {
+			backoff = s.config.MaxBackoff
+		}
+		if backoff < minBackoff {
+			backoff = minBackoff
+		}
+	}
+}
+
+// failHandlers fails all the calls in flight
+func (s *stream) failHandlers(err error) {
+	s.handlerLock.Lock()
+	handlers := s.handler
+	s.handler = map[uint64]callback{}
+	s.handlerLock.Unlock()
+
+	for _, callback := range handlers {
+		callback(nil, &DisconnectedError{Err: err})
+	}
+}
+
+// endSubscriptions ends all the subscriptions once the stream stops
+func (s *stream) endSubscriptions(err error) {
+	s.subsLock.Lock()
+	subs := s.subs
+	s.subs = map[*subscription]struct{}{}
+	s.subIDs = map[string]*subscription{}
+	s.subsLock.Unlock()
+
+	for sub := range subs {
+		sub.closeWithErr(&DisconnectedError{Err: err})
+	}
+}
+
+// resetSubscriptions forgets the ids of the subscriptions in the dropped connection
+func (s *stream) resetSubscriptions() {
+	s.subsLock.Lock()
+	defer s.subsLock.Unlock()
+
+	for sub := range s.subs {
+		sub.id = ""
+	}
+	s.subIDs = map[string]*subscription{}
+}
+
+// resubscribe issues again the active subscriptions in the connection of the codec
+// and remaps them to the new subscription ids. Only one pass runs at a time and a
+// pass stops once its connection drops, the next connection starts a new one.
+func (s *stream) resubscribe(codec Codec) {
+	s.resubLock.Lock()
+	defer s.resubLock.Unlock()
+
+	s.subsLock.Lock()
+	subs := make([]*subscription, 0, len(s.subs))
+	for sub := range s.subs {
+		subs = append(subs, sub)
+	}
+	s.subsLock.Unlock()
+
+	for _, sub := range subs {
+		if !s.isConnected(codec) {
+			return
+		}
+
+		// skip the subscriptions removed or issued in this connection in the meantime
+		s.subsLock.Lock()
+		_, ok := s.subs[sub]
+		pending := ok && sub.id == ""
+		s.subsLock.Unlock()
+
+		if !pending {
+			continue
+		}
+		if err := s.subscribe(sub); err != nil {
+			if _, ok := err.(*DisconnectedError); ok {
+				// the subscriptions are issued again with the next connection
+				return
+			}
+			// the node does not accept the subscription anymore
+			s.subsLock.Lock()
+			delete(s.subs, sub)
+			s.subsLock.Unlock()
+			sub.closeWithErr(fmt.Errorf("failed to subscribe again: %w", err))
+		}
+	}
+
+	// the connection could drop right after the check, the state is pushed
+	// while connLock is held so that it is notified before the disconnection
+	s.connLock.RLock()
+	if s.connected && s.codec == codec {
+		s.states.push(ConnStateConnected, nil)
+	}
+	s.connLock.RUnlock()
+}
+
+func (s *stream) listen(conn Codec) error {
 	buf := []byte{}
 
 	for {
 		var err error
-		buf, err = s.codec.Read(buf[:0])
+		buf, err = conn.Read(buf[:0])
 		if err != nil {
-			if !s.isClosed() {
-				// log error
+			return err
+		}
+
+		if len(buf) != 0 && buf[0] == '[' {
+			// response to a batch request
+			var resps []codec.Response
+			if err = json.Unmarshal(buf, &resps); err != nil {
+				return err
 			}
-			return
+			for _, resp := range resps {
+				go s.handleMsg(resp)
+			}
+			continue
 		}
 
 		var resp codec.Response
 		if err = json.Unmarshal(buf, &resp); err != nil {
-			return
+			return err
 		}
 
 		if resp.ID != 0 {
-			go s.handleMsg(resp)
+			// the handlers do not block, the response is handled before the
+			// notifications that follow it (i.e. the ones of a new subscription)
+			s.handleMsg(resp)
 		} else {
 			// handle subscription
 			var respSub codec.Request
 			if err = json.Unmarshal(buf, &respSub); err != nil {
-				return
+				return err
 			}
 
 			if respSub.Method == "eth_subscription" {
-				go s.handleSubscription(respSub)
+				s.handleSubscription(respSub)
+			} else if resp.Error != nil {
+				// an error without id rejects a whole batch
+				s.handleBatchError(resp.Error)
 			}
 		}
 	}
@@ -121,19 +656,39 @@ func (s *stream) listen() {
 func (s *stream) handleSubscription(response codec.Request) {
 	var sub codec.Subscription
 	if err := json.Unmarshal(response.Params, &sub); err != nil {
-		panic(err)
+		return
 	}
 
 	s.subsLock.Lock()
-	callback, ok := s.subs[sub.ID]
+	record, ok := s.subIDs[sub.ID]
 	s.subsLock.Unlock()
 
 	if !ok {
 		return
 	}
 
-	// call the callback function
-	callback(sub.Result)
+	// the callback is called in order from the goroutine of the subscription
+	if !record.push(sub.Result) {
+		s.dropSubscription(record)
+	}
+}
+
+// dropSubscription ends a subscription whose callback fell behind its messages
+func (s *stream) dropSubscription(sub *subscription) {
+	s.subsLock.Lock()
+	delete(s.subs, sub)
+	delete(s.subIDs, sub.id)
+	id := sub.id
+	s.subsLock.Unlock()
+
+	sub.closeWithErr(ErrSubscriptionOverflow)
+
+	// the node keeps sending the messages otherwise. The call waits for
+	// a response and it cannot be made from the goroutine that reads it.
+	go func() {
+		var result bool
+		s.Call("eth_unsubscribe", &result, id)
+	}()
 }
 
 func (s *stream) handleMsg(response codec.Response) {
@@ -155,8 +710,11 @@ func (s *stream) handleMsg(response codec.Response) {
 	}
 }
 
-func (s *stream) setHandler(id uint64, ack chan *ackMessage) {
+func (s *stream) setHandler(id uint64, ack chan *ackMessage, onResult func(b []byte)) {
 	callback := func(b []byte, err error) {
+		if err == nil && onResult != nil {
+			onResult(b)
+		}
 		select {
 		case ack <- &ackMessage{b, err}:
 		default:
@@ -166,21 +724,32 @@ func (s *stream) setHandler(id uint64, ack chan *ackMessage) {
 	s.handlerLock.Lock()
 	s.handler[id] = callback
 	s.handlerLock.Unlock()
+}
 
-	s.timer = time.AfterFunc(5*time.Second, func() {
-		s.handlerLock.Lock()
-		delete(s.handler, id)
-		s.handlerLock.Unlock()
-
-		select {
-		case ack <- &ackMessage{nil, ErrTimeout}:
-		default:
-		}
-	})
+func (s *stream) removeHandler(id uint64) {
+	s.handlerLock.Lock()
+	delete(s.handler, id)
+	s.handlerLock.Unlock()
 }
 
 // Call implements the transport interface
 func (s *stream) Call(method string, out interface{}, params ...interface{}) error {
+	return s.CallContext(context.Background(), method, out, params...)
+}
+
+// CallContext implements the transport interface
+func (s *stream) CallContext(ctx context.Context, method string, out interface{}, params ...interface{}) error {
+	return s.call(ctx, method, out, nil, params...)
+}
+
+// call sends the request and decodes its result in out. If onResult is set, it is
+// called with the result from the goroutine that reads the connection before the
+// next message is read. It must not block.
+func (s *stream) call(ctx context.Context, method string, out interface{}, onResult func(b []byte), params ...interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	seq := s.incSeq()
 	request := codec.Request{
 		ID:     seq,
@@ -193,19 +762,37 @@ func (s *stream) Call(method string, out interface{}, params ...interface{}) err
 		}
 		request.Params = data
 	}
-
-	ack := make(chan *ackMessage)
-	s.setHandler(seq, ack)
-
 	raw, err := json.Marshal(request)
 	if err != nil {
 		return err
 	}
-	if err := s.codec.Write(raw); err != nil {
+
+	ack := make(chan *ackMessage, 1)
+	s.setHandler(seq, ack, onResult)
+	// the handler is already gone if the response arrived, otherwise
+	// make sure it does not outlive the call
+	defer s.removeHandler(seq)
+
+	if err := s.write(raw); err != nil {
 		return err
 	}
 
-	resp := <-ack
+	// calls without a deadline in the context use the default timeout
+	var timeoutCh <-chan time.Time
+	if _, ok := ctx.Deadline(); !ok {
+		timer := time.NewTimer(defaultCallTimeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
+	var resp *ackMessage
+	select {
+	case resp = <-ack:
+	case <-timeoutCh:
+		return ErrTimeout
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 	if resp.err != nil {
 		return resp.err
 	}
@@ -215,14 +802,138 @@ func (s *stream) Call(method string, out interface{}, params ...interface{}) err
 	return nil
 }
 
-func (s *stream) unsubscribe(id string) error {
+// BatchCall implements the transport interface
+func (s *stream) BatchCall(ctx context.Context, elems []*BatchElem) error {
+	if len(elems) == 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	requests, err := newBatchRequests(elems)
+	if err != nil {
+		return err
+	}
+
+	// every request in the batch has its own handler, the responses
+	// are routed by id to the index of the element
+	ack := make(chan *batchAckMessage, len(elems))
+	for indx := range requests {
+		seq := s.incSeq()
+		requests[indx].ID = seq
+		s.setBatchHandler(seq, indx, ack)
+	}
+	batchID := requests[0].ID
+	errCh := make(chan error, 1)
+
+	s.batchLock.Lock()
+	s.batches[batchID] = errCh
+	s.batchLock.Unlock()
+
+	defer func() {
+		for _, request := range requests {
+			s.removeHandler(request.ID)
+		}
+		s.batchLock.Lock()
+		delete(s.batches, batchID)
+		s.batchLock.Unlock()
+	}()
+
+	raw, err := json.Marshal(requests)
+	if err != nil {
+		return err
+	}
+	if err := s.write(raw); err != nil {
+		return err
+	}
+
+	var timeoutCh <-chan time.Time
+	if _, ok := ctx.Deadline(); !ok {
+		timer := time.NewTimer(defaultCallTimeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
+	for i := 0; i < len(elems); i++ {
+		select {
+		case resp := <-ack:
+			if resp.err != nil {
+				return resp.err
+			}
+			elems[resp.indx].setResult(resp.resp)
+		case err := <-errCh:
+			return err
+		case <-timeoutCh:
+			return ErrTimeout
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// handleBatchError fails the batches in flight with the error of the node. The
+// error does not say which batch was rejected so all of them fail.
+func (s *stream) handleBatchError(err *codec.ErrorObject) {
+	s.batchLock.Lock()
+	defer s.batchLock.Unlock()
+
+	for _, errCh := range s.batches {
+		select {
+		case errCh <- err:
+		default:
+		}
+	}
+}
+
+type batchAckMessage struct {
+	indx int
+	resp *codec.Response
+	err  error
+}
+
+func (s *stream) setBatchHandler(id uint64, indx int, ack chan *batchAckMessage) {
+	callback := func(b []byte, err error) {
+		if _, ok := err.(*DisconnectedError); ok {
+			// the whole batch fails if the connection drops
+			ack <- &batchAckMessage{indx: indx, err: err}
+			return
+		}
+		resp := &codec.Response{ID: id, Result: b}
+		if err != nil {
+			obj, ok := err.(*codec.ErrorObject)
+			if !ok {
+				obj = &codec.ErrorObject{Message: err.Error()}
+			}
+			resp.Error = obj
+		}
+		// ack has capacity for all the elements in the batch
+		ack <- &batchAckMessage{indx: indx, resp: resp}
+	}
+
+	s.handlerLock.Lock()
+	s.handler[id] = callback
+	s.handlerLock.Unlock()
+}
+
+func (s *stream) unsubscribe(sub *subscription) error {
 	s.subsLock.Lock()
-	defer s.subsLock.Unlock()
+	if _, ok := s.subs[sub]; !ok {
+		s.subsLock.Unlock()
+		return fmt.Errorf("subscription %s not found", sub.id)
+	}
+	delete(s.subs, sub)
+	delete(s.subIDs, sub.id)
+	id := sub.id
+	s.subsLock.Unlock()
+
+	sub.close()
 
-	if _, ok := s.subs[id]; !ok {
-		return fmt.Errorf("subscription %s not found", id)
+	if id == "" {
+		// the subscription is not active in the current connection
+		return nil
 	}
-	delete(s.subs, id)
 
 	var result bool
 	if err := s.Call("eth_unsubscribe", &result, id); err != nil {
@@ -234,23 +945,59 @@ func (s *stream) unsubscribe(id string) error {
 	return nil
 }
 
-func (s *stream) setSubscription(id string, callback func(b []byte)) {
-	s.subsLock.Lock()
-	defer s.subsLock.Unlock()
+// subscribe issues the subscription in the current connection. The id is registered
+// when the response is read, so no notification that follows it is missed.
+func (s *stream) subscribe(sub *subscription) error {
+	register := func(b []byte) {
+		var id string
+		if err := json.Unmarshal(b, &id); err != nil {
+			return
+		}
+		s.subsLock.Lock()
+		defer s.subsLock.Unlock()
+
+		if _, ok := s.subs[sub]; ok {
+			sub.id = id
+			s.subIDs[id] = sub
+		}
+	}
 
-	s.subs[id] = callback
+	var out string
+	return s.call(context.Background(), "eth_subscribe", &out, register, append([]interface{}{sub.method}, sub.params...)...)
 }
 
 // Subscribe implements the PubSubTransport interface
-func (s *stream) Subscribe(method string, callback func(b []byte)) (func() error, error) {
-	var out string
-	if err := s.Call("eth_subscribe", &out, method); err != nil {
+func (s *stream) Subscribe(method string, callback func(b []byte), params ...interface{}) (func() error, error) {
+	return s.SubscribeWithError(method, callback, nil, params...)
+}
+
+// SubscribeWithError implements the PubSubErrTransport interface
+func (s *stream) SubscribeWithError(method string, callback func(b []byte), onErr func(err error), params ...interface{}) (func() error, error) {
+	sub := &subscription{
+		method:   method,
+		params:   params,
+		callback: callback,
+		onErr:    onErr,
+		notifyCh: make(chan struct{}, 1),
+	}
+
+	s.subsLock.Lock()
+	s.subs[sub] = struct{}{}
+	s.subsLock.Unlock()
+
+	if err := s.subscribe(sub); err != nil {
+		s.subsLock.Lock()
+		delete(s.subs, sub)
+		if sub.id != "" {
+			delete(s.subIDs, sub.id)
+		}
+		s.subsLock.Unlock()
 		return nil, err
 	}
+	go sub.run()
 
-	s.setSubscription(out, callback)
 	cancel := func() error {
-		return s.unsubscribe(out)
+		return s.unsubscribe(sub)
 	}
 	return cancel, nil
 }
@@ -260,18 +1007,71 @@ func (s *stream) SetMaxConnsPerHost(count int) {
 }
 
 type websocketCodec struct {
-	conn *websocket.Conn
+	conn        *websocket.Conn
+	writeLock   sync.Mutex
+	readTimeout time.Duration
+	closeCh     chan struct{}
+	closeOnce   sync.Once
+}
+
+func newWebsocketCodec(conn *websocket.Conn, config *WebsocketConfig) *websocketCodec {
+	w := &websocketCodec{
+		conn:    conn,
+		closeCh: make(chan struct{}),
+	}
+	if config.ReadLimit > 0 {
+		conn.SetReadLimit(config.ReadLimit)
+	}
+	if config.PingInterval > 0 {
+		// every pong extends the deadline of the reads
+		w.readTimeout = config.PingInterval + config.PongTimeout
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(w.readTimeout))
+		})
+		go w.keepAlive(config.PingInterval, config.PongTimeout)
+	}
+	return w
+}
+
+func (w *websocketCodec) keepAlive(interval, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(timeout)); err != nil {
+				return
+			}
+		case <-w.closeCh:
+			return
+		}
+	}
 }
 
 func (w *websocketCodec) Close() error {
-	return w.conn.Close()
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.closeCh)
+		err = w.conn.Close()
+	})
+	return err
 }
 
 func (w *websocketCodec) Write(b []byte) error {
+	// the websocket connection does not support concurrent writers
+	w.writeLock.Lock()
+	defer w.writeLock.Unlock()
+
 	return w.conn.WriteMessage(websocket.TextMessage, b)
 }
 
 func (w *websocketCodec) Read(b []byte) ([]byte, error) {
+	if w.readTimeout != 0 {
+		if err := w.conn.SetReadDeadline(time.Now().Add(w.readTimeout)); err != nil {
+			return nil, err
+		}
+	}
 	_, buf, err := w.conn.ReadMessage()
 	if err != nil {
 		return nil, err
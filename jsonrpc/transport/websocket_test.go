@@ -0,0 +1,687 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/git-yongge/ethgo/jsonrpc/codec"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockCodec is a codec that accepts writes and never returns any response
+type mockCodec struct {
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+func (m *mockCodec) Read(b []byte) ([]byte, error) {
+	<-m.closeCh
+	return nil, ErrTimeout
+}
+
+func (m *mockCodec) Write(b []byte) error {
+	return nil
+}
+
+func (m *mockCodec) Close() error {
+	m.closeOnce.Do(func() {
+		close(m.closeCh)
+	})
+	return nil
+}
+
+func TestStream_CallContextCancel(t *testing.T) {
+	s, err := newStream(&mockCodec{closeCh: make(chan struct{})})
+	assert.NoError(t, err)
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var out string
+	err = s.CallContext(ctx, "eth_blockNumber", &out)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// the pending handler is removed after the cancellation
+	s.handlerLock.Lock()
+	assert.Len(t, s.handler, 0)
+	s.handlerLock.Unlock()
+}
+
+// echoCodec is a codec that answers every batch request with the method
+// name as the result, or with an error if the method is 'fail'
+type echoCodec struct {
+	respCh  chan []byte
+	closeCh chan struct{}
+}
+
+func (e *echoCodec) Read(b []byte) ([]byte, error) {
+	select {
+	case buf := <-e.respCh:
+		return append(b, buf...), nil
+	case <-e.closeCh:
+		return nil, ErrTimeout
+	}
+}
+
+func (e *echoCodec) Write(b []byte) error {
+	var requests []codec.Request
+	if err := json.Unmarshal(b, &requests); err != nil {
+		return err
+	}
+	responses := []*codec.Response{}
+	for _, req := range requests {
+		resp := &codec.Response{ID: req.ID}
+		if req.Method == "fail" {
+			resp.Error = &codec.ErrorObject{Code: 1, Message: "failed"}
+		} else {
+			resp.Result, _ = json.Marshal(req.Method)
+		}
+		responses = append(responses, resp)
+	}
+	raw, err := json.Marshal(responses)
+	if err != nil {
+		return err
+	}
+	e.respCh <- raw
+	return nil
+}
+
+func (e *echoCodec) Close() error {
+	close(e.closeCh)
+	return nil
+}
+
+func TestStream_ReconnectZeroBackoff(t *testing.T) {
+	var dials int32
+	dial := func() (Codec, error) {
+		atomic.AddInt32(&dials, 1)
+		return nil, fmt.Errorf("connection refused")
+	}
+
+	codec := &mockCodec{closeCh: make(chan struct{})}
+	s, err := newReconnectStream(codec, dial, &WebsocketConfig{})
+	assert.NoError(t, err)
+
+	// the connection drops and the zero backoff is raised to the minimum
+	codec.Close()
+	time.Sleep(350 * time.Millisecond)
+	assert.NoError(t, s.Close())
+
+	num := atomic.LoadInt32(&dials)
+	assert.True(t, num >= 1 && num <= 4, num)
+}
+
+func TestStream_BatchCall(t *testing.T) {
+	s, err := newStream(&echoCodec{respCh: make(chan []byte, 1), closeCh: make(chan struct{})})
+	assert.NoError(t, err)
+	defer s.Close()
+
+	var out0, out1 string
+	elems := []*BatchElem{
+		{Method: "a", Result: &out0},
+		{Method: "fail"},
+		{Method: "b", Result: &out1},
+	}
+	assert.NoError(t, s.BatchCall(context.Background(), elems))
+
+	assert.NoError(t, elems[0].Error)
+	assert.Equal(t, "a", out0)
+	assert.Error(t, elems[1].Error)
+	assert.NoError(t, elems[2].Error)
+	assert.Equal(t, "b", out1)
+}
+
+// wsNode is a websocket jsonrpc node for the tests. It answers every request with the
+// method name as the result, except for 'hang' that is never answered
+type wsNode struct {
+	t      *testing.T
+	server *httptest.Server
+
+	lock    sync.Mutex
+	conns   []*websocket.Conn
+	subID   int
+	subsCh  chan string
+	noPongs bool
+	result  json.RawMessage
+
+	// notification sent right after the response of every subscription
+	firstNotification string
+
+	// rejectSubscribe fails the subscriptions
+	rejectSubscribe bool
+}
+
+func newWsNode(t *testing.T) *wsNode {
+	n := &wsNode{t: t, subsCh: make(chan string, 10)}
+	n.server = httptest.NewServer(http.HandlerFunc(n.serve))
+	return n
+}
+
+func (n *wsNode) url() string {
+	return "ws" + strings.TrimPrefix(n.server.URL, "http")
+}
+
+func (n *wsNode) serve(w http.ResponseWriter, r *http.Request) {
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		n.t.Error(err)
+		return
+	}
+
+	n.lock.Lock()
+	n.conns = append(n.conns, conn)
+	if n.noPongs {
+		conn.SetPingHandler(func(string) error { return nil })
+	}
+	n.lock.Unlock()
+
+	for {
+		_, buf, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var req codec.Request
+		if err := json.Unmarshal(buf, &req); err != nil {
+			n.t.Error(err)
+			return
+		}
+
+		resp := &codec.Response{ID: req.ID}
+		switch req.Method {
+		case "hang":
+			continue
+		case "eth_subscribe":
+			n.lock.Lock()
+			if n.rejectSubscribe {
+				n.lock.Unlock()
+				resp.Error = &codec.ErrorObject{Code: -32000, Message: "subscriptions not available"}
+				break
+			}
+			n.subID++
+			id := fmt.Sprintf("0x%d", n.subID)
+			n.lock.Unlock()
+
+			resp.Result, _ = json.Marshal(id)
+			n.subsCh <- id
+		case "eth_unsubscribe":
+			resp.Result = []byte("true")
+		default:
+			n.lock.Lock()
+			resp.Result = n.result
+			n.lock.Unlock()
+			if resp.Result == nil {
+				resp.Result, _ = json.Marshal(req.Method)
+			}
+		}
+		n.write(conn, resp)
+
+		n.lock.Lock()
+		first := n.firstNotification
+		n.lock.Unlock()
+
+		if req.Method == "eth_subscribe" && first != "" {
+			var id string
+			json.Unmarshal(resp.Result, &id)
+
+			params, _ := json.Marshal(map[string]interface{}{
+				"subscription": id,
+				"result":       first,
+			})
+			n.write(conn, &codec.Request{JsonRPC: "2.0", Method: "eth_subscription", Params: params})
+		}
+	}
+}
+
+func (n *wsNode) write(conn *websocket.Conn, obj interface{}) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		n.t.Fatal(err)
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	conn.WriteMessage(websocket.TextMessage, raw)
+}
+
+// notify sends a subscription message on the last connection
+func (n *wsNode) notify(id string, result string) {
+	n.lock.Lock()
+	conn := n.conns[len(n.conns)-1]
+	n.lock.Unlock()
+
+	params, _ := json.Marshal(map[string]interface{}{
+		"subscription": id,
+		"result":       result,
+	})
+	n.write(conn, &codec.Request{JsonRPC: "2.0", Method: "eth_subscription", Params: params})
+}
+
+// restart drops all the connections
+func (n *wsNode) restart() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	for _, conn := range n.conns {
+		conn.Close()
+	}
+}
+
+func (n *wsNode) close() {
+	n.restart()
+	n.server.Close()
+}
+
+func (n *wsNode) waitSubscribe(t *testing.T) string {
+	select {
+	case id := <-n.subsCh:
+		return id
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscription not found")
+	}
+	return ""
+}
+
+func stateRecorder() (chan ConnState, WebsocketOption) {
+	stateCh := make(chan ConnState, 10)
+	return stateCh, func(c *WebsocketConfig) {
+		c.MinBackoff = 100 * time.Millisecond
+		c.MaxBackoff = 200 * time.Millisecond
+		c.OnStateChange = func(state ConnState, err error) {
+			stateCh <- state
+		}
+	}
+}
+
+func waitState(t *testing.T, stateCh chan ConnState, state ConnState) {
+	select {
+	case s := <-stateCh:
+		assert.Equal(t, state, s)
+	case <-time.After(2 * time.Second):
+		t.Fatalf("state %s not found", state)
+	}
+}
+
+func TestWebsocket_Reconnect(t *testing.T) {
+	n := newWsNode(t)
+	defer n.close()
+
+	stateCh, opt := stateRecorder()
+	tr, err := NewTransport(n.url(), nil, opt, func(c *WebsocketConfig) {
+		c.Reconnect = true
+	})
+	assert.NoError(t, err)
+	defer tr.Close()
+
+	msgCh := make(chan string, 10)
+	cancel, err := tr.(PubSubTransport).Subscribe("newHeads", func(b []byte) {
+		var msg string
+		assert.NoError(t, json.Unmarshal(b, &msg))
+		msgCh <- msg
+	})
+	assert.NoError(t, err)
+
+	id0 := n.waitSubscribe(t)
+	n.notify(id0, "a")
+	assert.Equal(t, "a", <-msgCh)
+
+	// the call in flight fails when the connection drops
+	errCh := make(chan error)
+	go func() {
+		errCh <- tr.Call("hang", nil)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	n.restart()
+	waitState(t, stateCh, ConnStateDisconnected)
+
+	var dErr *DisconnectedError
+	assert.True(t, errors.As(<-errCh, &dErr))
+
+	// the subscription is issued again with a new id
+	id1 := n.waitSubscribe(t)
+	assert.NotEqual(t, id0, id1)
+	waitState(t, stateCh, ConnStateConnected)
+
+	n.notify(id1, "b")
+	assert.Equal(t, "b", <-msgCh)
+
+	var out string
+	assert.NoError(t, tr.Call("eth_blockNumber", &out))
+	assert.Equal(t, "eth_blockNumber", out)
+
+	assert.NoError(t, cancel())
+
+	assert.NoError(t, tr.Close())
+	waitState(t, stateCh, ConnStateClosed)
+
+	err = tr.Call("eth_blockNumber", &out)
+	assert.True(t, errors.As(err, &dErr))
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestWebsocket_StateChangeOrder(t *testing.T) {
+	n := newWsNode(t)
+	defer n.close()
+
+	// the first change blocks until it is released
+	releaseCh := make(chan struct{})
+	stateCh := make(chan ConnState, 10)
+	tr, err := NewTransport(n.url(), nil, func(c *WebsocketConfig) {
+		c.Reconnect = true
+		c.MinBackoff = 100 * time.Millisecond
+		c.OnStateChange = func(state ConnState, err error) {
+			if state == ConnStateDisconnected {
+				<-releaseCh
+			}
+			stateCh <- state
+		}
+	})
+	assert.NoError(t, err)
+	defer tr.Close()
+
+	_, err = tr.(PubSubTransport).Subscribe("newHeads", func(b []byte) {})
+	assert.NoError(t, err)
+	n.waitSubscribe(t)
+
+	// the connection is restored while the handler blocks
+	n.restart()
+	n.waitSubscribe(t)
+
+	var out string
+	assert.NoError(t, tr.Call("eth_blockNumber", &out))
+
+	close(releaseCh)
+	waitState(t, stateCh, ConnStateDisconnected)
+	waitState(t, stateCh, ConnStateConnected)
+
+	assert.NoError(t, tr.Close())
+	waitState(t, stateCh, ConnStateClosed)
+}
+
+func TestWebsocket_SubscribeFirstNotification(t *testing.T) {
+	n := newWsNode(t)
+	n.firstNotification = "a"
+	defer n.close()
+
+	stateCh, opt := stateRecorder()
+	tr, err := NewTransport(n.url(), nil, opt, func(c *WebsocketConfig) {
+		c.Reconnect = true
+	})
+	assert.NoError(t, err)
+	defer tr.Close()
+
+	msgCh := make(chan string, 10)
+	_, err = tr.(PubSubTransport).Subscribe("newHeads", func(b []byte) {
+		var msg string
+		assert.NoError(t, json.Unmarshal(b, &msg))
+		msgCh <- msg
+	})
+	assert.NoError(t, err)
+
+	// the notification right after the response is not dropped
+	n.waitSubscribe(t)
+	assert.Equal(t, "a", <-msgCh)
+
+	// neither after the subscription is issued again
+	n.lock.Lock()
+	n.firstNotification = "b"
+	n.lock.Unlock()
+
+	n.restart()
+	waitState(t, stateCh, ConnStateDisconnected)
+	n.waitSubscribe(t)
+	waitState(t, stateCh, ConnStateConnected)
+	assert.Equal(t, "b", <-msgCh)
+}
+
+func TestStream_ResubscribeStaleConnection(t *testing.T) {
+	n := newWsNode(t)
+	defer n.close()
+
+	tr, err := NewTransport(n.url(), nil)
+	assert.NoError(t, err)
+	defer tr.Close()
+
+	s := tr.(*stream)
+	_, err = s.Subscribe("newHeads", func(b []byte) {})
+	assert.NoError(t, err)
+	id := n.waitSubscribe(t)
+
+	// a pass of a connection that is not the current one does nothing
+	s.resetSubscriptions()
+	s.resubscribe(&rejectCodec{})
+
+	select {
+	case <-n.subsCh:
+		t.Fatal("subscription issued from a stale connection")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// a pass of the current connection issues the subscription again
+	s.connLock.RLock()
+	codec := s.codec
+	s.connLock.RUnlock()
+
+	s.resubscribe(codec)
+	assert.NotEqual(t, id, n.waitSubscribe(t))
+}
+
+func TestWebsocket_NoReconnect(t *testing.T) {
+	n := newWsNode(t)
+	defer n.close()
+
+	// the transport does not reconnect by default
+	stateCh, opt := stateRecorder()
+	tr, err := NewTransport(n.url(), nil, opt)
+	assert.NoError(t, err)
+	defer tr.Close()
+
+	n.restart()
+	waitState(t, stateCh, ConnStateDisconnected)
+
+	var dErr *DisconnectedError
+	assert.True(t, errors.As(tr.Call("eth_blockNumber", nil), &dErr))
+}
+
+func TestWebsocket_KeepAlive(t *testing.T) {
+	n := newWsNode(t)
+	n.noPongs = true
+	defer n.close()
+
+	stateCh, opt := stateRecorder()
+	tr, err := NewTransport(n.url(), nil, opt, func(c *WebsocketConfig) {
+		c.PingInterval = 20 * time.Millisecond
+		c.PongTimeout = 20 * time.Millisecond
+	})
+	assert.NoError(t, err)
+	defer tr.Close()
+
+	// the node does not answer the pings
+	waitState(t, stateCh, ConnStateDisconnected)
+}
+
+func TestWebsocket_ReadLimit(t *testing.T) {
+	n := newWsNode(t)
+	n.result, _ = json.Marshal(strings.Repeat("a", 1024))
+	defer n.close()
+
+	stateCh, opt := stateRecorder()
+	tr, err := NewTransport(n.url(), nil, opt, func(c *WebsocketConfig) {
+		c.ReadLimit = 512
+	})
+	assert.NoError(t, err)
+	defer tr.Close()
+
+	var dErr *DisconnectedError
+	assert.True(t, errors.As(tr.Call("eth_blockNumber", nil), &dErr))
+	waitState(t, stateCh, ConnStateDisconnected)
+}
+
+// rejectCodec is a codec that rejects every batch with a single error object
+type rejectCodec struct {
+	respCh  chan []byte
+	closeCh chan struct{}
+}
+
+func (r *rejectCodec) Read(b []byte) ([]byte, error) {
+	select {
+	case buf := <-r.respCh:
+		return append(b, buf...), nil
+	case <-r.closeCh:
+		return nil, ErrTimeout
+	}
+}
+
+func (r *rejectCodec) Write(b []byte) error {
+	r.respCh <- []byte(`{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"batch too large"}}`)
+	return nil
+}
+
+func (r *rejectCodec) Close() error {
+	close(r.closeCh)
+	return nil
+}
+
+func TestStream_BatchCallRejected(t *testing.T) {
+	s, err := newStream(&rejectCodec{respCh: make(chan []byte, 1), closeCh: make(chan struct{})})
+	assert.NoError(t, err)
+	defer s.Close()
+
+	elems := []*BatchElem{
+		{Method: "a"},
+		{Method: "b"},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err = s.BatchCall(ctx, elems)
+	assert.Equal(t, &codec.ErrorObject{Code: -32600, Message: "batch too large"}, err)
+}
+
+func subscribeWithError(t *testing.T, tr Transport, callback func(b []byte)) chan error {
+	errCh := make(chan error, 1)
+	_, err := tr.(PubSubErrTransport).SubscribeWithError("newHeads", callback, func(err error) {
+		errCh <- err
+	})
+	assert.NoError(t, err)
+	return errCh
+}
+
+func waitSubscriptionErr(t *testing.T, errCh chan error) error {
+	select {
+	case err := <-errCh:
+		return err
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscription did not end")
+	}
+	return nil
+}
+
+func TestWebsocket_SubscriptionEnds(t *testing.T) {
+	var dErr *DisconnectedError
+
+	t.Run("disconnected", func(t *testing.T) {
+		n := newWsNode(t)
+		defer n.close()
+
+		tr, err := NewTransport(n.url(), nil)
+		assert.NoError(t, err)
+		defer tr.Close()
+
+		msgCh := make(chan []byte, 1)
+		errCh := subscribeWithError(t, tr, func(b []byte) {
+			msgCh <- b
+		})
+		id := n.waitSubscribe(t)
+
+		// the queued messages are delivered before the error
+		n.notify(id, "a")
+		n.restart()
+
+		assert.Equal(t, `"a"`, string(<-msgCh))
+		assert.True(t, errors.As(waitSubscriptionErr(t, errCh), &dErr))
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		n := newWsNode(t)
+		defer n.close()
+
+		tr, err := NewTransport(n.url(), nil)
+		assert.NoError(t, err)
+
+		errCh := subscribeWithError(t, tr, func(b []byte) {})
+		n.waitSubscribe(t)
+
+		assert.NoError(t, tr.Close())
+		assert.ErrorIs(t, waitSubscriptionErr(t, errCh), ErrClosed)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		n := newWsNode(t)
+		defer n.close()
+
+		stateCh, opt := stateRecorder()
+		tr, err := NewTransport(n.url(), nil, opt, func(c *WebsocketConfig) {
+			c.Reconnect = true
+		})
+		assert.NoError(t, err)
+		defer tr.Close()
+
+		errCh := subscribeWithError(t, tr, func(b []byte) {})
+		n.waitSubscribe(t)
+
+		// the node does not accept the subscription after reconnecting
+		n.lock.Lock()
+		n.rejectSubscribe = true
+		n.lock.Unlock()
+
+		n.restart()
+		waitState(t, stateCh, ConnStateDisconnected)
+
+		var obj *codec.ErrorObject
+		assert.True(t, errors.As(waitSubscriptionErr(t, errCh), &obj))
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		n := newWsNode(t)
+		defer n.close()
+
+		tr, err := NewTransport(n.url(), nil)
+		assert.NoError(t, err)
+		defer tr.Close()
+
+		// the callback is stuck with the first message
+		blockCh := make(chan struct{})
+		var delivered int32
+
+		errCh := subscribeWithError(t, tr, func(b []byte) {
+			atomic.AddInt32(&delivered, 1)
+			<-blockCh
+		})
+		id := n.waitSubscribe(t)
+
+		for i := 0; i < maxSubscriptionQueue+2; i++ {
+			n.notify(id, "a")
+		}
+		// wait for the node to deliver the messages
+		var out string
+		assert.NoError(t, tr.Call("eth_blockNumber", &out))
+
+		// the queued messages are dropped and the error is reported
+		// once the callback returns
+		close(blockCh)
+		assert.Equal(t, ErrSubscriptionOverflow, waitSubscriptionErr(t, errCh))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&delivered))
+	})
+}
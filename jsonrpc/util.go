@@ -2,16 +2,28 @@ package jsonrpc
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"strconv"
 	"strings"
+
+	"github.com/git-yongge/ethgo"
 )
 
 func encodeUintToHex(i uint64) string {
 	return fmt.Sprintf("0x%x", i)
 }
 
+// encodeBlock returns the parameter of a block selector. The EIP-1898
+// selectors are encoded as objects and the rest as strings.
+func encodeBlock(block ethgo.BlockNumberOrHash) interface{} {
+	if obj, ok := block.(json.Marshaler); ok {
+		return obj
+	}
+	return block.Location()
+}
+
 func parseBigInt(str string) *big.Int {
 	if strings.HasPrefix(str, "0x") {
 		str = str[2:]
@@ -0,0 +1,237 @@
+package multicall
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/abi"
+	"github.com/git-yongge/ethgo/contract"
+)
+
+// Multicall3Address is the address of the canonical Multicall3 deployment,
+// available at the same address in most chains
+var Multicall3Address = ethgo.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+var aggregate3 = abi.MustNewMethod("function aggregate3(tuple(address target, bool allowFailure, bytes callData)[] calls) payable returns (tuple(bool success, bytes returnData)[] returnData)")
+
+// Provider are the eth1x methods required by the multicall
+type Provider interface {
+	Call(msg *ethgo.CallMsg, block ethgo.BlockNumberOrHash) (string, error)
+	ChainID() (*big.Int, error)
+}
+
+// Config is the multicall configuration
+type Config struct {
+	// Address is the Multicall3 address used in any chain
+	Address *ethgo.Address
+
+	// Addresses are the Multicall3 addresses for each chain id. If
+	// the chain is not found, the canonical deployment is used.
+	Addresses map[uint64]ethgo.Address
+}
+
+// DefaultConfig returns the default multicall configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Addresses: map[uint64]ethgo.Address{},
+	}
+}
+
+// ConfigOption is an option to configure the multicall
+type ConfigOption func(*Config)
+
+// WithAddress sets the Multicall3 address for any chain
+func WithAddress(addr ethgo.Address) ConfigOption {
+	return func(c *Config) {
+		c.Address = &addr
+	}
+}
+
+// WithChainAddress sets the Multicall3 address for a chain
+func WithChainAddress(chainID uint64, addr ethgo.Address) ConfigOption {
+	return func(c *Config) {
+		c.Addresses[chainID] = addr
+	}
+}
+
+// Call is a contract call aggregated in the multicall
+type Call struct {
+	Target ethgo.Address
+	Method *abi.Method
+	Args   []interface{}
+
+	// AllowFailure is true by default. If it is false and the
+	// call fails, the whole multicall fails.
+	AllowFailure bool
+}
+
+// Result is the result of a call
+type Result struct {
+	// Success is true if the call did not revert
+	Success bool
+
+	// ReturnData is the raw output of the call or the revert data
+	ReturnData []byte
+
+	// Output is the output of the call decoded with the method
+	Output map[string]interface{}
+
+	// Err is the *abi.RevertError if the call failed or the
+	// error decoding the output
+	Err error
+}
+
+// Multicall aggregates contract calls in a single eth_call
+// to the Multicall3 contract
+type Multicall struct {
+	provider Provider
+	config   *Config
+	calls    []*Call
+
+	lock    sync.Mutex
+	address *ethgo.Address
+}
+
+// NewMulticall creates a new multicall
+func NewMulticall(provider Provider, opts ...ConfigOption) *Multicall {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	return &Multicall{
+		provider: provider,
+		config:   config,
+	}
+}
+
+// Add adds a call to a method of the target contract
+func (m *Multicall) Add(target ethgo.Address, method *abi.Method, args ...interface{}) *Call {
+	call := &Call{
+		Target:       target,
+		Method:       method,
+		Args:         args,
+		AllowFailure: true,
+	}
+	m.calls = append(m.calls, call)
+	return call
+}
+
+// AddContract adds a call to a method of the contract
+func (m *Multicall) AddContract(c *contract.Contract, method string, args ...interface{}) (*Call, error) {
+	mm := c.ABI().GetMethod(method)
+	if mm == nil {
+		return nil, fmt.Errorf("method %s not found", method)
+	}
+	return m.Add(c.Addr(), mm, args...), nil
+}
+
+// Len returns the number of calls
+func (m *Multicall) Len() int {
+	return len(m.calls)
+}
+
+// Reset removes all the calls
+func (m *Multicall) Reset() {
+	m.calls = m.calls[:0]
+}
+
+// Address returns the Multicall3 address used in the chain of the provider
+func (m *Multicall) Address() (ethgo.Address, error) {
+	if m.config.Address != nil {
+		return *m.config.Address, nil
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.address != nil {
+		return *m.address, nil
+	}
+
+	addr := Multicall3Address
+	if len(m.config.Addresses) != 0 {
+		chainID, err := m.provider.ChainID()
+		if err != nil {
+			return ethgo.Address{}, err
+		}
+		if chainAddr, ok := m.config.Addresses[chainID.Uint64()]; ok {
+			addr = chainAddr
+		}
+	}
+	m.address = &addr
+	return addr, nil
+}
+
+// Do sends all the calls in one eth_call at the given block and returns
+// their results in the same order the calls were added.
+func (m *Multicall) Do(block ethgo.BlockNumberOrHash) ([]*Result, error) {
+	if len(m.calls) == 0 {
+		return []*Result{}, nil
+	}
+
+	addr, err := m.Address()
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]map[string]interface{}, len(m.calls))
+	for indx, call := range m.calls {
+		data, err := call.Method.Encode(call.Args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode call %d (%s): %v", indx, call.Method.Name, err)
+		}
+		calls[indx] = map[string]interface{}{
+			"target":       call.Target,
+			"allowFailure": call.AllowFailure,
+			"callData":     data,
+		}
+	}
+	input, err := aggregate3.Encode([]interface{}{calls})
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &ethgo.CallMsg{
+		To:   &addr,
+		Data: input,
+	}
+	rawStr, err := m.provider.Call(msg, block)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := hex.DecodeString(strings.TrimPrefix(rawStr, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("multicall contract not found at %s", addr)
+	}
+
+	output, err := aggregate3.Decode(raw)
+	if err != nil {
+		return nil, err
+	}
+	returnData, ok := output["returnData"].([]map[string]interface{})
+	if !ok || len(returnData) != len(m.calls) {
+		return nil, fmt.Errorf("expected %d results from the multicall", len(m.calls))
+	}
+
+	results := make([]*Result, len(m.calls))
+	for indx, call := range m.calls {
+		res := &Result{
+			Success:    returnData[indx]["success"].(bool),
+			ReturnData: returnData[indx]["returnData"].([]byte),
+		}
+		if !res.Success {
+			res.Err = abi.UnpackRevert(res.ReturnData, nil)
+		} else if len(call.Method.Outputs.TupleElems()) != 0 {
+			res.Output, res.Err = call.Method.Decode(res.ReturnData)
+		}
+		results[indx] = res
+	}
+	return results, nil
+}
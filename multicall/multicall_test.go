@@ -0,0 +1,151 @@
+package multicall
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/abi"
+	"github.com/git-yongge/ethgo/contract"
+	"github.com/git-yongge/ethgo/jsonrpc"
+	"github.com/git-yongge/ethgo/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	tokenA = ethgo.Address{0x1}
+	tokenB = ethgo.Address{0x2}
+)
+
+var erc20 = abi.MustNewABI(`[
+	{"type": "function", "name": "balanceOf", "stateMutability": "view", "inputs": [{"name": "owner", "type": "address"}], "outputs": [{"name": "balance", "type": "uint256"}]},
+	{"type": "function", "name": "symbol", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "string"}]}
+]`)
+
+// newMulticallServer returns a server that answers the aggregate3 calls. The calls
+// to tokenA return the balance of the owner and the calls to tokenB revert.
+func newMulticallServer(t *testing.T, multicallAddr ethgo.Address) *testutil.MockServer {
+	s := testutil.NewMockServer()
+	s.HandleResult("eth_chainId", "0x539")
+	s.Handle("eth_call", func(params []json.RawMessage) (interface{}, error) {
+		var msg struct {
+			To   ethgo.Address
+			Data string
+		}
+		assert.NoError(t, json.Unmarshal(params[0], &msg))
+		if msg.To != multicallAddr {
+			// no code in the address
+			return "0x", nil
+		}
+
+		data, err := hex.DecodeString(msg.Data[2:])
+		assert.NoError(t, err)
+
+		input, err := abi.Decode(aggregate3.Inputs, data[4:])
+		assert.NoError(t, err)
+
+		results := []map[string]interface{}{}
+		for _, call := range input.(map[string]interface{})["calls"].([]map[string]interface{}) {
+			callData := call["callData"].([]byte)
+
+			var res map[string]interface{}
+			switch call["target"].(ethgo.Address) {
+			case tokenA:
+				args, err := abi.Decode(erc20.GetMethod("balanceOf").Inputs, callData[4:])
+				assert.NoError(t, err)
+
+				owner := args.(map[string]interface{})["owner"].(ethgo.Address)
+				out, err := erc20.GetMethod("balanceOf").Outputs.Encode(map[string]interface{}{
+					"balance": new(big.Int).SetBytes(owner[:1]),
+				})
+				assert.NoError(t, err)
+				res = map[string]interface{}{"success": true, "returnData": out}
+
+			case tokenB:
+				out, err := abi.MustNewType("tuple(string)").Encode([]interface{}{"not a token"})
+				assert.NoError(t, err)
+				revert := append([]byte{0x8, 0xc3, 0x79, 0xa0}, out...)
+				res = map[string]interface{}{"success": false, "returnData": revert}
+			}
+			results = append(results, res)
+		}
+
+		out, err := aggregate3.Outputs.Encode(map[string]interface{}{"returnData": results})
+		assert.NoError(t, err)
+		return "0x" + hex.EncodeToString(out), nil
+	})
+	return s
+}
+
+func TestMulticall_Do(t *testing.T) {
+	s := newMulticallServer(t, Multicall3Address)
+	defer s.Close()
+
+	client, _ := jsonrpc.NewClient(s.HTTPAddr())
+
+	m := NewMulticall(client.Eth())
+
+	balanceOf := erc20.GetMethod("balanceOf")
+	m.Add(tokenA, balanceOf, ethgo.Address{0x5})
+	m.Add(tokenB, balanceOf, ethgo.Address{0x5})
+
+	_, err := m.AddContract(contract.NewContract(tokenA, erc20, client), "balanceOf", ethgo.Address{0x7})
+	assert.NoError(t, err)
+
+	_, err = m.AddContract(contract.NewContract(tokenA, erc20, client), "transfer")
+	assert.Error(t, err)
+
+	results, err := m.Do(ethgo.Latest)
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+
+	// one eth_call for all the calls
+	assert.Equal(t, 1, s.Calls("eth_call"))
+
+	assert.True(t, results[0].Success)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, big.NewInt(5), results[0].Output["balance"])
+
+	assert.False(t, results[1].Success)
+	assert.EqualError(t, results[1].Err, "execution reverted: not a token")
+
+	assert.True(t, results[2].Success)
+	assert.Equal(t, big.NewInt(7), results[2].Output["balance"])
+}
+
+func TestMulticall_Address(t *testing.T) {
+	chainAddr := ethgo.Address{0xca}
+
+	s := newMulticallServer(t, chainAddr)
+	defer s.Close()
+
+	client, _ := jsonrpc.NewClient(s.HTTPAddr())
+
+	// the canonical deployment is not found in the chain
+	m := NewMulticall(client.Eth())
+	m.Add(tokenA, erc20.GetMethod("balanceOf"), ethgo.Address{0x1})
+
+	_, err := m.Do(ethgo.Latest)
+	assert.Error(t, err)
+
+	// the address of the chain 1337 is used
+	m = NewMulticall(client.Eth(), WithChainAddress(1, ethgo.Address{0x1}), WithChainAddress(1337, chainAddr))
+	m.Add(tokenA, erc20.GetMethod("balanceOf"), ethgo.Address{0x1})
+
+	addr, err := m.Address()
+	assert.NoError(t, err)
+	assert.Equal(t, chainAddr, addr)
+
+	results, err := m.Do(ethgo.Latest)
+	assert.NoError(t, err)
+	assert.True(t, results[0].Success)
+
+	// the address for any chain does not query the chain id
+	m = NewMulticall(client.Eth(), WithAddress(chainAddr))
+	addr, err = m.Address()
+	assert.NoError(t, err)
+	assert.Equal(t, chainAddr, addr)
+	assert.Equal(t, 1, s.Calls("eth_chainId"))
+}
@@ -0,0 +1,240 @@
+package nonce
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/git-yongge/ethgo"
+)
+
+// Provider are the eth1x methods required by the nonce manager
+type Provider interface {
+	GetNonce(addr ethgo.Address, blockNumber ethgo.BlockNumberOrHash) (uint64, error)
+}
+
+// Manager hands out nonces for the accounts that send transactions
+// concurrently. The nonces of an account are seeded from the pending
+// transaction count of the node the first time they are requested.
+type Manager struct {
+	provider Provider
+
+	lock     sync.Mutex
+	accounts map[ethgo.Address]*account
+}
+
+type account struct {
+	lock sync.Mutex
+
+	// synced is true once the nonce has been seeded from the node
+	synced bool
+
+	// next is the next nonce never handed out
+	next uint64
+
+	// released are the nonces handed out but not used, sorted
+	released []uint64
+
+	// inflight are the nonces handed out and not yet released or sent
+	inflight map[uint64]struct{}
+
+	// stale is true if the nonce has to be synced again with
+	// the node once there are no nonces in flight
+	stale bool
+}
+
+// ErrNoncesInFlight is returned by Resync if there are nonces handed
+// out that are not released or marked as sent yet
+var ErrNoncesInFlight = fmt.Errorf("nonces in flight")
+
+// NewManager creates a new nonce manager
+func NewManager(provider Provider) *Manager {
+	return &Manager{
+		provider: provider,
+		accounts: map[ethgo.Address]*account{},
+	}
+}
+
+func (m *Manager) getAccount(addr ethgo.Address) *account {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	acct, ok := m.accounts[addr]
+	if !ok {
+		acct = &account{inflight: map[uint64]struct{}{}}
+		m.accounts[addr] = acct
+	}
+	return acct
+}
+
+func (m *Manager) syncLocked(addr ethgo.Address, acct *account) error {
+	nonce, err := m.provider.GetNonce(addr, ethgo.Pending)
+	if err != nil {
+		return err
+	}
+
+	// the nonces are handed out again from the pending count, the
+	// released nonces are either used already or above the next nonce
+	acct.released = nil
+	acct.next = nonce
+	acct.synced = true
+	acct.stale = false
+	return nil
+}
+
+// Acquire returns the next nonce for the account. The lowest released
+// nonce is reused first. The nonce is in flight until it is either
+// released with Release or marked as used with Sent.
+func (m *Manager) Acquire(addr ethgo.Address) (uint64, error) {
+	acct := m.getAccount(addr)
+
+	acct.lock.Lock()
+	defer acct.lock.Unlock()
+
+	if !acct.synced || (acct.stale && len(acct.inflight) == 0) {
+		if err := m.syncLocked(addr, acct); err != nil {
+			return 0, err
+		}
+	}
+
+	var nonce uint64
+	if len(acct.released) != 0 {
+		nonce = acct.released[0]
+		acct.released = acct.released[1:]
+	} else {
+		nonce = acct.next
+		acct.next++
+	}
+	acct.inflight[nonce] = struct{}{}
+	return nonce, nil
+}
+
+// Sent marks a nonce handed out as used by a transaction sent to the node
+func (m *Manager) Sent(addr ethgo.Address, nonce uint64) {
+	acct := m.getAccount(addr)
+
+	acct.lock.Lock()
+	defer acct.lock.Unlock()
+
+	delete(acct.inflight, nonce)
+}
+
+// Release returns a nonce that was not used (i.e. the transaction was
+// not sent) so that it is handed out again.
+func (m *Manager) Release(addr ethgo.Address, nonce uint64) {
+	acct := m.getAccount(addr)
+
+	acct.lock.Lock()
+	defer acct.lock.Unlock()
+
+	delete(acct.inflight, nonce)
+
+	if !acct.synced || nonce >= acct.next {
+		// not handed out by this manager
+		return
+	}
+	if nonce == acct.next-1 {
+		acct.next--
+
+		// the highest released nonces can be collapsed too
+		for len(acct.released) != 0 && acct.released[len(acct.released)-1] == acct.next-1 {
+			acct.released = acct.released[:len(acct.released)-1]
+			acct.next--
+		}
+		return
+	}
+
+	indx := sort.Search(len(acct.released), func(i int) bool {
+		return acct.released[i] >= nonce
+	})
+	if indx < len(acct.released) && acct.released[indx] == nonce {
+		// already released
+		return
+	}
+	acct.released = append(acct.released, 0)
+	copy(acct.released[indx+1:], acct.released[indx:])
+	acct.released[indx] = nonce
+}
+
+// Resync seeds again the nonce of the account from the pending transaction
+// count of the node. Use it after nonce errors. The pending count does not
+// include the transactions not sent yet, so it fails with ErrNoncesInFlight
+// while there are nonces handed out that are not released or sent.
+func (m *Manager) Resync(addr ethgo.Address) error {
+	acct := m.getAccount(addr)
+
+	acct.lock.Lock()
+	defer acct.lock.Unlock()
+
+	if len(acct.inflight) != 0 {
+		return ErrNoncesInFlight
+	}
+	return m.syncLocked(addr, acct)
+}
+
+// markStale drops the nonce from the nonces in flight and syncs the
+// account again with the next Acquire once no nonces are in flight
+func (m *Manager) markStale(addr ethgo.Address, nonce uint64) {
+	acct := m.getAccount(addr)
+
+	acct.lock.Lock()
+	defer acct.lock.Unlock()
+
+	delete(acct.inflight, nonce)
+	acct.stale = true
+}
+
+// Reset forgets the state of the account. The nonce is seeded again
+// from the node the next time it is acquired, the nonces in flight
+// must be sent or abandoned before.
+func (m *Manager) Reset(addr ethgo.Address) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.accounts, addr)
+}
+
+// Do acquires a nonce for the account and calls send with it. If send
+// fails with a nonce error, the account is synced again with the node
+// once the other sends in progress finish. Otherwise, the nonce is
+// released to be used again.
+func (m *Manager) Do(addr ethgo.Address, send func(nonce uint64) error) error {
+	nonce, err := m.Acquire(addr)
+	if err != nil {
+		return err
+	}
+	if err := send(nonce); err != nil {
+		if IsNonceError(err) {
+			m.markStale(addr, nonce)
+		} else {
+			m.Release(addr, nonce)
+		}
+		return err
+	}
+	m.Sent(addr, nonce)
+	return nil
+}
+
+var nonceErrors = []string{
+	"nonce too low",
+	"nonce too high",
+	"replacement transaction underpriced",
+	"already known",
+	"known transaction",
+}
+
+// IsNonceError returns true if the error returned by the node
+// is caused by an invalid nonce
+func IsNonceError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, str := range nonceErrors {
+		if strings.Contains(msg, str) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,200 @@
+package nonce
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockProvider struct {
+	lock  sync.Mutex
+	nonce uint64
+	calls int
+}
+
+func (m *mockProvider) GetNonce(addr ethgo.Address, blockNumber ethgo.BlockNumberOrHash) (uint64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if blockNumber != ethgo.Pending {
+		return 0, fmt.Errorf("pending block expected")
+	}
+	m.calls++
+	return m.nonce, nil
+}
+
+func TestManager_Concurrent(t *testing.T) {
+	p := &mockProvider{nonce: 10}
+	m := NewManager(p)
+
+	addr := ethgo.Address{0x1}
+	num := 100
+
+	var lock sync.Mutex
+	found := map[uint64]struct{}{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < num; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			nonce, err := m.Acquire(addr)
+			assert.NoError(t, err)
+
+			lock.Lock()
+			found[nonce] = struct{}{}
+			lock.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// all the nonces are unique and the provider is only queried once
+	assert.Len(t, found, num)
+	for i := uint64(10); i < 10+uint64(num); i++ {
+		_, ok := found[i]
+		assert.True(t, ok)
+	}
+	assert.Equal(t, 1, p.calls)
+}
+
+func TestManager_Release(t *testing.T) {
+	m := NewManager(&mockProvider{nonce: 5})
+	addr := ethgo.Address{0x1}
+
+	acquire := func() uint64 {
+		nonce, err := m.Acquire(addr)
+		assert.NoError(t, err)
+		return nonce
+	}
+
+	assert.Equal(t, uint64(5), acquire())
+	assert.Equal(t, uint64(6), acquire())
+	assert.Equal(t, uint64(7), acquire())
+
+	// a released nonce in the middle is reused first
+	m.Release(addr, 6)
+	assert.Equal(t, uint64(6), acquire())
+	assert.Equal(t, uint64(8), acquire())
+
+	// releasing the highest nonces moves the next nonce back
+	m.Release(addr, 7)
+	m.Release(addr, 8)
+	assert.Equal(t, uint64(7), acquire())
+	assert.Equal(t, uint64(8), acquire())
+
+	// nonces never handed out are ignored
+	m.Release(addr, 20)
+	assert.Equal(t, uint64(9), acquire())
+}
+
+func TestManager_Do(t *testing.T) {
+	p := &mockProvider{nonce: 1}
+	m := NewManager(p)
+	addr := ethgo.Address{0x1}
+
+	// a failed send releases the nonce
+	err := m.Do(addr, func(nonce uint64) error {
+		assert.Equal(t, uint64(1), nonce)
+		return fmt.Errorf("insufficient funds")
+	})
+	assert.Error(t, err)
+
+	assert.NoError(t, m.Do(addr, func(nonce uint64) error {
+		assert.Equal(t, uint64(1), nonce)
+		return nil
+	}))
+
+	// a nonce error resyncs with the node
+	p.nonce = 5
+	err = m.Do(addr, func(nonce uint64) error {
+		assert.Equal(t, uint64(2), nonce)
+		return fmt.Errorf("nonce too low")
+	})
+	assert.Error(t, err)
+
+	assert.NoError(t, m.Do(addr, func(nonce uint64) error {
+		assert.Equal(t, uint64(5), nonce)
+		return nil
+	}))
+}
+
+func TestManager_ResyncInFlight(t *testing.T) {
+	p := &mockProvider{nonce: 1}
+	m := NewManager(p)
+	addr := ethgo.Address{0x1}
+
+	nonce, err := m.Acquire(addr)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), nonce)
+
+	// the nonce is not sent yet and the node does not know about it
+	assert.Equal(t, ErrNoncesInFlight, m.Resync(addr))
+
+	m.Sent(addr, nonce)
+	p.nonce = 2
+	assert.NoError(t, m.Resync(addr))
+}
+
+func TestManager_DoNonceErrorInFlight(t *testing.T) {
+	p := &mockProvider{nonce: 1}
+	m := NewManager(p)
+	addr := ethgo.Address{0x1}
+
+	// a send in progress holds the nonce 1
+	inflight, err := m.Acquire(addr)
+	assert.NoError(t, err)
+
+	// the nonce error does not resync while the nonce 1 is in flight
+	err = m.Do(addr, func(nonce uint64) error {
+		assert.Equal(t, uint64(2), nonce)
+		return fmt.Errorf("nonce too high")
+	})
+	assert.Error(t, err)
+
+	nonce, err := m.Acquire(addr)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), nonce)
+	m.Release(addr, nonce)
+
+	// the account is synced once the nonces in flight are sent
+	m.Sent(addr, inflight)
+	p.nonce = 2
+
+	assert.NoError(t, m.Do(addr, func(nonce uint64) error {
+		assert.Equal(t, uint64(2), nonce)
+		return nil
+	}))
+}
+
+func TestManager_ResyncReleased(t *testing.T) {
+	p := &mockProvider{nonce: 5}
+	m := NewManager(p)
+	addr := ethgo.Address{0x1}
+
+	acquire := func() uint64 {
+		nonce, err := m.Acquire(addr)
+		assert.NoError(t, err)
+		return nonce
+	}
+
+	for i := uint64(5); i <= 8; i++ {
+		assert.Equal(t, i, acquire())
+	}
+	m.Sent(addr, 5)
+	m.Sent(addr, 6)
+	m.Sent(addr, 8)
+	m.Release(addr, 7)
+
+	// the node dropped the transactions with the nonces 6 and 8
+	p.nonce = 6
+	assert.NoError(t, m.Resync(addr))
+
+	// the released nonce above the pending count is not handed out twice
+	for i := uint64(6); i <= 9; i++ {
+		assert.Equal(t, i, acquire())
+	}
+}
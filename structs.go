@@ -136,6 +136,48 @@ type Block struct {
 	Transactions       []*Transaction
 	TransactionsHashes []Hash
 	Uncles             []Hash
+
+	// MixHash is the proof of work mix digest or, after the merge,
+	// the randomness (prevRandao) of the beacon chain
+	MixHash *Hash
+
+	// Nonce is the 8 bytes proof of work nonce (zero after the merge)
+	Nonce []byte
+
+	LogsBloom []byte
+
+	// BaseFee is the base fee per gas of the block (EIP-1559)
+	BaseFee *big.Int
+
+	// Size is the size of the block in bytes
+	Size uint64
+
+	// TotalDifficulty is the total difficulty of the chain up to this block
+	TotalDifficulty *big.Int
+
+	// Withdrawals are the validator withdrawals of the block (EIP-4895)
+	Withdrawals     []*Withdrawal
+	WithdrawalsRoot *Hash
+
+	// BlobGasUsed and ExcessBlobGas are the blob gas values of the block (EIP-4844)
+	BlobGasUsed   *uint64
+	ExcessBlobGas *uint64
+
+	// ParentBeaconRoot is the root of the parent beacon block (EIP-4788)
+	ParentBeaconRoot *Hash
+
+	// RequestsHash is the commitment to the execution layer requests (EIP-7685)
+	RequestsHash *Hash
+}
+
+// Withdrawal is a validator withdrawal from the beacon chain (EIP-4895)
+type Withdrawal struct {
+	Index          uint64
+	ValidatorIndex uint64
+	Address        Address
+
+	// Amount is the amount of the withdrawal in gwei
+	Amount uint64
 }
 
 type TransactionType int
@@ -146,6 +188,8 @@ const (
 	TransactionAccessList TransactionType = 1
 	// eip-1559
 	TransactionDynamicFee TransactionType = 2
+	// eip-4844 (only the receipts are supported)
+	TransactionBlob TransactionType = 3
 )
 
 type Transaction struct {
@@ -176,6 +220,10 @@ type Transaction struct {
 	// eip-1559 values
 	MaxPriorityFeePerGas *big.Int
 	MaxFeePerGas         *big.Int
+
+	// eip-4844 values
+	MaxFeePerBlobGas    *big.Int
+	BlobVersionedHashes []Hash
 }
 
 type AccessEntry struct {
@@ -217,6 +265,7 @@ func (l *LogFilter) SetTo(b BlockNumber) {
 }
 
 type Receipt struct {
+	Type              TransactionType
 	TransactionHash   Hash
 	TransactionIndex  uint64
 	ContractAddress   Address
@@ -228,6 +277,62 @@ type Receipt struct {
 	LogsBloom         []byte
 	Logs              []*Log
 	Status            uint64
+
+	// To is the recipient of the transaction (nil for contract creations)
+	To *Address
+
+	// Root is the post-transaction state root of the receipts before
+	// Byzantium (EIP-658), which do not have a status
+	Root *Hash
+
+	// EffectiveGasPrice is the price per gas paid by the transaction
+	EffectiveGasPrice *big.Int
+
+	// BlobGasUsed and BlobGasPrice are the blob gas values of
+	// blob transactions (EIP-4844)
+	BlobGasUsed  uint64
+	BlobGasPrice *big.Int
+}
+
+// Fee returns the fee paid by the transaction in wei (gas used times the
+// effective gas price plus the blob gas fee). It is nil if the receipt
+// does not include the effective gas price.
+func (r *Receipt) Fee() *big.Int {
+	if r.EffectiveGasPrice == nil {
+		return nil
+	}
+	fee := new(big.Int).SetUint64(r.GasUsed)
+	fee.Mul(fee, r.EffectiveGasPrice)
+
+	if r.BlobGasPrice != nil {
+		blobFee := new(big.Int).SetUint64(r.BlobGasUsed)
+		fee.Add(fee, blobFee.Mul(blobFee, r.BlobGasPrice))
+	}
+	return fee
+}
+
+// AccountProof is the proof of an account and its storage slots (eth_getProof)
+type AccountProof struct {
+	Address Address
+
+	// AccountProof are the rlp encoded nodes from the state root to the account
+	AccountProof [][]byte
+
+	Balance     *big.Int
+	CodeHash    Hash
+	Nonce       uint64
+	StorageHash Hash
+
+	StorageProof []*StorageProof
+}
+
+// StorageProof is the proof of a storage slot of an account
+type StorageProof struct {
+	Key   Hash
+	Value *big.Int
+
+	// Proof are the rlp encoded nodes from the storage root to the slot
+	Proof [][]byte
 }
 
 type Log struct {
@@ -248,6 +353,10 @@ const (
 	Latest   BlockNumber = -1
 	Earliest BlockNumber = -2
 	Pending  BlockNumber = -3
+	// Safe is the most recent block that is unlikely to be reorged
+	Safe BlockNumber = -4
+	// Finalized is the most recent block that cannot be reorged
+	Finalized BlockNumber = -5
 )
 
 func (b BlockNumber) Location() string {
@@ -262,6 +371,10 @@ func (b BlockNumber) String() string {
 		return "earliest"
 	case Pending:
 		return "pending"
+	case Safe:
+		return "safe"
+	case Finalized:
+		return "finalized"
 	}
 	if b < 0 {
 		panic("internal. blocknumber is negative")
@@ -269,6 +382,30 @@ func (b BlockNumber) String() string {
 	return fmt.Sprintf("0x%x", uint64(b))
 }
 
+// ParseBlockNumber parses a block tag or a hex block number
+func ParseBlockNumber(str string) (BlockNumber, error) {
+	switch str {
+	case "latest":
+		return Latest, nil
+	case "earliest":
+		return Earliest, nil
+	case "pending":
+		return Pending, nil
+	case "safe":
+		return Safe, nil
+	case "finalized":
+		return Finalized, nil
+	}
+	if !strings.HasPrefix(str, "0x") {
+		return 0, fmt.Errorf("block number '%s' does not have 0x prefix", str)
+	}
+	num, err := strconv.ParseInt(str[2:], 16, 64)
+	if err != nil {
+		return 0, err
+	}
+	return BlockNumber(num), nil
+}
+
 func EncodeBlock(block ...BlockNumber) BlockNumber {
 	if len(block) != 1 {
 		return Latest
@@ -280,6 +417,18 @@ type BlockNumberOrHash interface {
 	Location() string
 }
 
+// BlockHashParam selects a block by hash as defined in EIP-1898. If RequireCanonical
+// is set the node fails the request if the block is not in the canonical chain.
+type BlockHashParam struct {
+	Hash             Hash
+	RequireCanonical bool
+}
+
+// Location implements the BlockNumberOrHash interface
+func (b BlockHashParam) Location() string {
+	return b.Hash.String()
+}
+
 func (b *Block) Copy() *Block {
 	bb := new(Block)
 	*bb = *b
@@ -2,6 +2,7 @@ package ethgo
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"io/ioutil"
 	"path/filepath"
@@ -52,6 +53,39 @@ func TestEncodingJSON_Transaction(t *testing.T) {
 	}
 }
 
+func TestEncodingJSON_TransactionType(t *testing.T) {
+	decode := func(typ, chainID, maxFee string) *Transaction {
+		str := `{"hash":"0x1","from":"0x1","to":null,"input":"0x","value":"0x0","gasPrice":"0x1","gas":"0x5208","nonce":"0x0","v":"0x0","r":"0x1","s":"0x1"`
+		if typ != "" {
+			str += `,"type":"` + typ + `"`
+		}
+		if chainID != "" {
+			str += `,"chainId":"` + chainID + `"`
+		}
+		if maxFee != "" {
+			str += `,"maxFeePerGas":"` + maxFee + `","maxPriorityFeePerGas":"` + maxFee + `"`
+		}
+		str += "}"
+
+		txn := new(Transaction)
+		assert.NoError(t, txn.UnmarshalJSON([]byte(str)))
+		return txn
+	}
+
+	// the nodes return the chain id of the eip-155 legacy transactions
+	txn := decode("0x0", "0x1", "")
+	assert.Equal(t, TransactionLegacy, txn.Type)
+	assert.Nil(t, txn.ChainID)
+
+	// the type is detected from the fields if it is not set
+	txn = decode("", "0x1", "")
+	assert.Equal(t, TransactionAccessList, txn.Type)
+
+	txn = decode("", "0x1", "0x2")
+	assert.Equal(t, TransactionDynamicFee, txn.Type)
+	assert.Equal(t, uint64(21000), txn.Gas)
+}
+
 type testFile struct {
 	name    string
 	content []byte
@@ -77,3 +111,46 @@ func readTestsuite(t *testing.T, pattern string) (res []*testFile) {
 	}
 	return
 }
+
+func TestEncodingJSON_BlockForks(t *testing.T) {
+	decode := func(name string) *Block {
+		data, err := ioutil.ReadFile(filepath.Join("./testsuite", name))
+		assert.NoError(t, err)
+
+		b := new(Block)
+		assert.NoError(t, b.UnmarshalJSON(data))
+		return b
+	}
+
+	// pre-london block without the optional fields
+	b := decode("block-txn-hashes.json")
+	assert.Nil(t, b.BaseFee)
+	assert.Nil(t, b.MixHash)
+	assert.Nil(t, b.Nonce)
+	assert.Nil(t, b.Withdrawals)
+
+	// the blocks of each fork are built by go-ethereum
+	b = decode("block-london.json")
+	assert.Equal(t, uint64(1000000000), b.BaseFee.Uint64())
+	assert.Equal(t, "b223da049adf2216", hex.EncodeToString(b.Nonce))
+	assert.Len(t, b.LogsBloom, 256)
+	assert.NotNil(t, b.TotalDifficulty)
+	assert.Nil(t, b.WithdrawalsRoot)
+
+	// post-merge blocks have zero difficulty and nonce
+	b = decode("block-paris.json")
+	assert.Equal(t, uint64(0), b.Difficulty.Uint64())
+	assert.Equal(t, make([]byte, 8), b.Nonce)
+	assert.NotNil(t, b.MixHash)
+
+	b = decode("block-shanghai.json")
+	assert.NotNil(t, b.WithdrawalsRoot)
+	assert.Nil(t, b.TotalDifficulty)
+	assert.Len(t, b.Withdrawals, 2)
+	assert.Equal(t, &Withdrawal{
+		Index:          1,
+		ValidatorIndex: 65780,
+		Address:        HexToAddress("0x388ea662ef2c223ec0b047d41bf3c0f362142ad5"),
+		Amount:         3972081,
+	}, b.Withdrawals[1])
+}
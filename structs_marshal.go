@@ -61,6 +61,39 @@ func (t *Block) MarshalJSON() ([]byte, error) {
 	o.Set("timestamp", a.NewString(fmt.Sprintf("0x%x", t.Timestamp)))
 	o.Set("difficulty", a.NewString(fmt.Sprintf("0x%x", t.Difficulty)))
 	o.Set("extraData", a.NewString("0x"+hex.EncodeToString(t.ExtraData)))
+	if t.MixHash != nil {
+		o.Set("mixHash", a.NewString(t.MixHash.String()))
+	}
+	if t.Nonce != nil {
+		o.Set("nonce", a.NewString("0x"+hex.EncodeToString(t.Nonce)))
+	}
+	if t.LogsBloom != nil {
+		o.Set("logsBloom", a.NewString("0x"+hex.EncodeToString(t.LogsBloom)))
+	}
+	if t.BaseFee != nil {
+		o.Set("baseFeePerGas", a.NewString(fmt.Sprintf("0x%x", t.BaseFee)))
+	}
+	if t.WithdrawalsRoot != nil {
+		o.Set("withdrawalsRoot", a.NewString(t.WithdrawalsRoot.String()))
+	}
+	if t.BlobGasUsed != nil {
+		o.Set("blobGasUsed", a.NewString(fmt.Sprintf("0x%x", *t.BlobGasUsed)))
+	}
+	if t.ExcessBlobGas != nil {
+		o.Set("excessBlobGas", a.NewString(fmt.Sprintf("0x%x", *t.ExcessBlobGas)))
+	}
+	if t.ParentBeaconRoot != nil {
+		o.Set("parentBeaconBlockRoot", a.NewString(t.ParentBeaconRoot.String()))
+	}
+	if t.RequestsHash != nil {
+		o.Set("requestsHash", a.NewString(t.RequestsHash.String()))
+	}
+	if t.Size != 0 {
+		o.Set("size", a.NewString(fmt.Sprintf("0x%x", t.Size)))
+	}
+	if t.TotalDifficulty != nil {
+		o.Set("totalDifficulty", a.NewString(fmt.Sprintf("0x%x", t.TotalDifficulty)))
+	}
 
 	// uncles
 	if len(t.Uncles) != 0 {
@@ -87,11 +120,38 @@ func (t *Block) MarshalJSON() ([]byte, error) {
 		o.Set("transactions", txns)
 	}
 
+	// withdrawals
+	if t.Withdrawals != nil {
+		withdrawals := a.NewArray()
+		for indx, w := range t.Withdrawals {
+			withdrawals.SetArrayItem(indx, w.marshalJSON(a))
+		}
+		o.Set("withdrawals", withdrawals)
+	}
+
 	res := o.MarshalTo(nil)
 	defaultArena.Put(a)
 	return res, nil
 }
 
+// MarshalJSON implements the marshal interface
+func (w *Withdrawal) MarshalJSON() ([]byte, error) {
+	a := defaultArena.Get()
+	v := w.marshalJSON(a)
+	res := v.MarshalTo(nil)
+	defaultArena.Put(a)
+	return res, nil
+}
+
+func (w *Withdrawal) marshalJSON(a *fastjson.Arena) *fastjson.Value {
+	o := a.NewObject()
+	o.Set("index", a.NewString(fmt.Sprintf("0x%x", w.Index)))
+	o.Set("validatorIndex", a.NewString(fmt.Sprintf("0x%x", w.ValidatorIndex)))
+	o.Set("address", a.NewString(w.Address.String()))
+	o.Set("amount", a.NewString(fmt.Sprintf("0x%x", w.Amount)))
+	return o
+}
+
 // MarshalJSON implements the Marshal interface.
 func (t *Transaction) MarshalJSON() ([]byte, error) {
 	a := defaultArena.Get()
@@ -123,6 +183,16 @@ func (t *Transaction) marshalJSON(a *fastjson.Arena) *fastjson.Value {
 	if t.MaxFeePerGas != nil {
 		o.Set("maxFeePerGas", a.NewString(fmt.Sprintf("0x%x", t.MaxFeePerGas)))
 	}
+	if t.MaxFeePerBlobGas != nil {
+		o.Set("maxFeePerBlobGas", a.NewString(fmt.Sprintf("0x%x", t.MaxFeePerBlobGas)))
+	}
+	if t.BlobVersionedHashes != nil {
+		hashes := a.NewArray()
+		for indx, hash := range t.BlobVersionedHashes {
+			hashes.SetArrayItem(indx, a.NewString(hash.String()))
+		}
+		o.Set("blobVersionedHashes", hashes)
+	}
 
 	if t.Nonce != 0 {
 		// we can remove this once we include support for custom nonces
@@ -213,6 +283,7 @@ func (l *LogFilter) MarshalJSON() ([]byte, error) {
 		for indx, addr := range l.Address {
 			v.SetArrayItem(indx, a.NewString(addr.String()))
 		}
+		o.Set("address", v)
 	}
 
 	v := a.NewArray()
@@ -252,3 +323,18 @@ func (l *LogFilter) MarshalJSON() ([]byte, error) {
 	defaultArena.Put(a)
 	return res, nil
 }
+
+// MarshalJSON implements the json marshal interface
+func (b BlockHashParam) MarshalJSON() ([]byte, error) {
+	a := defaultArena.Get()
+
+	o := a.NewObject()
+	o.Set("blockHash", a.NewString(b.Hash.String()))
+	if b.RequireCanonical {
+		o.Set("requireCanonical", a.NewTrue())
+	}
+
+	res := o.MarshalTo(nil)
+	defaultArena.Put(a)
+	return res, nil
+}
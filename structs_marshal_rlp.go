@@ -40,7 +40,7 @@ func (t *Transaction) MarshalRLPWith(arena *fastrlp.Arena) (*fastrlp.Value, erro
 
 	vv.Set(arena.NewUint(t.Nonce))
 
-	if t.Type == TransactionDynamicFee {
+	if t.Type == TransactionDynamicFee || t.Type == TransactionBlob {
 		// dynamic fee uses
 		vv.Set(arena.NewBigInt(t.MaxPriorityFeePerGas))
 		vv.Set(arena.NewBigInt(t.MaxFeePerGas))
@@ -70,10 +70,20 @@ func (t *Transaction) MarshalRLPWith(arena *fastrlp.Arena) (*fastrlp.Value, erro
 		vv.Set(accessList)
 	}
 
-	// signature values
-	vv.Set(arena.NewCopyBytes(t.V))
-	vv.Set(arena.NewCopyBytes(t.R))
-	vv.Set(arena.NewCopyBytes(t.S))
+	if t.Type == TransactionBlob {
+		vv.Set(arena.NewBigInt(t.MaxFeePerBlobGas))
+
+		hashes := arena.NewArray()
+		for _, hash := range t.BlobVersionedHashes {
+			hashes.Set(arena.NewCopyBytes(hash[:]))
+		}
+		vv.Set(hashes)
+	}
+
+	// signature values are integers without leading zeros
+	vv.Set(arena.NewBigInt(new(big.Int).SetBytes(t.V)))
+	vv.Set(arena.NewBigInt(new(big.Int).SetBytes(t.R)))
+	vv.Set(arena.NewBigInt(new(big.Int).SetBytes(t.S)))
 
 	if t.Type == TransactionLegacy {
 		return vv, nil
@@ -91,6 +101,8 @@ func (t *Transaction) UnmarshalRLP(buf []byte) error {
 			t.Type = TransactionAccessList
 		case 2:
 			t.Type = TransactionDynamicFee
+		case 3:
+			t.Type = TransactionBlob
 		default:
 			return fmt.Errorf("type byte %d not found", typ)
 		}
@@ -124,6 +136,9 @@ func (t *Transaction) UnmarshalRLPWith(v *fastrlp.Value) error {
 	case TransactionDynamicFee:
 		// access list txn + gas fee 1 + gas fee 2 - gas price
 		num = 12
+	case TransactionBlob:
+		// dynamic fee txn + blob gas fee + blob hashes
+		num = 14
 	default:
 		return fmt.Errorf("transaction type %d not found", t.Type)
 	}
@@ -143,7 +158,7 @@ func (t *Transaction) UnmarshalRLPWith(v *fastrlp.Value) error {
 		return err
 	}
 
-	if t.Type == TransactionDynamicFee {
+	if t.Type == TransactionDynamicFee || t.Type == TransactionBlob {
 		// dynamic fee uses
 		t.MaxPriorityFeePerGas = new(big.Int)
 		if err := getElem().GetBigInt(t.MaxPriorityFeePerGas); err != nil {
@@ -190,6 +205,25 @@ func (t *Transaction) UnmarshalRLPWith(v *fastrlp.Value) error {
 		}
 	}
 
+	if t.Type == TransactionBlob {
+		t.MaxFeePerBlobGas = new(big.Int)
+		if err := getElem().GetBigInt(t.MaxFeePerBlobGas); err != nil {
+			return err
+		}
+		hashes, err := getElem().GetElems()
+		if err != nil {
+			return err
+		}
+		t.BlobVersionedHashes = t.BlobVersionedHashes[:0]
+		for _, elem := range hashes {
+			var hash Hash
+			if err := elem.GetHash(hash[:]); err != nil {
+				return err
+			}
+			t.BlobVersionedHashes = append(t.BlobVersionedHashes, hash)
+		}
+	}
+
 	// V
 	if t.V, err = getElem().GetBytes(t.V); err != nil {
 		return err
@@ -281,3 +315,479 @@ func (a *AccessList) UnmarshalRLPWith(v *fastrlp.Value) error {
 	}
 	return nil
 }
+
+const (
+	// number of fields in the header before London
+	headerFieldsLegacy = 15
+
+	// London adds the base fee
+	headerFieldsLondon = 16
+
+	// Shanghai adds the withdrawals root
+	headerFieldsShanghai = 17
+
+	// Cancun adds the blob gas used, the excess blob gas and the parent beacon root
+	headerFieldsCancun = 20
+
+	// Prague adds the requests hash
+	headerFieldsPrague = 21
+)
+
+// ComputeHash returns the hash of the block computed from the header fields.
+// It can be compared with the Hash reported by the node.
+func (b *Block) ComputeHash() (hash Hash, err error) {
+	var rlpEncode []byte
+	if rlpEncode, err = b.MarshalRLPTo(nil); err != nil {
+		return Hash{}, err
+	}
+	return BytesToHash(Keccak256(rlpEncode)), nil
+}
+
+// numHeaderFields returns the number of fields in the header. The optional
+// fields of a fork are encoded if any field of that fork or a later one is set.
+func (b *Block) numHeaderFields() int {
+	switch {
+	case b.RequestsHash != nil:
+		return headerFieldsPrague
+	case b.BlobGasUsed != nil || b.ExcessBlobGas != nil || b.ParentBeaconRoot != nil:
+		return headerFieldsCancun
+	case b.WithdrawalsRoot != nil:
+		return headerFieldsShanghai
+	case b.BaseFee != nil:
+		return headerFieldsLondon
+	default:
+		return headerFieldsLegacy
+	}
+}
+
+// MarshalRLPTo marshals the header of the block to a []byte destination
+func (b *Block) MarshalRLPTo(dst []byte) ([]byte, error) {
+	return fastrlp.MarshalRLP(b)
+}
+
+// MarshalRLPWith marshals the header of the block to RLP with a specific fastrlp.Arena
+func (b *Block) MarshalRLPWith(arena *fastrlp.Arena) (*fastrlp.Value, error) {
+	vv := arena.NewArray()
+
+	vv.Set(arena.NewCopyBytes(b.ParentHash[:]))
+	vv.Set(arena.NewCopyBytes(b.Sha3Uncles[:]))
+	vv.Set(arena.NewCopyBytes(b.Miner[:]))
+	vv.Set(arena.NewCopyBytes(b.StateRoot[:]))
+	vv.Set(arena.NewCopyBytes(b.TransactionsRoot[:]))
+	vv.Set(arena.NewCopyBytes(b.ReceiptsRoot[:]))
+
+	if b.LogsBloom == nil {
+		vv.Set(arena.NewCopyBytes(make([]byte, 256)))
+	} else {
+		if len(b.LogsBloom) != 256 {
+			return nil, fmt.Errorf("logs bloom has %d bytes but 256 expected", len(b.LogsBloom))
+		}
+		vv.Set(arena.NewCopyBytes(b.LogsBloom))
+	}
+
+	vv.Set(arena.NewBigInt(bigOrZero(b.Difficulty)))
+	vv.Set(arena.NewUint(b.Number))
+	vv.Set(arena.NewUint(b.GasLimit))
+	vv.Set(arena.NewUint(b.GasUsed))
+	vv.Set(arena.NewUint(b.Timestamp))
+	vv.Set(arena.NewCopyBytes(b.ExtraData))
+
+	if b.MixHash == nil {
+		vv.Set(arena.NewCopyBytes(make([]byte, 32)))
+	} else {
+		vv.Set(arena.NewCopyBytes(b.MixHash[:]))
+	}
+
+	if b.Nonce == nil {
+		vv.Set(arena.NewCopyBytes(make([]byte, 8)))
+	} else {
+		if len(b.Nonce) != 8 {
+			return nil, fmt.Errorf("nonce has %d bytes but 8 expected", len(b.Nonce))
+		}
+		vv.Set(arena.NewCopyBytes(b.Nonce))
+	}
+
+	num := b.numHeaderFields()
+	if num >= headerFieldsLondon {
+		vv.Set(arena.NewBigInt(bigOrZero(b.BaseFee)))
+	}
+	if num >= headerFieldsShanghai {
+		vv.Set(arena.NewCopyBytes(hashOrZero(b.WithdrawalsRoot)))
+	}
+	if num >= headerFieldsCancun {
+		vv.Set(arena.NewUint(uintOrZero(b.BlobGasUsed)))
+		vv.Set(arena.NewUint(uintOrZero(b.ExcessBlobGas)))
+		vv.Set(arena.NewCopyBytes(hashOrZero(b.ParentBeaconRoot)))
+	}
+	if num >= headerFieldsPrague {
+		vv.Set(arena.NewCopyBytes(hashOrZero(b.RequestsHash)))
+	}
+	return vv, nil
+}
+
+// UnmarshalRLP unmarshals the header of the block from RLP. The hash of the
+// block is set from the encoded header.
+func (b *Block) UnmarshalRLP(buf []byte) error {
+	b.Hash = BytesToHash(Keccak256(buf))
+
+	if err := fastrlp.UnmarshalRLP(buf, b); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UnmarshalRLPWith unmarshals the header of the block from a fastrlp.Value
+func (b *Block) UnmarshalRLPWith(v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+
+	num := len(elems)
+	switch num {
+	case headerFieldsLegacy, headerFieldsLondon, headerFieldsShanghai, headerFieldsCancun, headerFieldsPrague:
+	default:
+		return fmt.Errorf("incorrect number of elements to decode header, found %d", num)
+	}
+
+	getElem := func() *fastrlp.Value {
+		v := elems[0]
+		elems = elems[1:]
+		return v
+	}
+
+	if err = getElem().GetHash(b.ParentHash[:]); err != nil {
+		return err
+	}
+	if err = getElem().GetHash(b.Sha3Uncles[:]); err != nil {
+		return err
+	}
+	if err = getElem().GetAddr(b.Miner[:]); err != nil {
+		return err
+	}
+	if err = getElem().GetHash(b.StateRoot[:]); err != nil {
+		return err
+	}
+	if err = getElem().GetHash(b.TransactionsRoot[:]); err != nil {
+		return err
+	}
+	if err = getElem().GetHash(b.ReceiptsRoot[:]); err != nil {
+		return err
+	}
+	if b.LogsBloom, err = getElem().GetBytes(b.LogsBloom[:0], 256); err != nil {
+		return err
+	}
+	b.Difficulty = new(big.Int)
+	if err = getElem().GetBigInt(b.Difficulty); err != nil {
+		return err
+	}
+	if b.Number, err = getElem().GetUint64(); err != nil {
+		return err
+	}
+	if b.GasLimit, err = getElem().GetUint64(); err != nil {
+		return err
+	}
+	if b.GasUsed, err = getElem().GetUint64(); err != nil {
+		return err
+	}
+	if b.Timestamp, err = getElem().GetUint64(); err != nil {
+		return err
+	}
+	if b.ExtraData, err = getElem().GetBytes(b.ExtraData[:0]); err != nil {
+		return err
+	}
+	b.MixHash = &Hash{}
+	if err = getElem().GetHash(b.MixHash[:]); err != nil {
+		return err
+	}
+	if b.Nonce, err = getElem().GetBytes(b.Nonce[:0], 8); err != nil {
+		return err
+	}
+
+	b.BaseFee = nil
+	if num >= headerFieldsLondon {
+		b.BaseFee = new(big.Int)
+		if err = getElem().GetBigInt(b.BaseFee); err != nil {
+			return err
+		}
+	}
+	b.WithdrawalsRoot = nil
+	if num >= headerFieldsShanghai {
+		b.WithdrawalsRoot = &Hash{}
+		if err = getElem().GetHash(b.WithdrawalsRoot[:]); err != nil {
+			return err
+		}
+	}
+	b.BlobGasUsed, b.ExcessBlobGas, b.ParentBeaconRoot = nil, nil, nil
+	if num >= headerFieldsCancun {
+		var blobGasUsed, excessBlobGas uint64
+		if blobGasUsed, err = getElem().GetUint64(); err != nil {
+			return err
+		}
+		if excessBlobGas, err = getElem().GetUint64(); err != nil {
+			return err
+		}
+		b.BlobGasUsed, b.ExcessBlobGas = &blobGasUsed, &excessBlobGas
+
+		b.ParentBeaconRoot = &Hash{}
+		if err = getElem().GetHash(b.ParentBeaconRoot[:]); err != nil {
+			return err
+		}
+	}
+	b.RequestsHash = nil
+	if num >= headerFieldsPrague {
+		b.RequestsHash = &Hash{}
+		if err = getElem().GetHash(b.RequestsHash[:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func bigOrZero(b *big.Int) *big.Int {
+	if b == nil {
+		return new(big.Int)
+	}
+	return b
+}
+
+func uintOrZero(i *uint64) uint64 {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
+func hashOrZero(h *Hash) []byte {
+	if h == nil {
+		return make([]byte, 32)
+	}
+	return h[:]
+}
+
+// MarshalRLPTo marshals the consensus encoding of the receipt to a []byte
+// destination. Typed receipts are prefixed with the type byte.
+func (r *Receipt) MarshalRLPTo(dst []byte) ([]byte, error) {
+	raw, err := fastrlp.MarshalRLP(r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Type == TransactionLegacy {
+		return append(dst, raw...), nil
+	}
+	// append type byte
+	dst = append(dst, byte(r.Type))
+	return append(dst, raw...), nil
+}
+
+// MarshalRLPWith marshals the receipt to RLP with a specific fastrlp.Arena
+func (r *Receipt) MarshalRLPWith(arena *fastrlp.Arena) (*fastrlp.Value, error) {
+	vv := arena.NewArray()
+
+	if r.Root != nil {
+		// post state root before byzantium
+		vv.Set(arena.NewCopyBytes(r.Root[:]))
+	} else if r.Status == 1 {
+		vv.Set(arena.NewCopyBytes([]byte{0x1}))
+	} else {
+		vv.Set(arena.NewNull())
+	}
+	vv.Set(arena.NewUint(r.CumulativeGasUsed))
+
+	if r.LogsBloom == nil {
+		vv.Set(arena.NewCopyBytes(make([]byte, 256)))
+	} else {
+		if len(r.LogsBloom) != 256 {
+			return nil, fmt.Errorf("logs bloom has %d bytes but 256 expected", len(r.LogsBloom))
+		}
+		vv.Set(arena.NewCopyBytes(r.LogsBloom))
+	}
+
+	if len(r.Logs) == 0 {
+		vv.Set(arena.NewNullArray())
+	} else {
+		logs := arena.NewArray()
+		for _, log := range r.Logs {
+			v, err := log.MarshalRLPWith(arena)
+			if err != nil {
+				return nil, err
+			}
+			logs.Set(v)
+		}
+		vv.Set(logs)
+	}
+	return vv, nil
+}
+
+// UnmarshalRLP unmarshals the consensus encoding of the receipt. Only the
+// consensus fields (type, status or root, cumulative gas used, bloom and logs)
+// are set.
+func (r *Receipt) UnmarshalRLP(buf []byte) error {
+	r.Type = TransactionLegacy
+	if len(buf) != 0 && buf[0] <= 0x7f {
+		// it includes a type byte
+		switch typ := TransactionType(buf[0]); typ {
+		case TransactionAccessList, TransactionDynamicFee, TransactionBlob:
+			r.Type = typ
+		default:
+			return fmt.Errorf("type byte %d not found", typ)
+		}
+		buf = buf[1:]
+	}
+	if err := fastrlp.UnmarshalRLP(buf, r); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UnmarshalRLPWith unmarshals the receipt from a fastrlp.Value
+func (r *Receipt) UnmarshalRLPWith(v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+	if len(elems) != 4 {
+		return fmt.Errorf("incorrect number of elements to decode receipt, expected 4 but found %d", len(elems))
+	}
+
+	// status or root
+	buf, err := elems[0].Bytes()
+	if err != nil {
+		return err
+	}
+	r.Root = nil
+	r.Status = 0
+	switch size := len(buf); {
+	case size == 32:
+		r.Root = &Hash{}
+		copy(r.Root[:], buf)
+	case size == 0:
+	case size == 1 && buf[0] == 0x1:
+		r.Status = 1
+	default:
+		return fmt.Errorf("invalid receipt status %x", buf)
+	}
+
+	if r.CumulativeGasUsed, err = elems[1].GetUint64(); err != nil {
+		return err
+	}
+	if r.LogsBloom, err = elems[2].GetBytes(r.LogsBloom[:0], 256); err != nil {
+		return err
+	}
+
+	r.Logs = r.Logs[:0]
+	if elems[3].Type() != fastrlp.TypeArrayNull {
+		logElems, err := elems[3].GetElems()
+		if err != nil {
+			return err
+		}
+		for _, elem := range logElems {
+			log := new(Log)
+			if err := log.UnmarshalRLPWith(elem); err != nil {
+				return err
+			}
+			r.Logs = append(r.Logs, log)
+		}
+	}
+	return nil
+}
+
+// MarshalRLPWith marshals the consensus fields of the log (address,
+// topics and data) to RLP with a specific fastrlp.Arena
+func (l *Log) MarshalRLPWith(arena *fastrlp.Arena) (*fastrlp.Value, error) {
+	vv := arena.NewArray()
+	vv.Set(arena.NewCopyBytes(l.Address[:]))
+
+	if len(l.Topics) == 0 {
+		vv.Set(arena.NewNullArray())
+	} else {
+		topics := arena.NewArray()
+		for _, topic := range l.Topics {
+			topics.Set(arena.NewCopyBytes(topic[:]))
+		}
+		vv.Set(topics)
+	}
+	vv.Set(arena.NewCopyBytes(l.Data))
+	return vv, nil
+}
+
+// UnmarshalRLPWith unmarshals the consensus fields of the log from a fastrlp.Value
+func (l *Log) UnmarshalRLPWith(v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+	if len(elems) != 3 {
+		return fmt.Errorf("incorrect number of elements to decode log, expected 3 but found %d", len(elems))
+	}
+	if err = elems[0].GetAddr(l.Address[:]); err != nil {
+		return err
+	}
+
+	l.Topics = l.Topics[:0]
+	if elems[1].Type() != fastrlp.TypeArrayNull {
+		topicElems, err := elems[1].GetElems()
+		if err != nil {
+			return err
+		}
+		for _, elem := range topicElems {
+			var topic Hash
+			if err = elem.GetHash(topic[:]); err != nil {
+				return err
+			}
+			l.Topics = append(l.Topics, topic)
+		}
+	}
+	if l.Data, err = elems[2].GetBytes(l.Data[:0]); err != nil {
+		return err
+	}
+	return nil
+}
+
+// MarshalRLPTo marshals the withdrawal to a []byte destination
+func (w *Withdrawal) MarshalRLPTo(dst []byte) ([]byte, error) {
+	raw, err := fastrlp.MarshalRLP(w)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, raw...), nil
+}
+
+// MarshalRLPWith marshals the withdrawal to RLP with a specific fastrlp.Arena
+func (w *Withdrawal) MarshalRLPWith(arena *fastrlp.Arena) (*fastrlp.Value, error) {
+	vv := arena.NewArray()
+	vv.Set(arena.NewUint(w.Index))
+	vv.Set(arena.NewUint(w.ValidatorIndex))
+	vv.Set(arena.NewCopyBytes(w.Address[:]))
+	vv.Set(arena.NewUint(w.Amount))
+	return vv, nil
+}
+
+// UnmarshalRLP unmarshals the withdrawal from RLP
+func (w *Withdrawal) UnmarshalRLP(buf []byte) error {
+	return fastrlp.UnmarshalRLP(buf, w)
+}
+
+// UnmarshalRLPWith unmarshals the withdrawal from a fastrlp.Value
+func (w *Withdrawal) UnmarshalRLPWith(v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+	if len(elems) != 4 {
+		return fmt.Errorf("incorrect number of elements to decode withdrawal, expected 4 but found %d", len(elems))
+	}
+	if w.Index, err = elems[0].GetUint64(); err != nil {
+		return err
+	}
+	if w.ValidatorIndex, err = elems[1].GetUint64(); err != nil {
+		return err
+	}
+	if err = elems[2].GetAddr(w.Address[:]); err != nil {
+		return err
+	}
+	if w.Amount, err = elems[3].GetUint64(); err != nil {
+		return err
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package ethgo
 
 import (
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -40,6 +42,9 @@ func TestEncodingRLP_Transaction_Fuzz(t *testing.T) {
 	t.Run("dynamicfee", func(t *testing.T) {
 		testTransaction(t, TransactionDynamicFee)
 	})
+	t.Run("blob", func(t *testing.T) {
+		testTransaction(t, TransactionBlob)
+	})
 }
 
 func TestEncodingRLP_AccessList_Fuzz(t *testing.T) {
@@ -48,3 +53,121 @@ func TestEncodingRLP_AccessList_Fuzz(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestEncodingRLP_BlockHeader(t *testing.T) {
+	// headers of each fork of a chain built by go-ethereum,
+	// the hash in the files is the one computed by go-ethereum
+	cases := []struct {
+		name   string
+		fields int
+	}{
+		{"block-byzantium.json", headerFieldsLegacy},
+		{"block-london.json", headerFieldsLondon},
+		{"block-paris.json", headerFieldsLondon},
+		{"block-shanghai.json", headerFieldsShanghai},
+		{"block-cancun.json", headerFieldsCancun},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			data, err := ioutil.ReadFile(filepath.Join("./testsuite", c.name))
+			assert.NoError(t, err)
+
+			b := new(Block)
+			assert.NoError(t, b.UnmarshalJSON(data))
+			assert.Equal(t, c.fields, b.numHeaderFields())
+
+			hash, err := b.ComputeHash()
+			assert.NoError(t, err)
+			assert.Equal(t, b.Hash, hash)
+
+			// the header decoded from rlp has the same hash
+			raw, err := b.MarshalRLPTo(nil)
+			assert.NoError(t, err)
+
+			b2 := new(Block)
+			assert.NoError(t, b2.UnmarshalRLP(raw))
+			assert.Equal(t, b.Hash, b2.Hash)
+			assert.Equal(t, b.BaseFee, b2.BaseFee)
+			assert.Equal(t, b.WithdrawalsRoot, b2.WithdrawalsRoot)
+			assert.Equal(t, b.ParentBeaconRoot, b2.ParentBeaconRoot)
+			assert.Equal(t, b.Nonce, b2.Nonce)
+		})
+	}
+
+	// headers of mainnet with the hash reported by the node, the genesis
+	// is always there and 'make update-testsuite' adds the forks
+	for _, c := range readTestsuite(t, "./testsuite/block-mainnet-*.json") {
+		b := new(Block)
+		assert.NoError(t, b.UnmarshalJSON(c.content))
+
+		hash, err := b.ComputeHash()
+		assert.NoError(t, err)
+		assert.Equal(t, b.Hash, hash, c.name)
+	}
+}
+
+func TestEncodingRLP_BlockHeaderOptionalFields(t *testing.T) {
+	// the fields of the previous forks are encoded if a later one is set
+	requestsHash := Hash{0x1}
+	b := &Block{
+		Number:       1,
+		RequestsHash: &requestsHash,
+	}
+	raw, err := b.MarshalRLPTo(nil)
+	assert.NoError(t, err)
+
+	b2 := new(Block)
+	assert.NoError(t, b2.UnmarshalRLP(raw))
+	assert.Equal(t, headerFieldsPrague, b2.numHeaderFields())
+	assert.Equal(t, uint64(0), b2.BaseFee.Uint64())
+	assert.Equal(t, &Hash{}, b2.WithdrawalsRoot)
+	assert.Equal(t, uint64(0), *b2.BlobGasUsed)
+	assert.Equal(t, &requestsHash, b2.RequestsHash)
+
+	// invalid number of fields
+	assert.Error(t, b2.UnmarshalRLP([]byte{0xc1, 0x80}))
+}
+
+func TestEncodingRLP_Receipt(t *testing.T) {
+	// hash of the consensus encoding computed by go-ethereum
+	cases := []struct {
+		name string
+		typ  TransactionType
+		hash string
+	}{
+		{"receipt-legacy.json", TransactionLegacy, "0x79a369ee2ec07ea68eb2530f416e9596ab71b5bfdf7543280917de7185bbf9eb"},
+		{"receipt-root.json", TransactionLegacy, "0x87b182a1fdc2802fd718a2466fde1b1fe65e441cf9097e7e498dd2d6e17564e9"},
+		{"receipt-contract-creation.json", TransactionLegacy, "0x400424e093c6dfb25bd9c34d4b6f6de5354b46e15116dcb57ad0c9cf10627eab"},
+		{"receipt-eip2930.json", TransactionAccessList, "0xac0d2114644b6bdb2e8dfdf5a74c90871e98afb5c440c3154edc8244438a1ff1"},
+		{"receipt-eip1559.json", TransactionDynamicFee, "0x3b7a1ab762581e278b2124d0cb6ba015dc827492bd837e36518ea88a69d863fe"},
+		{"receipt-eip4844.json", TransactionBlob, "0x42b1e3a05e22bc1e4277eac7204411bb89c2b65a02b339252b20962cf6749e43"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			data, err := ioutil.ReadFile(filepath.Join("./testsuite", c.name))
+			assert.NoError(t, err)
+
+			r := new(Receipt)
+			assert.NoError(t, r.UnmarshalJSON(data))
+			assert.Equal(t, c.typ, r.Type)
+
+			raw, err := r.MarshalRLPTo(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, c.hash, BytesToHash(Keccak256(raw)).String())
+
+			r2 := new(Receipt)
+			assert.NoError(t, r2.UnmarshalRLP(raw))
+			assert.Equal(t, r.Type, r2.Type)
+			assert.Equal(t, r.Status, r2.Status)
+			assert.Equal(t, r.Root, r2.Root)
+			assert.Equal(t, r.CumulativeGasUsed, r2.CumulativeGasUsed)
+			assert.Equal(t, r.LogsBloom, r2.LogsBloom)
+			assert.Len(t, r2.Logs, len(r.Logs))
+			for indx, log := range r2.Logs {
+				assert.Equal(t, r.Logs[indx].Address, log.Address)
+				assert.Equal(t, r.Logs[indx].Topics, log.Topics)
+				assert.Equal(t, r.Logs[indx].Data, log.Data)
+			}
+		})
+	}
+}
@@ -93,4 +93,43 @@ func TestLogFilter_MarshalJSON(t *testing.T) {
 			assert.Equal(t, defaultLogFilter, reverseOutput)
 		})
 	}
+
+	t.Run("multiple addresses", func(t *testing.T) {
+		filter := &LogFilter{
+			Address: []Address{HexToAddress("0x1"), HexToAddress("0x2")},
+		}
+
+		output, err := filter.MarshalJSON()
+		assert.NoError(t, err)
+
+		reverseOutput := &LogFilter{}
+		assert.NoError(t, json.Unmarshal(output, reverseOutput))
+		assert.Equal(t, filter.Address, reverseOutput.Address)
+	})
+	t.Run("block tags", func(t *testing.T) {
+		filter := &LogFilter{}
+		filter.SetFromUint64(10)
+		filter.SetTo(Finalized)
+
+		output, err := filter.MarshalJSON()
+		assert.NoError(t, err)
+		assert.Contains(t, string(output), `"toBlock":"finalized"`)
+
+		reverseOutput := &LogFilter{}
+		assert.NoError(t, json.Unmarshal(output, reverseOutput))
+		assert.Equal(t, BlockNumber(10), *reverseOutput.From)
+		assert.Equal(t, Finalized, *reverseOutput.To)
+	})
+}
+
+func TestBlockHashParam_MarshalJSON(t *testing.T) {
+	hash := HexToHash("0x1")
+
+	output, err := json.Marshal(BlockHashParam{Hash: hash})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"blockHash":"`+hash.String()+`"}`, string(output))
+
+	output, err = json.Marshal(BlockHashParam{Hash: hash, RequireCanonical: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"blockHash":"`+hash.String()+`","requireCanonical":true}`, string(output))
 }
@@ -1,6 +1,9 @@
 package ethgo
 
 import (
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -42,3 +45,50 @@ func TestAddress_HexToString(t *testing.T) {
 func TestHash_HexToString(t *testing.T) {
 	assert.Equal(t, HexToHash("1").String(), "0x0000000000000000000000000000000000000000000000000000000000000001")
 }
+
+func TestBlockNumber_Parse(t *testing.T) {
+	for _, b := range []BlockNumber{Latest, Earliest, Pending, Safe, Finalized, 0, 100} {
+		res, err := ParseBlockNumber(b.String())
+		assert.NoError(t, err)
+		assert.Equal(t, b, res)
+	}
+
+	_, err := ParseBlockNumber("100")
+	assert.Error(t, err)
+
+	_, err = ParseBlockNumber("unsafe")
+	assert.Error(t, err)
+}
+
+func TestReceipt_UnmarshalJSON(t *testing.T) {
+	decode := func(name string) *Receipt {
+		data, err := ioutil.ReadFile(filepath.Join("./testsuite", name))
+		assert.NoError(t, err)
+
+		r := new(Receipt)
+		assert.NoError(t, r.UnmarshalJSON(data))
+		return r
+	}
+
+	r := decode("receipt-eip1559.json")
+	assert.Equal(t, TransactionDynamicFee, r.Type)
+	assert.Equal(t, uint64(1), r.Status)
+	assert.Nil(t, r.Root)
+	assert.Equal(t, HexToAddress("0xdac17f958d2ee523a2206206994597c13d831ec7"), *r.To)
+	assert.Equal(t, big.NewInt(0x3b9aca07), r.EffectiveGasPrice)
+	assert.Equal(t, new(big.Int).Mul(big.NewInt(0xb0b0), big.NewInt(0x3b9aca07)), r.Fee())
+
+	// pre-byzantium receipts have the state root instead of the status
+	r = decode("receipt-root.json")
+	assert.Equal(t, HexToHash("0xabcdef"), *r.Root)
+
+	r = decode("receipt-contract-creation.json")
+	assert.Nil(t, r.To)
+	assert.Equal(t, uint64(0), r.Status)
+
+	r = decode("receipt-eip4844.json")
+	assert.Equal(t, TransactionBlob, r.Type)
+	assert.Equal(t, uint64(0x20000), r.BlobGasUsed)
+	assert.Equal(t, big.NewInt(1), r.BlobGasPrice)
+	assert.Equal(t, new(big.Int).Add(new(big.Int).Mul(big.NewInt(0xb0b0), big.NewInt(0x3b9aca07)), big.NewInt(0x20000)), r.Fee())
+}
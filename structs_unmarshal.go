@@ -62,6 +62,82 @@ func (b *Block) UnmarshalJSON(buf []byte) error {
 		return err
 	}
 
+	// optional fields depending on the fork and the client
+	b.MixHash = nil
+	if isKeySet(v, "mixHash") {
+		b.MixHash = &Hash{}
+		if err := decodeHash(b.MixHash, v, "mixHash"); err != nil {
+			return err
+		}
+	}
+	b.Nonce = nil
+	if isKeySet(v, "nonce") {
+		if b.Nonce, err = decodeBytes(nil, v, "nonce", 8); err != nil {
+			return err
+		}
+	}
+	b.LogsBloom = nil
+	if isKeySet(v, "logsBloom") {
+		if b.LogsBloom, err = decodeBytes(nil, v, "logsBloom", 256); err != nil {
+			return err
+		}
+	}
+	b.BaseFee = nil
+	if isKeySet(v, "baseFeePerGas") {
+		if b.BaseFee, err = decodeBigInt(nil, v, "baseFeePerGas"); err != nil {
+			return err
+		}
+	}
+	b.WithdrawalsRoot = nil
+	if isKeySet(v, "withdrawalsRoot") {
+		b.WithdrawalsRoot = &Hash{}
+		if err := decodeHash(b.WithdrawalsRoot, v, "withdrawalsRoot"); err != nil {
+			return err
+		}
+	}
+	b.BlobGasUsed = nil
+	if isKeySet(v, "blobGasUsed") {
+		blobGasUsed, err := decodeUint(v, "blobGasUsed")
+		if err != nil {
+			return err
+		}
+		b.BlobGasUsed = &blobGasUsed
+	}
+	b.ExcessBlobGas = nil
+	if isKeySet(v, "excessBlobGas") {
+		excessBlobGas, err := decodeUint(v, "excessBlobGas")
+		if err != nil {
+			return err
+		}
+		b.ExcessBlobGas = &excessBlobGas
+	}
+	b.ParentBeaconRoot = nil
+	if isKeySet(v, "parentBeaconBlockRoot") {
+		b.ParentBeaconRoot = &Hash{}
+		if err := decodeHash(b.ParentBeaconRoot, v, "parentBeaconBlockRoot"); err != nil {
+			return err
+		}
+	}
+	b.RequestsHash = nil
+	if isKeySet(v, "requestsHash") {
+		b.RequestsHash = &Hash{}
+		if err := decodeHash(b.RequestsHash, v, "requestsHash"); err != nil {
+			return err
+		}
+	}
+	b.Size = 0
+	if isKeySet(v, "size") {
+		if b.Size, err = decodeUint(v, "size"); err != nil {
+			return err
+		}
+	}
+	b.TotalDifficulty = nil
+	if isKeySet(v, "totalDifficulty") {
+		if b.TotalDifficulty, err = decodeBigInt(nil, v, "totalDifficulty"); err != nil {
+			return err
+		}
+	}
+
 	b.TransactionsHashes = b.TransactionsHashes[:0]
 	b.Transactions = b.Transactions[:0]
 
@@ -98,6 +174,48 @@ func (b *Block) UnmarshalJSON(buf []byte) error {
 		b.Uncles = append(b.Uncles, h)
 	}
 
+	// withdrawals
+	b.Withdrawals = nil
+	if isKeySet(v, "withdrawals") {
+		b.Withdrawals = []*Withdrawal{}
+		for _, elem := range v.GetArray("withdrawals") {
+			w := new(Withdrawal)
+			if err := w.unmarshalJSON(elem); err != nil {
+				return err
+			}
+			b.Withdrawals = append(b.Withdrawals, w)
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalJSON implements the unmarshal interface
+func (w *Withdrawal) UnmarshalJSON(buf []byte) error {
+	p := defaultPool.Get()
+	defer defaultPool.Put(p)
+
+	v, err := p.Parse(string(buf))
+	if err != nil {
+		return err
+	}
+	return w.unmarshalJSON(v)
+}
+
+func (w *Withdrawal) unmarshalJSON(v *fastjson.Value) error {
+	var err error
+	if w.Index, err = decodeUint(v, "index"); err != nil {
+		return err
+	}
+	if w.ValidatorIndex, err = decodeUint(v, "validatorIndex"); err != nil {
+		return err
+	}
+	if err := decodeAddr(&w.Address, v, "address"); err != nil {
+		return err
+	}
+	if w.Amount, err = decodeUint(v, "amount"); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -130,10 +248,20 @@ func (t *Transaction) unmarshalJSON(v *fastjson.Value) error {
 		return nil
 	}
 
-	// detect transaction type
+	// detect transaction type. The nodes return the chain id
+	// of the legacy transactions too, it is only used to detect
+	// the type if the 'type' field is not set.
 	var typ TransactionType
-	if isKeySet(v, "chainId") {
-		if isKeySet(v, "maxFeePerGas") {
+	if isKeySet(v, "type") {
+		num, err := decodeUint(v, "type")
+		if err != nil {
+			return err
+		}
+		typ = TransactionType(num)
+	} else if isKeySet(v, "chainId") {
+		if isKeySet(v, "maxFeePerBlobGas") {
+			typ = TransactionBlob
+		} else if isKeySet(v, "maxFeePerGas") {
 			typ = TransactionDynamicFee
 		} else {
 			typ = TransactionAccessList
@@ -199,14 +327,30 @@ func (t *Transaction) unmarshalJSON(v *fastjson.Value) error {
 		}
 	}
 
-	if typ == TransactionDynamicFee {
+	if typ == TransactionDynamicFee || typ == TransactionBlob {
 		if t.MaxPriorityFeePerGas, err = decodeBigInt(t.MaxPriorityFeePerGas, v, "maxPriorityFeePerGas"); err != nil {
 			return err
 		}
 		if t.MaxFeePerGas, err = decodeBigInt(t.MaxFeePerGas, v, "maxFeePerGas"); err != nil {
 			return err
 		}
-	} else {
+	}
+	if typ == TransactionBlob {
+		if t.MaxFeePerBlobGas, err = decodeBigInt(t.MaxFeePerBlobGas, v, "maxFeePerBlobGas"); err != nil {
+			return err
+		}
+		t.BlobVersionedHashes = t.BlobVersionedHashes[:0]
+		for _, elem := range v.GetArray("blobVersionedHashes") {
+			var hash Hash
+			if err := hash.UnmarshalText(elem.GetStringBytes()); err != nil {
+				return err
+			}
+			t.BlobVersionedHashes = append(t.BlobVersionedHashes, hash)
+		}
+	}
+
+	// the gas limit is optional for the fee market transactions
+	if isKeySet(v, "gas") || (typ != TransactionDynamicFee && typ != TransactionBlob) {
 		if t.Gas, err = decodeUint(v, "gas"); err != nil {
 			return err
 		}
@@ -306,10 +450,53 @@ func (r *Receipt) UnmarshalJSON(buf []byte) error {
 	if r.LogsBloom, err = decodeBytes(r.LogsBloom[:0], v, "logsBloom", 256); err != nil {
 		return err
 	}
-	if r.Status, err = decodeUint(v, "status"); err != nil {
+
+	// status (or root before byzantium)
+	r.Root = nil
+	r.Status = 0
+	if isKeySet(v, "root") && !isKeySet(v, "status") {
+		r.Root = &Hash{}
+		if err := decodeHash(r.Root, v, "root"); err != nil {
+			return err
+		}
+	} else if r.Status, err = decodeUint(v, "status"); err != nil {
 		return err
 	}
 
+	r.Type = TransactionLegacy
+	if isKeySet(v, "type") {
+		typ, err := decodeUint(v, "type")
+		if err != nil {
+			return err
+		}
+		r.Type = TransactionType(typ)
+	}
+	r.To = nil
+	if isKeySet(v, "to") {
+		r.To = &Address{}
+		if err := decodeAddr(r.To, v, "to"); err != nil {
+			return err
+		}
+	}
+	r.EffectiveGasPrice = nil
+	if isKeySet(v, "effectiveGasPrice") {
+		if r.EffectiveGasPrice, err = decodeBigInt(nil, v, "effectiveGasPrice"); err != nil {
+			return err
+		}
+	}
+	r.BlobGasUsed = 0
+	if isKeySet(v, "blobGasUsed") {
+		if r.BlobGasUsed, err = decodeUint(v, "blobGasUsed"); err != nil {
+			return err
+		}
+	}
+	r.BlobGasPrice = nil
+	if isKeySet(v, "blobGasPrice") {
+		if r.BlobGasPrice, err = decodeBigInt(nil, v, "blobGasPrice"); err != nil {
+			return err
+		}
+	}
+
 	// logs
 	r.Logs = r.Logs[:0]
 	for _, elem := range v.GetArray("logs") {
@@ -377,13 +564,10 @@ func (lf *LogFilter) UnmarshalJSON(buf []byte) error {
 
 	// decodeBlockNum is a helper method for extracting a BlockNumber
 	decodeBlockNum := func(key string) (*BlockNumber, error) {
-		numRaw, err := decodeInt64(v, key)
+		blockNum, err := ParseBlockNumber(string(v.GetStringBytes(key)))
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("field '%s': %v", key, err)
 		}
-
-		blockNum := BlockNumber(numRaw)
-
 		return &blockNum, nil
 	}
 
@@ -493,6 +677,85 @@ func (r *Log) unmarshalJSON(v *fastjson.Value) error {
 	return nil
 }
 
+// UnmarshalJSON implements the unmarshal interface
+func (a *AccountProof) UnmarshalJSON(buf []byte) error {
+	p := defaultPool.Get()
+	defer defaultPool.Put(p)
+
+	v, err := p.Parse(string(buf))
+	if err != nil {
+		return err
+	}
+
+	if err := decodeAddr(&a.Address, v, "address"); err != nil {
+		return err
+	}
+	if a.AccountProof, err = decodeProof(v, "accountProof"); err != nil {
+		return err
+	}
+	if a.Balance, err = decodeBigInt(a.Balance, v, "balance"); err != nil {
+		return err
+	}
+	if err := decodeHash(&a.CodeHash, v, "codeHash"); err != nil {
+		return err
+	}
+	if a.Nonce, err = decodeUint(v, "nonce"); err != nil {
+		return err
+	}
+	if err := decodeHash(&a.StorageHash, v, "storageHash"); err != nil {
+		return err
+	}
+
+	a.StorageProof = a.StorageProof[:0]
+	for _, elem := range v.GetArray("storageProof") {
+		proof := new(StorageProof)
+		if err := proof.unmarshalJSON(elem); err != nil {
+			return err
+		}
+		a.StorageProof = append(a.StorageProof, proof)
+	}
+	return nil
+}
+
+func (s *StorageProof) unmarshalJSON(v *fastjson.Value) error {
+	// the key is returned as it was requested and it may not be 32 bytes long
+	key, err := decodeBigInt(nil, v, "key")
+	if err != nil {
+		return err
+	}
+	if key.BitLen() > 256 {
+		return fmt.Errorf("storage key '%s' is longer than 32 bytes", key)
+	}
+	key.FillBytes(s.Key[:])
+
+	if s.Value, err = decodeBigInt(s.Value, v, "value"); err != nil {
+		return err
+	}
+	if s.Proof, err = decodeProof(v, "proof"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func decodeProof(v *fastjson.Value, key string) ([][]byte, error) {
+	if !v.Exists(key) {
+		return nil, fmt.Errorf("field '%s' not found", key)
+	}
+	proof := [][]byte{}
+	for _, elem := range v.GetArray(key) {
+		str := strings.Trim(elem.String(), "\"")
+		if !strings.HasPrefix(str, "0x") {
+			return nil, fmt.Errorf("field '%s' does not have 0x prefix: '%s'", key, str)
+		}
+		buf, err := hex.DecodeString(str[2:])
+		if err != nil {
+			return nil, err
+		}
+		proof = append(proof, buf)
+	}
+	return proof, nil
+}
+
 func fieldNotFull(v *fastjson.Value, key string) bool {
 	vv := v.Get(key)
 	if vv == nil {
@@ -567,21 +830,6 @@ func decodeUint(v *fastjson.Value, key string) (uint64, error) {
 	return strconv.ParseUint(str[2:], 16, 64)
 }
 
-func decodeInt64(v *fastjson.Value, key string) (int64, error) {
-	vv := v.Get(key)
-	if vv == nil {
-		return 0, fmt.Errorf("field '%s' not found", key)
-	}
-	str := vv.String()
-	str = strings.Trim(str, "\"")
-
-	if !strings.HasPrefix(str, "0x") {
-		return 0, fmt.Errorf("field '%s' does not have 0x prefix: '%s'", key, str)
-	}
-
-	return strconv.ParseInt(str[2:], 16, 64)
-}
-
 func decodeHash(h *Hash, v *fastjson.Value, key string) error {
 	b := v.GetStringBytes(key)
 	if len(b) == 0 {
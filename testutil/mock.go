@@ -27,12 +27,23 @@ type MockClient struct {
 	blocks   map[ethgo.Hash]*ethgo.Block
 	logs     map[ethgo.Hash][]*ethgo.Log
 	chainID  *big.Int
+
+	// finalized is the number of the safe and finalized block (if any)
+	finalized *uint64
 }
 
 func (m *MockClient) SetChainID(id *big.Int) {
 	m.chainID = id
 }
 
+// SetFinalized sets the number of the block returned for the safe and finalized tags
+func (m *MockClient) SetFinalized(num uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.finalized = &num
+}
+
 func (d *MockClient) ChainID() (*big.Int, error) {
 	if d.chainID == nil {
 		d.chainID = big.NewInt(1337)
@@ -167,6 +178,11 @@ func (d *MockClient) GetBlockByNumber(i ethgo.BlockNumber, full bool) (*ethgo.Bl
 				return &ethgo.Block{Number: 0}, nil
 			}
 			return d.blockByNumberLock(d.num)
+		case ethgo.Safe, ethgo.Finalized:
+			if d.finalized == nil {
+				return nil, fmt.Errorf("finalized block not set")
+			}
+			return d.blockByNumberLock(*d.finalized)
 		default:
 			return nil, fmt.Errorf("getBlockByNumber query not supported")
 		}
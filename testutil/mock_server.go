@@ -0,0 +1,204 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/git-yongge/ethgo/jsonrpc/codec"
+	"github.com/gorilla/websocket"
+)
+
+// MockHandler answers a jsonrpc request with its decoded params
+type MockHandler func(params []json.RawMessage) (interface{}, error)
+
+// MockServer is an http and websocket jsonrpc server that answers the
+// requests with the handlers registered for each method
+type MockServer struct {
+	lock     sync.Mutex
+	srv      *httptest.Server
+	handlers map[string]MockHandler
+	calls    map[string]int
+
+	wsLock  sync.Mutex
+	wsConns []*websocket.Conn
+}
+
+// NewMockServer creates and starts a new mock jsonrpc server
+func NewMockServer() *MockServer {
+	m := &MockServer{
+		handlers: map[string]MockHandler{},
+		calls:    map[string]int{},
+	}
+	m.srv = httptest.NewServer(http.HandlerFunc(m.serveHTTP))
+	return m
+}
+
+// HTTPAddr returns the http endpoint of the server
+func (m *MockServer) HTTPAddr() string {
+	return m.srv.URL
+}
+
+// WsAddr returns the websocket endpoint of the server
+func (m *MockServer) WsAddr() string {
+	return "ws" + strings.TrimPrefix(m.srv.URL, "http")
+}
+
+// Close stops the server
+func (m *MockServer) Close() {
+	m.wsLock.Lock()
+	for _, conn := range m.wsConns {
+		conn.Close()
+	}
+	m.wsLock.Unlock()
+
+	m.srv.Close()
+}
+
+// Notify sends a subscription message to all the websocket connections
+func (m *MockServer) Notify(id string, result interface{}) error {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	params, err := json.Marshal(&codec.Subscription{ID: id, Result: raw})
+	if err != nil {
+		return err
+	}
+
+	m.wsLock.Lock()
+	defer m.wsLock.Unlock()
+
+	for _, conn := range m.wsConns {
+		if err := conn.WriteJSON(&codec.Request{JsonRPC: "2.0", Method: "eth_subscription", Params: params}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handle registers the handler for a jsonrpc method
+func (m *MockServer) Handle(method string, handler MockHandler) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.handlers[method] = handler
+}
+
+// HandleResult registers a handler that always returns the same result
+func (m *MockServer) HandleResult(method string, result interface{}) {
+	m.Handle(method, func(params []json.RawMessage) (interface{}, error) {
+		return result, nil
+	})
+}
+
+// Calls returns the number of requests received for a method
+func (m *MockServer) Calls(method string) int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.calls[method]
+}
+
+func (m *MockServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		m.serveWs(w, r)
+		return
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var resp interface{}
+	if len(data) != 0 && data[0] == '[' {
+		var reqs []*codec.Request
+		if err := json.Unmarshal(data, &reqs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resps := []*codec.Response{}
+		for _, req := range reqs {
+			resps = append(resps, m.handle(req))
+		}
+		resp = resps
+	} else {
+		var req *codec.Request
+		if err := json.Unmarshal(data, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resp = m.handle(req)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (m *MockServer) serveWs(w http.ResponseWriter, r *http.Request) {
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	m.wsLock.Lock()
+	m.wsConns = append(m.wsConns, conn)
+	m.wsLock.Unlock()
+
+	for {
+		var req *codec.Request
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		resp := m.handle(req)
+
+		m.wsLock.Lock()
+		err := conn.WriteJSON(resp)
+		m.wsLock.Unlock()
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (m *MockServer) handle(req *codec.Request) *codec.Response {
+	m.lock.Lock()
+	handler, ok := m.handlers[req.Method]
+	m.calls[req.Method]++
+	m.lock.Unlock()
+
+	resp := &codec.Response{ID: req.ID}
+	if !ok {
+		resp.Error = &codec.ErrorObject{Code: -32601, Message: fmt.Sprintf("method %s not found", req.Method)}
+		return resp
+	}
+
+	var params []json.RawMessage
+	if len(req.Params) != 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			resp.Error = &codec.ErrorObject{Code: -32602, Message: err.Error()}
+			return resp
+		}
+	}
+
+	result, err := handler(params)
+	if err != nil {
+		obj, ok := err.(*codec.ErrorObject)
+		if !ok {
+			obj = &codec.ErrorObject{Code: -32000, Message: err.Error()}
+		}
+		resp.Error = obj
+		return resp
+	}
+	if resp.Result, err = json.Marshal(result); err != nil {
+		resp.Error = &codec.ErrorObject{Code: -32603, Message: err.Error()}
+	}
+	return resp
+}
@@ -0,0 +1,128 @@
+package tracker
+
+import (
+	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/abi"
+)
+
+// DecodedEvent is a log decoded with the events of the ABIs of the filter
+type DecodedEvent struct {
+	// Name is the name of the event
+	Name string
+
+	// Event is the abi event that decoded the log
+	Event *abi.Event
+
+	// Args are the decoded arguments of the event
+	Args map[string]interface{}
+
+	Address          ethgo.Address
+	BlockNumber      uint64
+	BlockHash        ethgo.Hash
+	TransactionHash  ethgo.Hash
+	TransactionIndex uint64
+	LogIndex         uint64
+
+	// Removed is true if the log was removed from the chain in a reorg
+	Removed bool
+
+	// Log is the raw log
+	Log *ethgo.Log
+}
+
+// EventHandler is a function that handles a decoded event
+type EventHandler func(evnt *DecodedEvent)
+
+// eventDecoder decodes the logs with the events of a list of ABIs
+type eventDecoder struct {
+	events map[ethgo.Hash][]*abi.Event
+}
+
+func newEventDecoder(abis []*abi.ABI) *eventDecoder {
+	d := &eventDecoder{
+		events: map[ethgo.Hash][]*abi.Event{},
+	}
+	for _, a := range abis {
+		for _, evnt := range a.Events {
+			if evnt.Anonymous {
+				continue
+			}
+			id := evnt.ID()
+			d.events[id] = append(d.events[id], evnt)
+		}
+	}
+	return d
+}
+
+// decode decodes the log with the first event that matches the topics and the
+// data of the log. Events with the same signature but different indexed arguments
+// (i.e. ERC20 and ERC721 transfers) are disambiguated by the number of topics.
+func (d *eventDecoder) decode(log *ethgo.Log, removed bool) *DecodedEvent {
+	if len(log.Topics) == 0 {
+		return nil
+	}
+	for _, evnt := range d.events[log.Topics[0]] {
+		args, err := evnt.ParseLog(log)
+		if err != nil {
+			continue
+		}
+		return &DecodedEvent{
+			Name:             evnt.Name,
+			Event:            evnt,
+			Args:             args,
+			Address:          log.Address,
+			BlockNumber:      log.BlockNumber,
+			BlockHash:        log.BlockHash,
+			TransactionHash:  log.TransactionHash,
+			TransactionIndex: log.TransactionIndex,
+			LogIndex:         log.LogIndex,
+			Removed:          removed,
+			Log:              log,
+		}
+	}
+	return nil
+}
+
+// HandleEvent registers a handler for the decoded events with the given name.
+// An empty name registers the handler for all the decoded events. The handlers
+// are called in order before the event is emitted.
+func (t *Tracker) HandleEvent(name string, handler EventHandler) {
+	t.handlersLock.Lock()
+	defer t.handlersLock.Unlock()
+
+	t.handlers[name] = append(t.handlers[name], handler)
+}
+
+// decodeEvent decodes the removed and the added logs of the event
+func (t *Tracker) decodeEvent(evnt *Event) []*DecodedEvent {
+	res := []*DecodedEvent{}
+	for _, log := range evnt.Removed {
+		if decoded := t.decoder.decode(log, true); decoded != nil {
+			res = append(res, decoded)
+		}
+	}
+	for _, log := range evnt.Added {
+		if decoded := t.decoder.decode(log, false); decoded != nil {
+			res = append(res, decoded)
+		}
+	}
+	return res
+}
+
+func (t *Tracker) handleDecoded(events []*DecodedEvent) {
+	t.handlersLock.Lock()
+	handlers := make(map[string][]EventHandler, len(t.handlers))
+	for name, list := range t.handlers {
+		handlers[name] = list
+	}
+	t.handlersLock.Unlock()
+
+	for _, evnt := range events {
+		for _, handler := range handlers[evnt.Name] {
+			handler(evnt)
+		}
+		for _, handler := range handlers[""] {
+			handler(evnt)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package tracker
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/abi"
+	"github.com/git-yongge/ethgo/blocktracker"
+	"github.com/git-yongge/ethgo/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	erc20ABI = abi.MustNewABIFromList([]string{
+		"event Transfer(address indexed from, address indexed to, uint256 value)",
+		"event Approval(address indexed owner, address indexed spender, uint256 value)",
+	})
+	erc721ABI = abi.MustNewABIFromList([]string{
+		"event Transfer(address indexed from, address indexed to, uint256 indexed tokenId)",
+	})
+)
+
+func addressTopic(addr ethgo.Address) (h ethgo.Hash) {
+	copy(h[12:], addr[:])
+	return
+}
+
+func uintTopic(n uint64) ethgo.Hash {
+	return ethgo.BytesToHash(new(big.Int).SetUint64(n).Bytes())
+}
+
+func TestTracker_DecodeEvents(t *testing.T) {
+	from, to := ethgo.Address{0x1}, ethgo.Address{0x2}
+
+	value, err := abi.MustNewType("tuple(uint256)").Encode([]interface{}{big.NewInt(100)})
+	assert.NoError(t, err)
+
+	transfer20 := &ethgo.Log{
+		Address:         ethgo.Address{0x10},
+		BlockNumber:     1,
+		TransactionHash: ethgo.Hash{0x1},
+		LogIndex:        2,
+		Topics:          []ethgo.Hash{erc20ABI.Events["Transfer"].ID(), addressTopic(from), addressTopic(to)},
+		Data:            value,
+	}
+	transfer721 := &ethgo.Log{
+		Address:     ethgo.Address{0x11},
+		BlockNumber: 2,
+		Topics:      []ethgo.Hash{erc721ABI.Events["Transfer"].ID(), addressTopic(from), addressTopic(to), uintTopic(5)},
+	}
+	approval := &ethgo.Log{
+		Address:     ethgo.Address{0x10},
+		BlockNumber: 3,
+		Topics:      []ethgo.Hash{erc20ABI.Events["Approval"].ID(), addressTopic(from), addressTopic(to)},
+		Data:        value,
+	}
+	unknown := &ethgo.Log{
+		BlockNumber: 4,
+		Topics:      []ethgo.Hash{{0x1}},
+	}
+
+	tt, err := NewTracker(&testutil.MockClient{}, WithFilter(&FilterConfig{
+		Async: true,
+		ABIs:  []*abi.ABI{erc20ABI, erc721ABI},
+	}))
+	assert.NoError(t, err)
+
+	var transfers, all []*DecodedEvent
+	tt.HandleEvent("Transfer", func(evnt *DecodedEvent) {
+		transfers = append(transfers, evnt)
+	})
+	tt.HandleEvent("", func(evnt *DecodedEvent) {
+		all = append(all, evnt)
+	})
+
+	evnt := &Event{
+		Removed: []*ethgo.Log{approval},
+		Added:   []*ethgo.Log{transfer20, transfer721, unknown},
+	}
+	tt.emitEvent(evnt)
+
+	assert.Len(t, evnt.Decoded, 3)
+	assert.Equal(t, evnt.Decoded, all)
+	assert.Len(t, transfers, 2)
+
+	// the removed logs come first
+	removed := evnt.Decoded[0]
+	assert.Equal(t, "Approval", removed.Name)
+	assert.True(t, removed.Removed)
+	assert.Equal(t, uint64(3), removed.BlockNumber)
+
+	// the transfers are decoded with the event that matches the topics
+	erc20 := evnt.Decoded[1]
+	assert.Equal(t, "Transfer", erc20.Name)
+	assert.False(t, erc20.Removed)
+	assert.Equal(t, erc20ABI.Events["Transfer"], erc20.Event)
+	assert.Equal(t, from, erc20.Args["from"])
+	assert.Equal(t, big.NewInt(100), erc20.Args["value"])
+	assert.Equal(t, ethgo.Address{0x10}, erc20.Address)
+	assert.Equal(t, ethgo.Hash{0x1}, erc20.TransactionHash)
+	assert.Equal(t, uint64(2), erc20.LogIndex)
+	assert.Equal(t, transfer20, erc20.Log)
+
+	erc721 := evnt.Decoded[2]
+	assert.Equal(t, erc721ABI.Events["Transfer"], erc721.Event)
+	assert.Equal(t, big.NewInt(5), erc721.Args["tokenId"])
+
+	// the filter hash does not depend on the ABIs
+	tt1, err := NewTracker(&testutil.MockClient{}, WithFilter(&FilterConfig{}))
+	assert.NoError(t, err)
+	assert.Equal(t, tt1.config.Filter.Hash, tt.config.Filter.Hash)
+}
+
+func TestTracker_DecodeEventsSync(t *testing.T) {
+	m := &mockMultiClient{}
+	m.advance(0, 20, "")
+
+	// the logs of the mock chain are erc20 transfers
+	for _, logs := range m.MockClient.GetAllLogs() {
+		logs.Topics = []ethgo.Hash{erc20ABI.Events["Transfer"].ID(), addressTopic(multiAddr0), addressTopic(multiAddr1)}
+		logs.Data, _ = abi.MustNewType("tuple(uint256)").Encode([]interface{}{big.NewInt(int64(logs.BlockNumber))})
+	}
+
+	bt, err := blocktracker.NewBlockTracker(m)
+	assert.NoError(t, err)
+
+	mt, err := NewMultiTracker(m,
+		testConfig(),
+		WithBlockTracker(bt),
+	)
+	assert.NoError(t, err)
+
+	tt, err := mt.AddFilter(&FilterConfig{
+		Async:   true,
+		Address: []ethgo.Address{multiAddr0},
+		ABIs:    []*abi.ABI{erc20ABI},
+	})
+	assert.NoError(t, err)
+
+	var lock sync.Mutex
+	values := []uint64{}
+	tt.HandleEvent("Transfer", func(evnt *DecodedEvent) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		values = append(values, evnt.Args["value"].(*big.Int).Uint64())
+	})
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	assert.NoError(t, mt.Sync(ctx))
+	assert.NoError(t, mt.WaitDuration(time.Second))
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	// the even blocks belong to the address of the filter
+	assert.Equal(t, []uint64{2, 4, 6, 8, 10, 12, 14, 16, 18}, values)
+}
@@ -0,0 +1,458 @@
+package tracker
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/blocktracker"
+	"github.com/git-yongge/ethgo/tracker/store"
+)
+
+var (
+	dbQueue = "queue"
+)
+
+// ConsumerPolicy is the behaviour of the tracker when the consumer
+// of EventCh does not keep up with the events
+type ConsumerPolicy int
+
+const (
+	// ConsumerBlock blocks the sync until the event is consumed
+	ConsumerBlock ConsumerPolicy = iota
+	// ConsumerDrop drops the event if the consumer is not ready.
+	// The number of dropped events is returned by Dropped
+	ConsumerDrop
+	// ConsumerBuffer persists the event in the store if the consumer is not
+	// ready and delivers it later in order, even after a restart
+	ConsumerBuffer
+)
+
+func (p ConsumerPolicy) String() string {
+	switch p {
+	case ConsumerBlock:
+		return "block"
+	case ConsumerDrop:
+		return "drop"
+	case ConsumerBuffer:
+		return "buffer"
+	default:
+		return fmt.Sprintf("ConsumerPolicy(%d)", int(p))
+	}
+}
+
+// lifecycle is the state of the tracker between Start and Stop
+type lifecycle struct {
+	lock      sync.Mutex
+	started   bool
+	stopping  bool
+	cancelFn  context.CancelFunc
+	err       error
+	wg        sync.WaitGroup
+	closeCh   chan struct{}
+	stoppedCh chan struct{}
+
+	// emitLock guards the sends on the channels of the tracker once they are closed
+	emitLock sync.RWMutex
+	closed   bool
+
+	readyOnce    sync.Once
+	doneOnce     sync.Once
+	deliveryOnce sync.Once
+	dropped      uint64
+}
+
+func newLifecycle() *lifecycle {
+	return &lifecycle{
+		closeCh:   make(chan struct{}),
+		stoppedCh: make(chan struct{}),
+	}
+}
+
+// Start starts to sync the tracker in the background until Stop is called or the
+// context is done. An error during the sync stops the tracker and it is returned by Err.
+func (t *Tracker) Start(ctx context.Context) error {
+	l := t.lifecycle
+
+	l.lock.Lock()
+	if l.stopping {
+		l.lock.Unlock()
+		return fmt.Errorf("tracker is stopped")
+	}
+	if l.started {
+		l.lock.Unlock()
+		return fmt.Errorf("tracker already started")
+	}
+	l.started = true
+	ctx, l.cancelFn = context.WithCancel(ctx)
+	l.lock.Unlock()
+
+	if t.policy() == ConsumerBuffer {
+		// deliver the events buffered in a previous run
+		t.startDelivery()
+	}
+
+	t.spawn(func() {
+		if err := t.Sync(ctx); err != nil {
+			t.setErr(err)
+			go t.Stop()
+		}
+	})
+	return nil
+}
+
+// Stop stops the sync and waits for the batch in progress to be committed to
+// the store. The last block is persisted after the logs of each batch so the
+// tracker resumes at the same point on restart. Once stopped, EventCh, SyncCh and
+// BlockCh are closed and the events not consumed yet are lost unless the
+// ConsumerBuffer policy is used.
+func (t *Tracker) Stop() error {
+	l := t.lifecycle
+
+	l.lock.Lock()
+	if l.stopping {
+		l.lock.Unlock()
+		<-l.stoppedCh
+		return t.Err()
+	}
+	l.stopping = true
+	close(l.closeCh)
+	if l.cancelFn != nil {
+		l.cancelFn()
+	}
+	l.lock.Unlock()
+
+	l.wg.Wait()
+
+	l.emitLock.Lock()
+	l.closed = true
+	close(t.EventCh)
+	close(t.SyncCh)
+	close(t.BlockCh)
+	l.emitLock.Unlock()
+
+	l.readyOnce.Do(func() {
+		close(t.ReadyCh)
+	})
+	l.doneOnce.Do(func() {
+		close(t.DoneCh)
+	})
+
+	close(l.stoppedCh)
+	return t.Err()
+}
+
+// Close stops the tracker and closes its store
+func (t *Tracker) Close() error {
+	err := t.Stop()
+	if cErr := t.store.Close(); err == nil {
+		err = cErr
+	}
+	return err
+}
+
+// Err returns the error that stopped the tracker (if any)
+func (t *Tracker) Err() error {
+	t.lifecycle.lock.Lock()
+	defer t.lifecycle.lock.Unlock()
+
+	if errors.Is(t.lifecycle.err, context.Canceled) {
+		return nil
+	}
+	return t.lifecycle.err
+}
+
+func (t *Tracker) setErr(err error) {
+	t.lifecycle.lock.Lock()
+	defer t.lifecycle.lock.Unlock()
+
+	if t.lifecycle.err == nil {
+		t.lifecycle.err = err
+	}
+}
+
+// Dropped returns the number of events dropped because the consumer was not ready
+func (t *Tracker) Dropped() uint64 {
+	return atomic.LoadUint64(&t.lifecycle.dropped)
+}
+
+// spawn runs fn in a goroutine that Stop waits for. It returns false
+// if the tracker is already stopped.
+func (t *Tracker) spawn(fn func()) bool {
+	l := t.lifecycle
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.stopping {
+		return false
+	}
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		fn()
+	}()
+	return true
+}
+
+func (t *Tracker) policy() ConsumerPolicy {
+	if t.config.Filter.Async {
+		return ConsumerDrop
+	}
+	return t.config.ConsumerPolicy
+}
+
+func (t *Tracker) markReady() {
+	t.lifecycle.readyOnce.Do(func() {
+		close(t.ReadyCh)
+	})
+}
+
+func (t *Tracker) markDone() {
+	t.lifecycle.doneOnce.Do(func() {
+		close(t.DoneCh)
+	})
+}
+
+// notifySync sends the last synced block on SyncCh if there is a consumer ready
+func (t *Tracker) notifySync(num uint64) {
+	t.lifecycle.emitLock.RLock()
+	defer t.lifecycle.emitLock.RUnlock()
+
+	if t.lifecycle.closed {
+		return
+	}
+	select {
+	case t.SyncCh <- num:
+	default:
+	}
+}
+
+// notifyBlock sends the block event on BlockCh if there is a consumer ready
+func (t *Tracker) notifyBlock(evnt *blocktracker.BlockEvent) {
+	t.lifecycle.emitLock.RLock()
+	defer t.lifecycle.emitLock.RUnlock()
+
+	if t.lifecycle.closed {
+		return
+	}
+	select {
+	case t.BlockCh <- evnt:
+	default:
+	}
+}
+
+// send delivers the event on EventCh following the consumer policy
+func (t *Tracker) send(evnt *Event) {
+	l := t.lifecycle
+
+	l.emitLock.RLock()
+	defer l.emitLock.RUnlock()
+
+	if l.closed {
+		return
+	}
+
+	switch t.policy() {
+	case ConsumerDrop:
+		select {
+		case t.EventCh <- evnt:
+		default:
+			atomic.AddUint64(&l.dropped, 1)
+		}
+
+	case ConsumerBuffer:
+		t.queue.lock.Lock()
+		defer t.queue.lock.Unlock()
+
+		if t.queue.len() == 0 {
+			select {
+			case t.EventCh <- evnt:
+				return
+			default:
+			}
+		}
+		if err := t.queue.push(evnt); err != nil {
+			t.logger.Printf("[ERR]: Failed to buffer event: %v", err)
+			atomic.AddUint64(&l.dropped, 1)
+			return
+		}
+		t.startDelivery()
+
+	default:
+		select {
+		case t.EventCh <- evnt:
+		case <-l.closeCh:
+		}
+	}
+}
+
+func (t *Tracker) startDelivery() {
+	t.lifecycle.deliveryOnce.Do(func() {
+		t.spawn(t.deliver)
+	})
+}
+
+// deliver sends the buffered events on EventCh in order
+func (t *Tracker) deliver() {
+	for {
+		t.queue.lock.Lock()
+		evnt, err := t.queue.peek()
+		t.queue.lock.Unlock()
+
+		if err != nil {
+			t.logger.Printf("[ERR]: Failed to read buffered event: %v", err)
+			return
+		}
+		if evnt == nil {
+			select {
+			case <-t.queue.notifyCh:
+				continue
+			case <-t.lifecycle.closeCh:
+				return
+			}
+		}
+
+		if t.decoder != nil {
+			evnt.Decoded = t.decodeEvent(evnt)
+		}
+		select {
+		case t.EventCh <- evnt:
+		case <-t.lifecycle.closeCh:
+			return
+		}
+
+		t.queue.lock.Lock()
+		err = t.queue.pop()
+		t.queue.lock.Unlock()
+
+		if err != nil {
+			t.logger.Printf("[ERR]: Failed to remove buffered event: %v", err)
+			return
+		}
+	}
+}
+
+// eventQueue is a queue of events persisted in the store. The events are
+// stored with sequential keys between tail (the next event to deliver)
+// and head (the next event to push).
+type eventQueue struct {
+	lock     sync.Mutex
+	store    store.Store
+	prefix   string
+	head     uint64
+	tail     uint64
+	notifyCh chan struct{}
+}
+
+type queuedEvent struct {
+	Type    EventType
+	Added   []*ethgo.Log
+	Removed []*ethgo.Log
+}
+
+func newEventQueue(s store.Store, hash string) (*eventQueue, error) {
+	q := &eventQueue{
+		store:    s,
+		prefix:   dbQueue + "_" + hash + "_",
+		notifyCh: make(chan struct{}, 1),
+	}
+
+	var err error
+	if q.head, err = q.getIndex("head"); err != nil {
+		return nil, err
+	}
+	if q.tail, err = q.getIndex("tail"); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (q *eventQueue) getIndex(name string) (uint64, error) {
+	val, err := q.store.Get(q.prefix + name)
+	if err != nil {
+		return 0, err
+	}
+	if val == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(val, 10, 64)
+}
+
+func (q *eventQueue) setIndex(name string, indx uint64) error {
+	return q.store.Set(q.prefix+name, strconv.FormatUint(indx, 10))
+}
+
+func (q *eventQueue) key(indx uint64) string {
+	return q.prefix + strconv.FormatUint(indx, 10)
+}
+
+func (q *eventQueue) len() uint64 {
+	return q.head - q.tail
+}
+
+func (q *eventQueue) push(evnt *Event) error {
+	raw, err := json.Marshal(&queuedEvent{
+		Type:    evnt.Type,
+		Added:   evnt.Added,
+		Removed: evnt.Removed,
+	})
+	if err != nil {
+		return err
+	}
+	if err := q.store.Set(q.key(q.head), hex.EncodeToString(raw)); err != nil {
+		return err
+	}
+	if err := q.setIndex("head", q.head+1); err != nil {
+		return err
+	}
+	q.head++
+
+	select {
+	case q.notifyCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (q *eventQueue) peek() (*Event, error) {
+	if q.len() == 0 {
+		return nil, nil
+	}
+	val, err := q.store.Get(q.key(q.tail))
+	if err != nil {
+		return nil, err
+	}
+	raw, err := hex.DecodeString(val)
+	if err != nil {
+		return nil, err
+	}
+	var obj queuedEvent
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+	evnt := &Event{
+		Type:    obj.Type,
+		Added:   obj.Added,
+		Removed: obj.Removed,
+	}
+	return evnt, nil
+}
+
+func (q *eventQueue) pop() error {
+	if err := q.setIndex("tail", q.tail+1); err != nil {
+		return err
+	}
+	// the store does not delete keys, leave an empty value instead
+	if err := q.store.Set(q.key(q.tail), ""); err != nil {
+		return err
+	}
+	q.tail++
+	return nil
+}
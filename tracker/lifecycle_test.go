@@ -0,0 +1,195 @@
+package tracker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/testutil"
+	"github.com/git-yongge/ethgo/tracker/store"
+	"github.com/git-yongge/ethgo/tracker/store/inmem"
+	"github.com/stretchr/testify/assert"
+)
+
+func lifecycleClient(num int) (*testutil.MockClient, testutil.MockList) {
+	l := testutil.MockList{}
+	l.Create(0, num, func(b *testutil.MockBlock) {
+		b.Log("0x1")
+	})
+
+	m := &testutil.MockClient{}
+	m.AddScenario(l)
+	return m, l
+}
+
+// readEvents reads the logs of EventCh until it is closed
+func readEvents(tt *Tracker) chan []*ethgo.Log {
+	doneCh := make(chan []*ethgo.Log)
+	go func() {
+		logs := []*ethgo.Log{}
+		for evnt := range tt.EventCh {
+			logs = append(logs, evnt.Added...)
+		}
+		doneCh <- logs
+	}()
+	return doneCh
+}
+
+func TestTracker_StartStop(t *testing.T) {
+	m, l := lifecycleClient(50)
+
+	tt, err := NewTracker(m, testConfig())
+	assert.NoError(t, err)
+
+	logsCh := readEvents(tt)
+
+	assert.NoError(t, tt.Start(context.Background()))
+	assert.Error(t, tt.Start(context.Background()))
+
+	assert.NoError(t, tt.WaitDuration(2*time.Second))
+	assert.NoError(t, tt.Stop())
+
+	// all the channels are closed
+	logs := <-logsCh
+	assert.True(t, testutil.CompareLogs(l.GetLogs(), logs))
+
+	for range tt.SyncCh {
+	}
+	for range tt.BlockCh {
+	}
+
+	// stop is idempotent and the tracker cannot be started again
+	assert.NoError(t, tt.Stop())
+	assert.Error(t, tt.Start(context.Background()))
+}
+
+func TestTracker_StopBlockedConsumer(t *testing.T) {
+	m, _ := lifecycleClient(50)
+
+	s := inmem.NewInmemStore()
+	tt, err := NewTracker(m, testConfig(), WithStore(s))
+	assert.NoError(t, err)
+
+	// nobody reads the events and the sync blocks on the first batch
+	assert.NoError(t, tt.Start(context.Background()))
+	<-tt.ReadyCh
+
+	stopCh := make(chan error)
+	go func() {
+		stopCh <- tt.Stop()
+	}()
+	select {
+	case err := <-stopCh:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("stop is blocked")
+	}
+
+	assert.False(t, tt.IsSynced())
+	assert.EqualError(t, tt.WaitDuration(0), "tracker stopped")
+
+	// the logs of the batch in progress are committed with the last block
+	last, err := tt.GetLastBlock()
+	assert.NoError(t, err)
+	assert.NotNil(t, last)
+
+	logs := tt.Entry().(*inmem.Entry).Logs()
+	assert.NotEmpty(t, logs)
+	assert.Equal(t, last.Number, logs[len(logs)-1].BlockNumber)
+}
+
+func TestTracker_ConsumerDrop(t *testing.T) {
+	m, _ := lifecycleClient(50)
+
+	tt, err := NewTracker(m, testConfig(), WithConsumerPolicy(ConsumerDrop))
+	assert.NoError(t, err)
+
+	assert.NoError(t, tt.Start(context.Background()))
+	assert.NoError(t, tt.WaitDuration(2*time.Second))
+	assert.NoError(t, tt.Stop())
+
+	assert.NotZero(t, tt.Dropped())
+}
+
+func TestTracker_ConsumerBuffer(t *testing.T) {
+	m, l := lifecycleClient(50)
+	s := inmem.NewInmemStore()
+
+	newTracker := func() *Tracker {
+		tt, err := NewTracker(m, testConfig(), WithStore(s), WithConsumerPolicy(ConsumerBuffer))
+		assert.NoError(t, err)
+		return tt
+	}
+
+	// the events are buffered in the store while nobody reads them
+	tt0 := newTracker()
+	assert.NoError(t, tt0.Start(context.Background()))
+	assert.NoError(t, tt0.WaitDuration(2*time.Second))
+	assert.NoError(t, tt0.Stop())
+	assert.NotZero(t, tt0.queue.len())
+	assert.Zero(t, tt0.Dropped())
+
+	// the buffered events are delivered in order after the restart
+	tt1 := newTracker()
+	assert.NoError(t, tt1.Start(context.Background()))
+
+	logs := []*ethgo.Log{}
+	for len(logs) < len(l.GetLogs()) {
+		select {
+		case evnt := <-tt1.EventCh:
+			logs = append(logs, evnt.Added...)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout")
+		}
+	}
+	assert.NoError(t, tt1.Stop())
+
+	assert.True(t, testutil.CompareLogs(l.GetLogs(), logs))
+	assert.Zero(t, tt1.queue.len())
+}
+
+func TestTracker_DiscardUncommittedLogs(t *testing.T) {
+	m, l := lifecycleClient(50)
+	s := inmem.NewInmemStore()
+
+	newTracker := func() *Tracker {
+		tt, err := NewTracker(m, testConfig(), WithStore(s), WithFilter(&FilterConfig{Async: true}))
+		assert.NoError(t, err)
+		return tt
+	}
+
+	tt0 := newTracker()
+	assert.NoError(t, tt0.BatchSync(context.Background()))
+
+	// a log stored after the last block that was never committed
+	last, err := tt0.GetLastBlock()
+	assert.NoError(t, err)
+	assert.NoError(t, tt0.Entry().StoreLogs([]*ethgo.Log{{BlockNumber: last.Number + 1}}))
+
+	tt1 := newTracker()
+	assert.NoError(t, tt1.BatchSync(context.Background()))
+
+	assert.True(t, testutil.CompareLogs(l.GetLogs(), tt1.Entry().(*inmem.Entry).Logs()))
+}
+
+func TestTracker_Close(t *testing.T) {
+	m, _ := lifecycleClient(10)
+
+	s := &closeStore{Store: inmem.NewInmemStore()}
+	tt, err := NewTracker(m, testConfig(), WithStore(s))
+	assert.NoError(t, err)
+
+	assert.NoError(t, tt.Close())
+	assert.True(t, s.closed)
+}
+
+type closeStore struct {
+	store.Store
+	closed bool
+}
+
+func (c *closeStore) Close() error {
+	c.closed = true
+	return nil
+}
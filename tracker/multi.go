@@ -0,0 +1,636 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/blocktracker"
+	"github.com/git-yongge/ethgo/tracker/store"
+)
+
+// MultiTracker tracks the events of multiple filters. All the filters share
+// the same block tracker and each range of blocks is queried only once for
+// all the filters. The logs are routed to the store entry of each filter and
+// every filter keeps its own progress.
+type MultiTracker struct {
+	logger          *log.Logger
+	provider        Provider
+	config          *Config
+	store           store.Store
+	blockTracker    *blocktracker.BlockTracker
+	ownBlockTracker bool
+	preSyncOnce     sync.Once
+
+	lock    sync.Mutex
+	filters map[string]*Tracker
+	pending map[string]context.CancelFunc
+	ctx     context.Context
+	started bool
+	synced  int32
+
+	BlockCh chan *blocktracker.BlockEvent
+	ReadyCh chan struct{}
+	DoneCh  chan struct{}
+}
+
+// NewMultiTracker creates a new tracker for multiple filters. The filter of the config
+// (if any) is the first filter of the tracker, the others are included with AddFilter.
+func NewMultiTracker(provider Provider, opts ...ConfigOption) (*MultiTracker, error) {
+	config := DefaultConfig()
+	config.Filter = nil
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	m := &MultiTracker{
+		logger:       log.New(ioutil.Discard, "", log.LstdFlags),
+		provider:     provider,
+		config:       config,
+		store:        config.Store,
+		blockTracker: config.BlockTracker,
+		filters:      map[string]*Tracker{},
+		pending:      map[string]context.CancelFunc{},
+		BlockCh:      make(chan *blocktracker.BlockEvent, 1),
+		ReadyCh:      make(chan struct{}),
+		DoneCh:       make(chan struct{}, 1),
+	}
+	if m.blockTracker == nil {
+		blockTracker, err := blocktracker.NewBlockTracker(provider, blocktracker.WithBlockTag(config.BlockTag))
+		if err != nil {
+			return nil, err
+		}
+		m.blockTracker = blockTracker
+		m.ownBlockTracker = true
+	}
+	if err := checkBlockTag(m.blockTracker, config.BlockTag); err != nil {
+		return nil, err
+	}
+	if config.Filter != nil {
+		if _, err := m.AddFilter(config.Filter); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// AddFilter includes a new filter in the tracker. The filter is synced on its own
+// until it reaches the head of the chain and then it is tracked with the other filters.
+// The returned tracker holds the store entry and the channels of the filter, it is
+// driven by the multi tracker and its Sync methods must not be called.
+func (m *MultiTracker) AddFilter(filter *FilterConfig) (*Tracker, error) {
+	t, err := NewTracker(m.provider,
+		WithStore(m.store),
+		WithFilter(filter),
+		WithBlockTracker(m.blockTracker),
+		WithBatchSize(m.config.BatchSize),
+		WithEtherscan(m.config.EtherscanAPIKey),
+		WithConsumerPolicy(m.config.ConsumerPolicy),
+		WithBlockTag(m.config.BlockTag),
+	)
+	if err != nil {
+		return nil, err
+	}
+	hash := t.config.Filter.Hash
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, ok := m.filters[hash]; ok {
+		return nil, fmt.Errorf("filter %s already tracked", hash)
+	}
+	if _, ok := m.pending[hash]; ok {
+		return nil, fmt.Errorf("filter %s already tracked", hash)
+	}
+
+	if !m.started {
+		m.filters[hash] = t
+		return t, nil
+	}
+
+	ctx, cancelFn := context.WithCancel(m.ctx)
+	m.pending[hash] = cancelFn
+
+	go func() {
+		if err := m.catchUp(ctx, t); err != nil {
+			m.logger.Printf("[ERR]: Failed to sync filter %s: %v", hash, err)
+		}
+	}()
+	return t, nil
+}
+
+// RemoveFilter stops tracking the filter with the given hash. The logs and the
+// progress of the filter are kept in the store.
+func (m *MultiTracker) RemoveFilter(hash string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if cancelFn, ok := m.pending[hash]; ok {
+		cancelFn()
+		delete(m.pending, hash)
+		return nil
+	}
+	if _, ok := m.filters[hash]; !ok {
+		return fmt.Errorf("filter %s not found", hash)
+	}
+	delete(m.filters, hash)
+	return nil
+}
+
+// Filters returns the filters that are tracked
+func (m *MultiTracker) Filters() []*Tracker {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	res := make([]*Tracker, 0, len(m.filters))
+	for _, t := range m.filters {
+		res = append(res, t)
+	}
+	return res
+}
+
+func (m *MultiTracker) isTracked(t *Tracker) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	hash := t.config.Filter.Hash
+	if _, ok := m.pending[hash]; ok {
+		return true
+	}
+	return m.filters[hash] == t
+}
+
+// catchUp syncs a filter included while the tracker is running and
+// adds it to the tracked filters once it reaches the head
+func (m *MultiTracker) catchUp(ctx context.Context, t *Tracker) error {
+	if err := m.syncFilters(ctx, []*Tracker{t}); err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	// the filter might have been removed while syncing
+	if ctx.Err() != nil {
+		return nil
+	}
+	hash := t.config.Filter.Hash
+	delete(m.pending, hash)
+	m.filters[hash] = t
+
+	t.markSynced()
+	return nil
+}
+
+// IsSynced returns true if the filters are synced to head
+func (m *MultiTracker) IsSynced() bool {
+	return atomic.LoadInt32(&m.synced) != 0
+}
+
+// WaitDuration waits for the initial sync of the filters up to duration
+func (m *MultiTracker) WaitDuration(dur time.Duration) error {
+	if m.IsSynced() {
+		return nil
+	}
+	select {
+	case <-time.After(dur):
+		return fmt.Errorf("timeout")
+	case <-m.DoneCh:
+	}
+	return nil
+}
+
+// BatchSync syncs all the filters up to the head of the chain
+func (m *MultiTracker) BatchSync(ctx context.Context) error {
+	var err error
+	m.preSyncOnce.Do(func() {
+		err = checkGenesis(m.provider, m.store)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := m.blockTracker.Init(); err != nil {
+		return err
+	}
+	if m.ownBlockTracker {
+		go m.blockTracker.Start()
+		go func() {
+			// track our stop
+			<-ctx.Done()
+			m.blockTracker.Close()
+		}()
+	}
+
+	// from now on the new filters sync on their own
+	m.lock.Lock()
+	m.ctx = ctx
+	m.started = true
+	m.lock.Unlock()
+
+	close(m.ReadyCh)
+
+	filters := m.Filters()
+	if err := m.syncFilters(ctx, filters); err != nil {
+		return err
+	}
+	for _, t := range filters {
+		t.markSynced()
+	}
+
+	select {
+	case m.DoneCh <- struct{}{}:
+	default:
+	}
+
+	atomic.StoreInt32(&m.synced, 1)
+	return nil
+}
+
+// Sync syncs all the filters and keeps tracking them as new blocks arrive
+func (m *MultiTracker) Sync(ctx context.Context) error {
+	if err := m.BatchSync(ctx); err != nil {
+		return err
+	}
+
+	sub := m.blockTracker.Subscribe()
+	go func() {
+		for {
+			select {
+			case evnt := <-sub:
+				select {
+				case m.BlockCh <- evnt:
+				default:
+				}
+
+				if err := m.syncFilters(ctx, m.Filters()); err != nil {
+					m.logger.Printf("[ERR]: Failed to sync filters: %v", err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// filterState is the progress of a filter during a sync
+type filterState struct {
+	t *Tracker
+
+	// origin is the first block that the filter has not processed
+	origin uint64
+
+	// removed are the logs removed in a reorg
+	removed []*ethgo.Log
+}
+
+// syncFilters syncs the filters up to the last block of the block tracker. Each
+// filter starts from its own last block, after a reorg the logs past the common
+// ancestor are removed.
+func (m *MultiTracker) syncFilters(ctx context.Context, filters []*Tracker) error {
+	if len(filters) == 0 {
+		return nil
+	}
+
+	lock := m.blockTracker.AcquireLock()
+	defer func() {
+		if lock.Locked {
+			lock.Unlock()
+		}
+	}()
+
+	lock.Lock()
+	if m.blockTracker.Len() == 0 {
+		return nil
+	}
+	target := m.blockTracker.LastBlocked()
+
+	states := []*filterState{}
+	for _, t := range filters {
+		state, err := m.reconcile(t, target)
+		if err != nil {
+			return err
+		}
+		states = append(states, state)
+	}
+
+	// far from the target block, do bulk syncs with ranges of blocks
+	// without holding the lock on the block tracker
+	maxBacklog := m.blockTracker.MaxBlockBacklog()
+	for {
+		from := minOrigin(states)
+		if from > target.Number || target.Number-from+1 <= maxBacklog {
+			break
+		}
+
+		lock.Unlock()
+
+		if err := m.syncBatch(ctx, states, from, target.Number-maxBacklog); err != nil {
+			return err
+		}
+
+		lock.Lock()
+		target = m.blockTracker.LastBlocked()
+	}
+
+	// sync the blocks of the block tracker while holding the lock
+	// so that the target block does not change
+	added, err := m.syncBlocks(states, m.blockTracker.BlocksBlocked())
+	if err != nil {
+		return err
+	}
+	lock.Unlock()
+
+	for _, state := range states {
+		if len(state.removed) == 0 && len(added[state]) == 0 {
+			continue
+		}
+		if m.isTracked(state.t) {
+			state.t.emitEvent(&Event{
+				Removed: revertLogs(state.removed),
+				Added:   added[state],
+			})
+		}
+	}
+	return nil
+}
+
+// reconcile returns the first block to sync for the filter. If the last block
+// processed is not in the chain anymore, the logs after the common ancestor
+// are removed.
+func (m *MultiTracker) reconcile(t *Tracker, target *ethgo.Block) (*filterState, error) {
+	state := &filterState{t: t}
+
+	last, err := t.GetLastBlock()
+	if err != nil {
+		return nil, err
+	}
+	if last == nil {
+		// Try to fast track to the valid block (if possible)
+		last, err = t.fastTrack(t.config.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fasttrack: %v", err)
+		}
+		if last == nil {
+			return state, nil
+		}
+		if err := t.storeLastBlock(last); err != nil {
+			return nil, err
+		}
+	}
+
+	// discard the logs stored after the last block
+	if _, err := t.removeLogs(last.Number+1, nil); err != nil {
+		return nil, err
+	}
+	if last.Hash == target.Hash {
+		state.origin = target.Number + 1
+		return state, nil
+	}
+	if last.Number > target.Number {
+		return nil, fmt.Errorf("store is more advanced than the chain")
+	}
+
+	pivot := m.blockAt(last.Number)
+	if pivot == nil {
+		if pivot, err = m.provider.GetBlockByNumber(ethgo.BlockNumber(last.Number), false); err != nil {
+			return nil, err
+		}
+	}
+	if pivot.Hash == last.Hash {
+		state.origin = last.Number + 1
+		return state, nil
+	}
+
+	ancestor, err := t.findAncestor(last, pivot)
+	if err != nil {
+		return nil, err
+	}
+	if state.removed, err = t.removeLogs(ancestor+1, nil); err != nil {
+		return nil, err
+	}
+	state.origin = ancestor + 1
+	return state, nil
+}
+
+// blockAt returns the block of the block tracker with the given number.
+// The lock on the block tracker must be held.
+func (m *MultiTracker) blockAt(num uint64) *ethgo.Block {
+	blocks := m.blockTracker.BlocksBlocked()
+	if len(blocks) == 0 || num < blocks[0].Number {
+		return nil
+	}
+	indx := num - blocks[0].Number
+	if indx >= uint64(len(blocks)) {
+		return nil
+	}
+	return blocks[indx]
+}
+
+func minOrigin(states []*filterState) uint64 {
+	res := ^uint64(0)
+	for _, state := range states {
+		if state.origin < res {
+			res = state.origin
+		}
+	}
+	return res
+}
+
+// activeFilters returns the filters that have to process the block num
+func activeFilters(states []*filterState, num uint64) []*filterState {
+	res := []*filterState{}
+	for _, state := range states {
+		if state.origin <= num {
+			res = append(res, state)
+		}
+	}
+	return res
+}
+
+// syncBatch queries the logs of the filters between from and to in batches of blocks
+func (m *MultiTracker) syncBatch(ctx context.Context, states []*filterState, from, to uint64) error {
+	batchSize := m.config.BatchSize
+	additiveFactor := uint64(float64(batchSize) * 0.10)
+
+	for i := from; i <= to; {
+		dst := min(to, i+batchSize)
+
+		active := activeFilters(states, dst)
+
+		query := mergeFilters(active)
+		query.SetFromUint64(i)
+		query.SetToUint64(dst)
+
+		logs, err := m.provider.GetLogs(query)
+		if err != nil {
+			if tooMuchDataRequestedError(err) && batchSize > 1 {
+				// multiplicative decrease
+				batchSize = batchSize / 2
+				continue
+			}
+			return err
+		}
+
+		block, err := m.provider.GetBlockByNumber(ethgo.BlockNumber(dst), false)
+		if err != nil {
+			return err
+		}
+
+		for _, state := range active {
+			matched := routeLogs(state, logs)
+			if err := state.t.entry.StoreLogs(matched); err != nil {
+				return err
+			}
+			if err := state.t.storeLastBlock(block); err != nil {
+				return err
+			}
+			state.origin = dst + 1
+
+			state.t.notifySync(dst)
+			if len(matched) != 0 && m.isTracked(state.t) {
+				state.t.emitLogs(EventAdd, matched)
+			}
+		}
+
+		// check if the execution is over after each query batch
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		i = dst + 1
+
+		// update the batchSize with additive increase
+		if batchSize < m.config.BatchSize {
+			batchSize = min(m.config.BatchSize, batchSize+additiveFactor)
+		}
+	}
+	return nil
+}
+
+// syncBlocks queries the logs of the filters for each block of the block tracker
+// and returns the logs added to each filter
+func (m *MultiTracker) syncBlocks(states []*filterState, blocks []*ethgo.Block) (map[*filterState][]*ethgo.Log, error) {
+	added := map[*filterState][]*ethgo.Log{}
+
+	target := blocks[len(blocks)-1]
+	if from := minOrigin(states); from < blocks[0].Number {
+		return nil, fmt.Errorf("filter at block %d is behind the block tracker", from)
+	}
+
+	// query all the logs before storing them so that a failed
+	// query does not leave the filters half synced
+	for _, block := range blocks {
+		active := activeFilters(states, block.Number)
+		if len(active) == 0 {
+			continue
+		}
+
+		query := mergeFilters(active)
+		query.BlockHash = &block.Hash
+
+		// We check the hash, we need to do a retry to let unsynced nodes get the block
+		var logs []*ethgo.Log
+		var err error
+
+		for i := 0; i < 5; i++ {
+			logs, err = m.provider.GetLogs(query)
+			if err == nil {
+				break
+			}
+			time.Sleep(500 * time.Millisecond)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, state := range active {
+			added[state] = append(added[state], routeLogs(state, logs)...)
+		}
+	}
+
+	for _, state := range activeFilters(states, target.Number) {
+		if err := state.t.entry.StoreLogs(added[state]); err != nil {
+			return nil, err
+		}
+		if err := state.t.storeLastBlock(target); err != nil {
+			return nil, err
+		}
+		state.origin = target.Number + 1
+	}
+	return added, nil
+}
+
+// routeLogs returns the logs that belong to the filter
+func routeLogs(state *filterState, logs []*ethgo.Log) []*ethgo.Log {
+	res := []*ethgo.Log{}
+	for _, log := range logs {
+		if log.BlockNumber >= state.origin && state.t.config.Filter.match(log) {
+			res = append(res, log)
+		}
+	}
+	return res
+}
+
+// mergeFilters returns a query that includes the logs of all the filters. An address
+// or a topic position is only part of the query if all the filters restrict it.
+func mergeFilters(states []*filterState) *ethgo.LogFilter {
+	query := &ethgo.LogFilter{}
+
+	addresses := []ethgo.Address{}
+	seen := map[ethgo.Address]struct{}{}
+	for _, state := range states {
+		filter := state.t.config.Filter
+		if len(filter.Address) == 0 {
+			addresses = nil
+			break
+		}
+		for _, addr := range filter.Address {
+			if _, ok := seen[addr]; !ok {
+				seen[addr] = struct{}{}
+				addresses = append(addresses, addr)
+			}
+		}
+	}
+	if len(addresses) != 0 {
+		query.Address = addresses
+	}
+
+	numTopics := 0
+	for _, state := range states {
+		if num := len(state.t.config.Filter.Topics); num > numTopics {
+			numTopics = num
+		}
+	}
+	for indx := 0; indx < numTopics; indx++ {
+		topics := []*ethgo.Hash{}
+		seen := map[ethgo.Hash]struct{}{}
+		for _, state := range states {
+			filter := state.t.config.Filter
+			if indx >= len(filter.Topics) || isWildcard(filter.Topics[indx]) {
+				topics = nil
+				break
+			}
+			for _, topic := range filter.Topics[indx] {
+				if _, ok := seen[*topic]; !ok {
+					seen[*topic] = struct{}{}
+					topics = append(topics, topic)
+				}
+			}
+		}
+		query.Topics = append(query.Topics, topics)
+	}
+
+	// remove the trailing positions that match any topic
+	for len(query.Topics) != 0 && query.Topics[len(query.Topics)-1] == nil {
+		query.Topics = query.Topics[:len(query.Topics)-1]
+	}
+	return query
+}
@@ -0,0 +1,349 @@
+package tracker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/blocktracker"
+	"github.com/git-yongge/ethgo/testutil"
+	"github.com/git-yongge/ethgo/tracker/store/inmem"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	multiAddr0  = ethgo.Address{0x1}
+	multiAddr1  = ethgo.Address{0x2}
+	multiTopic0 = ethgo.Hash{0x1}
+	multiTopic1 = ethgo.Hash{0x2}
+)
+
+// mockMultiClient is a mock client that filters the logs by address
+// and topics and records the log queries
+type mockMultiClient struct {
+	testutil.MockClient
+
+	lock    sync.Mutex
+	queries []*ethgo.LogFilter
+}
+
+func (m *mockMultiClient) GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error) {
+	m.lock.Lock()
+	m.queries = append(m.queries, filter)
+	m.lock.Unlock()
+
+	logs, err := m.MockClient.GetLogs(filter)
+	if err != nil {
+		return nil, err
+	}
+	query := &FilterConfig{Address: filter.Address, Topics: filter.Topics}
+
+	res := []*ethgo.Log{}
+	for _, log := range logs {
+		if query.match(log) {
+			res = append(res, log)
+		}
+	}
+	return res, nil
+}
+
+// advance adds the blocks [from, to) to the chain. Each block has a log
+// from one of the two addresses and with one of the two topics.
+func (m *mockMultiClient) advance(from, to int, fork string) []*ethgo.Block {
+	l := testutil.MockList{}
+	l.Create(from, to, func(b *testutil.MockBlock) {
+		b.Extra(fork)
+	})
+	m.AddScenario(l)
+
+	for _, b := range l {
+		if b.GetNum() == 0 {
+			continue
+		}
+		log := &ethgo.Log{
+			Address:     multiAddr0,
+			Topics:      []ethgo.Hash{multiTopic0},
+			BlockNumber: uint64(b.GetNum()),
+			BlockHash:   b.Hash(),
+			Data:        []byte(fork),
+		}
+		if b.GetNum()%2 == 1 {
+			log.Address = multiAddr1
+		}
+		if b.GetNum()%3 == 0 {
+			log.Topics = []ethgo.Hash{multiTopic1}
+		}
+		m.AddLogs([]*ethgo.Log{log})
+	}
+
+	blocks := []*ethgo.Block{}
+	for _, b := range l {
+		block, _ := m.GetBlockByHash(b.Hash(), false)
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+func (m *mockMultiClient) expectedLogs(filter *FilterConfig) []*ethgo.Log {
+	res := []*ethgo.Log{}
+	for _, log := range m.GetAllLogs() {
+		if filter.match(log) {
+			res = append(res, log)
+		}
+	}
+	return res
+}
+
+func multiFilters() []*FilterConfig {
+	return []*FilterConfig{
+		{Address: []ethgo.Address{multiAddr0}},
+		{Address: []ethgo.Address{multiAddr1}},
+		{Topics: [][]*ethgo.Hash{{&multiTopic1}}},
+	}
+}
+
+// eventCollector reads the events of a filter
+type eventCollector struct {
+	lock    sync.Mutex
+	added   []*ethgo.Log
+	removed []*ethgo.Log
+}
+
+func collectEvents(ctx context.Context, tt *Tracker) *eventCollector {
+	c := &eventCollector{}
+	go func() {
+		for {
+			select {
+			case evnt, ok := <-tt.EventCh:
+				if !ok {
+					return
+				}
+				c.lock.Lock()
+				c.added = append(c.added, evnt.Added...)
+				c.removed = append(c.removed, evnt.Removed...)
+				c.lock.Unlock()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return c
+}
+
+// wait waits until the number of added and removed logs is collected and resets them
+func (c *eventCollector) wait(t *testing.T, added, removed int) ([]*ethgo.Log, []*ethgo.Log) {
+	t.Helper()
+
+	var resAdded, resRemoved []*ethgo.Log
+	assert.Eventually(t, func() bool {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if len(c.added) != added || len(c.removed) != removed {
+			return false
+		}
+		resAdded, resRemoved = c.added, c.removed
+		c.added, c.removed = nil, nil
+		return true
+	}, 2*time.Second, 10*time.Millisecond)
+	return resAdded, resRemoved
+}
+
+func TestMultiTracker_Sync(t *testing.T) {
+	m := &mockMultiClient{}
+	m.advance(0, 100, "")
+
+	store := inmem.NewInmemStore()
+
+	bt, err := blocktracker.NewBlockTracker(m)
+	assert.NoError(t, err)
+
+	mt, err := NewMultiTracker(m,
+		testConfig(),
+		WithStore(store),
+		WithBlockTracker(bt),
+	)
+	assert.NoError(t, err)
+
+	filters := multiFilters()
+	for _, filter := range filters {
+		filter.Async = true
+	}
+
+	tt0, err := mt.AddFilter(filters[0])
+	assert.NoError(t, err)
+
+	// the same filter cannot be tracked twice
+	_, err = mt.AddFilter(&FilterConfig{Address: []ethgo.Address{multiAddr0}})
+	assert.Error(t, err)
+
+	assert.NoError(t, mt.BatchSync(context.Background()))
+	assert.True(t, tt0.IsSynced())
+	assert.True(t, testutil.CompareLogs(m.expectedLogs(filters[0]), tt0.Entry().(*inmem.Entry).Logs()))
+
+	// the chain advances and a second tracker resumes the first filter
+	// and syncs the new filters from the start with the same queries
+	m.advance(100, 150, "")
+
+	bt, err = blocktracker.NewBlockTracker(m)
+	assert.NoError(t, err)
+
+	mt, err = NewMultiTracker(m,
+		testConfig(),
+		WithStore(store),
+		WithBlockTracker(bt),
+	)
+	assert.NoError(t, err)
+
+	trackers := []*Tracker{}
+	for _, filter := range filters {
+		tt, err := mt.AddFilter(filter)
+		assert.NoError(t, err)
+		trackers = append(trackers, tt)
+	}
+
+	m.queries = nil
+	assert.NoError(t, mt.BatchSync(context.Background()))
+
+	for indx, tt := range trackers {
+		assert.True(t, testutil.CompareLogs(m.expectedLogs(filters[indx]), tt.Entry().(*inmem.Entry).Logs()))
+
+		last, err := tt.GetLastBlock()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(149), last.Number)
+	}
+
+	// every range of blocks and every block is queried once for all the filters
+	ranges := map[uint64]struct{}{}
+	hashes := map[ethgo.Hash]struct{}{}
+	for _, query := range m.queries {
+		if query.BlockHash != nil {
+			assert.NotContains(t, hashes, *query.BlockHash)
+			hashes[*query.BlockHash] = struct{}{}
+		} else {
+			assert.NotContains(t, ranges, uint64(*query.From))
+			ranges[uint64(*query.From)] = struct{}{}
+		}
+		// the filters do not restrict all of them the address or the topics
+		assert.Empty(t, query.Address)
+	}
+}
+
+func TestMultiTracker_Filters(t *testing.T) {
+	m := &mockMultiClient{}
+	m.advance(0, 50, "")
+
+	bt, err := blocktracker.NewBlockTracker(m)
+	assert.NoError(t, err)
+
+	mt, err := NewMultiTracker(m,
+		testConfig(),
+		WithBlockTracker(bt),
+	)
+	assert.NoError(t, err)
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	filters := multiFilters()
+
+	tt0, err := mt.AddFilter(filters[0])
+	assert.NoError(t, err)
+	c0 := collectEvents(ctx, tt0)
+
+	assert.NoError(t, mt.Sync(ctx))
+	assert.NoError(t, mt.WaitDuration(time.Second))
+	c0.wait(t, len(m.expectedLogs(filters[0])), 0)
+
+	// add a filter at runtime
+	tt1, err := mt.AddFilter(filters[1])
+	assert.NoError(t, err)
+	c1 := collectEvents(ctx, tt1)
+
+	select {
+	case <-tt1.DoneCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout to sync the new filter")
+	}
+	assert.Len(t, mt.Filters(), 2)
+	c1.wait(t, len(m.expectedLogs(filters[1])), 0)
+
+	handleBlocks := func(blocks []*ethgo.Block) {
+		for _, block := range blocks {
+			assert.NoError(t, bt.HandleReconcile(block))
+		}
+	}
+
+	// new blocks are routed to both filters
+	handleBlocks(m.advance(50, 52, ""))
+
+	c0.wait(t, 1, 0)
+	c1.wait(t, 1, 0)
+
+	// reorg of the last block. The block 51 belongs to the
+	// second filter and the block 52 to the first one
+	handleBlocks(m.advance(51, 53, "f"))
+
+	c0.wait(t, 1, 0)
+	added, removed := c1.wait(t, 1, 1)
+	assert.Equal(t, uint64(51), removed[0].BlockNumber)
+	assert.Equal(t, []byte("f"), added[0].Data)
+
+	for indx, tt := range []*Tracker{tt0, tt1} {
+		assert.True(t, testutil.CompareLogs(m.expectedLogs(filters[indx]), tt.Entry().(*inmem.Entry).Logs()))
+	}
+
+	// a removed filter does not advance
+	assert.NoError(t, mt.RemoveFilter(tt1.config.Filter.Hash))
+	assert.Error(t, mt.RemoveFilter(tt1.config.Filter.Hash))
+
+	handleBlocks(m.advance(53, 55, "f"))
+
+	c0.wait(t, 1, 0)
+	c1.wait(t, 0, 0)
+
+	last, err := tt1.GetLastBlock()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(52), last.Number)
+
+	last, err = tt0.GetLastBlock()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(54), last.Number)
+}
+
+func TestMultiTracker_MergeFilters(t *testing.T) {
+	states := func(filters ...*FilterConfig) (res []*filterState) {
+		for _, filter := range filters {
+			res = append(res, &filterState{t: &Tracker{config: &Config{Filter: filter}}})
+		}
+		return
+	}
+
+	// the addresses are merged only if all the filters have addresses
+	query := mergeFilters(states(
+		&FilterConfig{Address: []ethgo.Address{multiAddr0}},
+		&FilterConfig{Address: []ethgo.Address{multiAddr1, multiAddr0}},
+	))
+	assert.Equal(t, []ethgo.Address{multiAddr0, multiAddr1}, query.Address)
+
+	query = mergeFilters(states(
+		&FilterConfig{Address: []ethgo.Address{multiAddr0}},
+		&FilterConfig{},
+	))
+	assert.Empty(t, query.Address)
+
+	// the topics are merged by position
+	query = mergeFilters(states(
+		&FilterConfig{Topics: [][]*ethgo.Hash{{&multiTopic0}, nil, {&multiTopic0}}},
+		&FilterConfig{Topics: [][]*ethgo.Hash{{&multiTopic1}, {&multiTopic1}}},
+	))
+	assert.Equal(t, [][]*ethgo.Hash{{&multiTopic0, &multiTopic1}}, query.Topics)
+
+	query = mergeFilters(states(
+		&FilterConfig{Topics: [][]*ethgo.Hash{nil, {&multiTopic0}}},
+		&FilterConfig{Topics: [][]*ethgo.Hash{{&multiTopic1}, {&multiTopic0, &multiTopic1}}},
+	))
+	assert.Equal(t, [][]*ethgo.Hash{nil, {&multiTopic0, &multiTopic1}}, query.Topics)
+}
@@ -89,6 +89,8 @@ func (e *Entry) LastIndex() (uint64, error) {
 
 // Logs returns the logs of the inmemory store
 func (e *Entry) Logs() []*ethgo.Log {
+	e.l.RLock()
+	defer e.l.RUnlock()
 	return e.logs
 }
 
@@ -112,6 +114,8 @@ func (e *Entry) RemoveLogs(indx uint64) error {
 
 // GetLog implements the store interface
 func (e *Entry) GetLog(indx uint64, log *ethgo.Log) error {
+	e.l.RLock()
+	defer e.l.RUnlock()
 	*log = *e.logs[indx]
 	return nil
 }
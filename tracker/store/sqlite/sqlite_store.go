@@ -0,0 +1,258 @@
+package trackersqlite
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"strings"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/tracker/store"
+	"github.com/jmoiron/sqlx"
+
+	// Enable sqlite for sqlx
+	_ "github.com/mattn/go-sqlite3"
+)
+
+var _ store.Store = (*SQLiteStore)(nil)
+
+// SQLiteStore is a tracker store implementation that uses SQLite as a backend.
+// The logs of all the entries are stored in the 'logs' table, see the schema
+// in logsSQLSchema. Addresses and hashes are stored as lowercase hex strings.
+type SQLiteStore struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteStore opens (or creates) the SQLite database at path. The database
+// uses the WAL journal mode so that the logs can be queried while the tracker
+// writes to it.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	dsn := "file:" + path + "?_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate"
+
+	db, err := sqlx.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(kvSQLSchema + logsSQLSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// DB returns the database connection to run queries on the logs
+func (s *SQLiteStore) DB() *sql.DB {
+	return s.db.DB
+}
+
+// Close implements the store interface
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// Get implements the store interface
+func (s *SQLiteStore) Get(k string) (string, error) {
+	var out string
+	if err := s.db.Get(&out, "SELECT val FROM kv WHERE key=?", k); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+	return out, nil
+}
+
+// ListPrefix implements the store interface
+func (s *SQLiteStore) ListPrefix(prefix string) ([]string, error) {
+	// LIKE is not used since it is case insensitive and '_' is a wildcard
+	out := []string{}
+	if err := s.db.Select(&out, "SELECT val FROM kv WHERE instr(key, ?) = 1 ORDER BY key", prefix); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Set implements the store interface
+func (s *SQLiteStore) Set(k, v string) error {
+	if _, err := s.db.Exec("INSERT INTO kv (key, val) VALUES (?, ?) ON CONFLICT (key) DO UPDATE SET val = excluded.val", k, v); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetEntry implements the store interface
+func (s *SQLiteStore) GetEntry(hash string) (store.Entry, error) {
+	e := &Entry{
+		hash: hash,
+		db:   s.db,
+	}
+	return e, nil
+}
+
+// Entry is an store.Entry implementation
+type Entry struct {
+	hash string
+	db   *sqlx.DB
+}
+
+// LastIndex implements the store interface
+func (e *Entry) LastIndex() (uint64, error) {
+	return lastIndex(e.db, e.hash)
+}
+
+func lastIndex(q sqlx.Queryer, hash string) (uint64, error) {
+	var index uint64
+	if err := sqlx.Get(q, &index, "SELECT COALESCE(MAX(indx) + 1, 0) FROM logs WHERE entry=?", hash); err != nil {
+		return 0, err
+	}
+	return index, nil
+}
+
+// StoreLogs implements the store interface. The logs are inserted in a single transaction.
+func (e *Entry) StoreLogs(logs []*ethgo.Log) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	tx, err := e.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	index, err := lastIndex(tx, e.hash)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareNamed("INSERT INTO logs (entry, indx, removed, log_index, tx_index, tx_hash, block_num, block_hash, address, topic0, topic1, topic2, topic3, data) VALUES (:entry, :indx, :removed, :log_index, :tx_index, :tx_hash, :block_num, :block_hash, :address, :topic0, :topic1, :topic2, :topic3, :data)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for indx, log := range logs {
+		obj := &logObj{
+			Entry:     e.hash,
+			Index:     index + uint64(indx),
+			Removed:   log.Removed,
+			LogIndex:  log.LogIndex,
+			TxIndex:   log.TransactionIndex,
+			TxHash:    encodeHex(log.TransactionHash[:]),
+			BlockNum:  log.BlockNumber,
+			BlockHash: encodeHex(log.BlockHash[:]),
+			Address:   encodeHex(log.Address[:]),
+			Data:      log.Data,
+		}
+		topics := []*sql.NullString{&obj.Topic0, &obj.Topic1, &obj.Topic2, &obj.Topic3}
+		for i, topic := range log.Topics {
+			if i == len(topics) {
+				break
+			}
+			*topics[i] = sql.NullString{String: encodeHex(topic[:]), Valid: true}
+		}
+
+		if _, err := stmt.Exec(obj); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// RemoveLogs implements the store interface
+func (e *Entry) RemoveLogs(indx uint64) error {
+	if _, err := e.db.Exec("DELETE FROM logs WHERE entry=? AND indx >= ?", e.hash, indx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetLog implements the store interface
+func (e *Entry) GetLog(indx uint64, log *ethgo.Log) error {
+	obj := logObj{}
+	if err := e.db.Get(&obj, "SELECT * FROM logs WHERE entry=? AND indx=?", e.hash, indx); err != nil {
+		return err
+	}
+
+	log.Removed = obj.Removed
+	log.LogIndex = obj.LogIndex
+	log.TransactionIndex = obj.TxIndex
+	if err := log.TransactionHash.UnmarshalText([]byte(obj.TxHash)); err != nil {
+		return err
+	}
+	log.BlockNumber = obj.BlockNum
+	if err := log.BlockHash.UnmarshalText([]byte(obj.BlockHash)); err != nil {
+		return err
+	}
+	if err := log.Address.UnmarshalText([]byte(obj.Address)); err != nil {
+		return err
+	}
+
+	log.Topics = nil
+	for _, item := range []sql.NullString{obj.Topic0, obj.Topic1, obj.Topic2, obj.Topic3} {
+		if !item.Valid {
+			break
+		}
+		var topic ethgo.Hash
+		if err := topic.UnmarshalText([]byte(item.String)); err != nil {
+			return err
+		}
+		log.Topics = append(log.Topics, topic)
+	}
+
+	log.Data = obj.Data
+	return nil
+}
+
+func encodeHex(b []byte) string {
+	return "0x" + strings.ToLower(hex.EncodeToString(b))
+}
+
+type logObj struct {
+	Entry     string         `db:"entry"`
+	Index     uint64         `db:"indx"`
+	Removed   bool           `db:"removed"`
+	LogIndex  uint64         `db:"log_index"`
+	TxIndex   uint64         `db:"tx_index"`
+	TxHash    string         `db:"tx_hash"`
+	BlockNum  uint64         `db:"block_num"`
+	BlockHash string         `db:"block_hash"`
+	Address   string         `db:"address"`
+	Topic0    sql.NullString `db:"topic0"`
+	Topic1    sql.NullString `db:"topic1"`
+	Topic2    sql.NullString `db:"topic2"`
+	Topic3    sql.NullString `db:"topic3"`
+	Data      []byte         `db:"data"`
+}
+
+var kvSQLSchema = `
+CREATE TABLE IF NOT EXISTS kv (
+	key text PRIMARY KEY,
+	val text
+);
+`
+
+// logsSQLSchema is the table with the logs of all the entries. The entry
+// column is the hash of the filter and indx the position of the log in it.
+var logsSQLSchema = `
+CREATE TABLE IF NOT EXISTS logs (
+	entry 		text NOT NULL,
+	indx 		integer NOT NULL,
+	removed 	boolean NOT NULL DEFAULT false,
+	log_index 	integer,
+	tx_index 	integer,
+	tx_hash 	text,
+	block_num 	integer,
+	block_hash 	text,
+	address 	text,
+	topic0 		text,
+	topic1 		text,
+	topic2 		text,
+	topic3 		text,
+	data 		blob,
+	PRIMARY KEY (entry, indx)
+);
+
+CREATE INDEX IF NOT EXISTS logs_block_num ON logs (block_num);
+CREATE INDEX IF NOT EXISTS logs_address ON logs (address);
+CREATE INDEX IF NOT EXISTS logs_topic0 ON logs (topic0);
+`
@@ -0,0 +1,79 @@
+package trackersqlite
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/tracker/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupDB(t *testing.T) (store.Store, func()) {
+	dir, err := ioutil.TempDir("/tmp", "sqlite-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "test.db")
+	store, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	close := func() {
+		if err := store.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store, close
+}
+
+func TestSQLiteStore(t *testing.T) {
+	store.TestStore(t, setupDB)
+}
+
+func TestSQLiteStore_Logs(t *testing.T) {
+	s, close := setupDB(t)
+	defer close()
+
+	entry, err := s.GetEntry("1")
+	assert.NoError(t, err)
+
+	log := &ethgo.Log{
+		LogIndex:         1,
+		TransactionIndex: 2,
+		TransactionHash:  ethgo.Hash{0x1},
+		BlockNumber:      10,
+		BlockHash:        ethgo.Hash{0x2},
+		Address:          ethgo.HexToAddress("0xde0B295669a9FD93d5F28D9Ec85E40f4cb697BAe"),
+		Topics:           []ethgo.Hash{{0x3}, {0x4}},
+		Data:             []byte{0x1, 0x2},
+	}
+	assert.NoError(t, entry.StoreLogs([]*ethgo.Log{log, {BlockNumber: 11}}))
+
+	var log2 ethgo.Log
+	assert.NoError(t, entry.GetLog(0, &log2))
+	assert.Equal(t, *log, log2)
+
+	// the logs table can be queried by address and topic
+	db := s.(*SQLiteStore).DB()
+
+	var num uint64
+	err = db.QueryRow("SELECT block_num FROM logs WHERE address=? AND topic0=?",
+		"0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae",
+		ethgo.Hash{0x3}.String(),
+	).Scan(&num)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), num)
+
+	// the journal is in WAL mode
+	var mode string
+	assert.NoError(t, db.QueryRow("PRAGMA journal_mode").Scan(&mode))
+	assert.Equal(t, "wal", mode)
+}
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/git-yongge/ethgo"
+	"github.com/git-yongge/ethgo/abi"
 	"github.com/git-yongge/ethgo/blocktracker"
 	"github.com/git-yongge/ethgo/etherscan"
 	"github.com/git-yongge/ethgo/jsonrpc/codec"
@@ -42,6 +43,9 @@ type FilterConfig struct {
 	Start   uint64
 	Hash    string
 	Async   bool
+
+	// ABIs decode the logs of the filter into the Decoded events
+	ABIs []*abi.ABI `json:"-"`
 }
 
 func (f *FilterConfig) buildHash() {
@@ -69,6 +73,54 @@ func (f *FilterConfig) buildHash() {
 	f.Hash = hex.EncodeToString(h.Sum(nil))
 }
 
+// match returns true if the log matches the addresses and the topics of the filter
+func (f *FilterConfig) match(log *ethgo.Log) bool {
+	if len(f.Address) != 0 {
+		found := false
+		for _, addr := range f.Address {
+			if addr == log.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for indx, topics := range f.Topics {
+		if isWildcard(topics) {
+			continue
+		}
+		if indx >= len(log.Topics) {
+			return false
+		}
+		found := false
+		for _, topic := range topics {
+			if *topic == log.Topics[indx] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// isWildcard returns true if any topic matches the position
+func isWildcard(topics []*ethgo.Hash) bool {
+	if len(topics) == 0 {
+		return true
+	}
+	for _, topic := range topics {
+		if topic == nil {
+			return true
+		}
+	}
+	return false
+}
+
 func (f *FilterConfig) getFilterSearch() *ethgo.LogFilter {
 	filter := &ethgo.LogFilter{}
 	if len(f.Address) != 0 {
@@ -87,6 +139,8 @@ type Config struct {
 	EtherscanAPIKey string
 	Filter          *FilterConfig
 	Store           store.Store
+	ConsumerPolicy  ConsumerPolicy
+	BlockTag        ethgo.BlockNumber
 }
 
 type ConfigOption func(*Config)
@@ -121,6 +175,22 @@ func WithEtherscan(k string) ConfigOption {
 	}
 }
 
+// WithBlockTag sets the block followed as the head of the chain. With ethgo.Finalized
+// only the logs of finalized blocks are emitted and there are no removed logs.
+func WithBlockTag(tag ethgo.BlockNumber) ConfigOption {
+	return func(c *Config) {
+		c.BlockTag = tag
+	}
+}
+
+// WithConsumerPolicy sets the policy for the events not read from EventCh.
+// The Async filters always use ConsumerDrop.
+func WithConsumerPolicy(p ConsumerPolicy) ConfigOption {
+	return func(c *Config) {
+		c.ConsumerPolicy = p
+	}
+}
+
 // DefaultConfig returns the default tracker config
 func DefaultConfig() *Config {
 	return &Config{
@@ -128,6 +198,8 @@ func DefaultConfig() *Config {
 		Store:           inmem.NewInmemStore(),
 		Filter:          &FilterConfig{},
 		EtherscanAPIKey: "",
+		ConsumerPolicy:  ConsumerBlock,
+		BlockTag:        ethgo.Latest,
 	}
 }
 
@@ -150,6 +222,11 @@ type Tracker struct {
 	preSyncOnce  sync.Once
 	blockTracker *blocktracker.BlockTracker
 	synced       int32
+	decoder      *eventDecoder
+	handlers     map[string][]EventHandler
+	handlersLock sync.Mutex
+	lifecycle    *lifecycle
+	queue        *eventQueue
 	BlockCh      chan *blocktracker.BlockEvent
 	ReadyCh      chan struct{}
 	SyncCh       chan uint64
@@ -176,6 +253,11 @@ func NewTracker(provider Provider, opts ...ConfigOption) (*Tracker, error) {
 		EventCh:      make(chan *Event),
 		SyncCh:       make(chan uint64, 1),
 		synced:       0,
+		handlers:     map[string][]EventHandler{},
+		lifecycle:    newLifecycle(),
+	}
+	if err := checkBlockTag(t.blockTracker, config.BlockTag); err != nil {
+		return nil, err
 	}
 	if err := t.setupFilter(); err != nil {
 		return nil, err
@@ -195,6 +277,10 @@ func (t *Tracker) setupFilter() error {
 		t.config.Filter.buildHash()
 	}
 
+	if len(t.config.Filter.ABIs) != 0 {
+		t.decoder = newEventDecoder(t.config.Filter.ABIs)
+	}
+
 	entry, err := t.store.GetEntry(t.config.Filter.Hash)
 	if err != nil {
 		return err
@@ -217,6 +303,20 @@ func (t *Tracker) setupFilter() error {
 			return err
 		}
 	}
+
+	if t.policy() == ConsumerBuffer {
+		if t.queue, err = newEventQueue(t.store, t.config.Filter.Hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkBlockTag checks that the block tracker (if any) follows the block of the config
+func checkBlockTag(b *blocktracker.BlockTracker, tag ethgo.BlockNumber) error {
+	if b != nil && b.Tag() != tag {
+		return fmt.Errorf("block tracker follows the %s block but the tracker expects %s", b.Tag(), tag)
+	}
 	return nil
 }
 
@@ -260,14 +360,11 @@ func (t *Tracker) emitEvent(evnt *Event) {
 	if evnt == nil {
 		return
 	}
-	if t.config.Filter.Async {
-		select {
-		case t.EventCh <- evnt:
-		default:
-		}
-	} else {
-		t.EventCh <- evnt
+	if t.decoder != nil {
+		evnt.Decoded = t.decodeEvent(evnt)
+		t.handleDecoded(evnt.Decoded)
 	}
+	t.send(evnt)
 }
 
 // IsSynced returns true if the filter is synced to head
@@ -275,19 +372,25 @@ func (t *Tracker) IsSynced() bool {
 	return atomic.LoadInt32(&t.synced) != 0
 }
 
+func (t *Tracker) markSynced() {
+	atomic.StoreInt32(&t.synced, 1)
+	t.markDone()
+}
+
 // Wait waits the filter to finish
 func (t *Tracker) Wait() {
 	t.WaitDuration(0)
 }
 
-// WaitDuration waits for the filter to finish up to duration
+// WaitDuration waits for the filter to finish up to duration. A zero
+// duration waits until the filter is synced or the tracker is stopped.
 func (t *Tracker) WaitDuration(dur time.Duration) error {
 	if t.IsSynced() {
 		return nil
 	}
 
 	var waitCh <-chan time.Time
-	if dur == 0 {
+	if dur != 0 {
 		waitCh = time.After(dur)
 	}
 	select {
@@ -295,6 +398,9 @@ func (t *Tracker) WaitDuration(dur time.Duration) error {
 		return fmt.Errorf("timeout")
 	case <-t.DoneCh:
 	}
+	if !t.IsSynced() {
+		return fmt.Errorf("tracker stopped")
+	}
 	return nil
 }
 
@@ -368,29 +474,24 @@ START:
 		}
 		return err
 	}
-
-	if t.SyncCh != nil {
-		select {
-		case t.SyncCh <- dst:
-		default:
-		}
-	}
-
-	// add logs to the store
-	if err := t.entry.StoreLogs(logs); err != nil {
+	block, err := t.provider.GetBlockByNumber(ethgo.BlockNumber(dst), false)
+	if err != nil {
 		return err
 	}
-	t.emitLogs(EventAdd, logs)
 
-	// update the last block entry
-	block, err := t.provider.GetBlockByNumber(ethgo.BlockNumber(dst), false)
-	if err != nil {
+	// add logs to the store and then update the last block entry. The last
+	// block is the commit point of the batch, any log stored after it is
+	// discarded when the sync resumes.
+	if err := t.entry.StoreLogs(logs); err != nil {
 		return err
 	}
 	if err := t.storeLastBlock(block); err != nil {
 		return err
 	}
 
+	t.notifySync(dst)
+	t.emitLogs(EventAdd, logs)
+
 	// check if the execution is over after each query batch
 	if err := ctx.Err(); err != nil {
 		return err
@@ -418,20 +519,26 @@ func (t *Tracker) preSyncCheck() error {
 }
 
 func (t *Tracker) preSyncCheckImpl() error {
-	rGenesis, err := t.provider.GetBlockByNumber(0, false)
+	return checkGenesis(t.provider, t.store)
+}
+
+// checkGenesis checks that the store was created for the chain of the provider.
+// The genesis hash and the chain id are stored the first time.
+func checkGenesis(provider Provider, s store.Store) error {
+	rGenesis, err := provider.GetBlockByNumber(0, false)
 	if err != nil {
 		return err
 	}
-	rChainID, err := t.provider.ChainID()
+	rChainID, err := provider.ChainID()
 	if err != nil {
 		return err
 	}
 
-	genesis, err := t.store.Get(dbGenesis)
+	genesis, err := s.Get(dbGenesis)
 	if err != nil {
 		return err
 	}
-	chainID, err := t.store.Get(dbChainID)
+	chainID, err := s.Get(dbChainID)
 	if err != nil {
 		return err
 	}
@@ -443,10 +550,10 @@ func (t *Tracker) preSyncCheckImpl() error {
 			return fmt.Errorf("bad genesis")
 		}
 	} else {
-		if err := t.store.Set(dbGenesis, rGenesis.Hash.String()); err != nil {
+		if err := s.Set(dbGenesis, rGenesis.Hash.String()); err != nil {
 			return err
 		}
-		if err := t.store.Set(dbChainID, rChainID.String()); err != nil {
+		if err := s.Set(dbChainID, rChainID.String()); err != nil {
 			return err
 		}
 	}
@@ -535,14 +642,21 @@ func (t *Tracker) BatchSync(ctx context.Context) error {
 
 	if t.blockTracker == nil {
 		// run a specfic block tracker
-		t.blockTracker = blocktracker.NewBlockTracker(t.provider)
+		blockTracker, err := blocktracker.NewBlockTracker(t.provider, blocktracker.WithBlockTag(t.config.BlockTag))
+		if err != nil {
+			return err
+		}
+		t.blockTracker = blockTracker
 		if err := t.blockTracker.Init(); err != nil {
 			return err
 		}
 		go t.blockTracker.Start()
 		go func() {
 			// track our stop
-			<-ctx.Done()
+			select {
+			case <-ctx.Done():
+			case <-t.lifecycle.closeCh:
+			}
 			t.blockTracker.Close()
 		}()
 	} else {
@@ -552,18 +666,13 @@ func (t *Tracker) BatchSync(ctx context.Context) error {
 		}
 	}
 
-	close(t.ReadyCh)
+	t.markReady()
 
 	if err := t.syncImpl(ctx); err != nil {
 		return err
 	}
 
-	select {
-	case t.DoneCh <- struct{}{}:
-	default:
-	}
-
-	atomic.StoreInt32(&t.synced, 1)
+	t.markSynced()
 	return nil
 }
 
@@ -575,17 +684,21 @@ func (t *Tracker) Sync(ctx context.Context) error {
 
 	// subscribe and sync
 	sub := t.blockTracker.Subscribe()
-	go func() {
+	ok := t.spawn(func() {
 		for {
 			select {
 			case evnt := <-sub:
 				t.handleBlockEvnt(evnt)
 			case <-ctx.Done():
 				return
+			case <-t.lifecycle.closeCh:
+				return
 			}
 		}
-	}()
-
+	})
+	if !ok {
+		return fmt.Errorf("tracker is stopped")
+	}
 	return nil
 }
 
@@ -633,7 +746,14 @@ func (t *Tracker) syncImpl(ctx context.Context) error {
 				return err
 			}
 		}
-	} else {
+	}
+
+	if last != nil {
+		// discard the logs stored after the last block, the sync stopped
+		// before they were committed and they are queried again
+		if _, err := t.removeLogs(last.Number+1, nil); err != nil {
+			return err
+		}
 		if last.Hash == target.Hash {
 			return nil
 		}
@@ -781,10 +901,7 @@ func (t *Tracker) handleBlockEvnt(blockEvnt *blocktracker.BlockEvent) error {
 	}
 
 	// emit the block event
-	select {
-	case t.BlockCh <- blockEvnt:
-	default:
-	}
+	t.notifyBlock(blockEvnt)
 
 	if t.IsSynced() {
 		evnt, err := t.doFilter(blockEvnt.Added, blockEvnt.Removed)
@@ -858,6 +975,10 @@ type Event struct {
 	Type    EventType
 	Added   []*ethgo.Log
 	Removed []*ethgo.Log
+
+	// Decoded are the removed and the added logs decoded with
+	// the ABIs of the filter (if any)
+	Decoded []*DecodedEvent
 }
 
 // BlockEvent is an event emitted when a new block is included
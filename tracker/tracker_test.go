@@ -417,7 +417,8 @@ func testTrackerSyncerRandom(t *testing.T, n int, backlog uint64) {
 		m.AddScenario(l)
 
 		// use a custom block tracker to add specific backlog
-		tracker := blocktracker.NewBlockTracker(m, blocktracker.WithBlockMaxBacklog(backlog))
+		tracker, err := blocktracker.NewBlockTracker(m, blocktracker.WithBlockMaxBacklog(backlog))
+		assert.NoError(t, err)
 
 		tt, _ := NewTracker(m,
 			testConfig(),
@@ -680,7 +681,10 @@ func TestTrackerReconcile(t *testing.T) {
 
 			store := inmem.NewInmemStore()
 
-			btracker := blocktracker.NewBlockTracker(m)
+			btracker, err := blocktracker.NewBlockTracker(m)
+			if err != nil {
+				t.Fatal(err)
+			}
 
 			tt, err := NewTracker(m, WithStore(store), WithBlockTracker(btracker))
 			if err != nil {
@@ -816,3 +820,50 @@ func TestTooMuchDataRequested(t *testing.T) {
 		t.Fatal("not the same count")
 	}
 }
+
+func TestTracker_Finalized(t *testing.T) {
+	l := testutil.MockList{}
+	l.Create(0, 60, func(b *testutil.MockBlock) {
+		b.Log("0x1")
+	})
+
+	m := &testutil.MockClient{}
+	m.AddScenario(l)
+	m.SetFinalized(40)
+
+	bt, err := blocktracker.NewBlockTracker(m, blocktracker.WithBlockTag(ethgo.Finalized))
+	assert.NoError(t, err)
+
+	// the block tracker must follow the same block
+	_, err = NewTracker(m, WithBlockTracker(bt))
+	assert.Error(t, err)
+
+	tt, err := NewTracker(m,
+		testConfig(),
+		WithBlockTracker(bt),
+		WithBlockTag(ethgo.Finalized),
+	)
+	assert.NoError(t, err)
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	c := collectEvents(ctx, tt)
+
+	assert.NoError(t, tt.Start(ctx))
+	defer tt.Stop()
+
+	// only the logs up to the finalized block are synced
+	c.wait(t, 41, 0)
+
+	// the finalized block advances one epoch
+	m.SetFinalized(55)
+	block, err := m.GetBlockByNumber(ethgo.Finalized, false)
+	assert.NoError(t, err)
+	assert.NoError(t, bt.HandleReconcile(block))
+
+	c.wait(t, 15, 0)
+
+	finalized := l[:56]
+	assert.True(t, testutil.CompareLogs(finalized.GetLogs(), tt.Entry().(*inmem.Entry).Logs()))
+}
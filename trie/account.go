@@ -0,0 +1,120 @@
+package trie
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/umbracle/fastrlp"
+)
+
+var emptyCodeHash = ethgo.BytesToHash(ethgo.Keccak256(nil))
+
+// VerifyAccountProof checks the account proof and its storage proofs (as returned
+// by eth_getProof) against the state root of a block. It returns an error if any
+// of the values in the proof is not the one committed in the state.
+func VerifyAccountProof(stateRoot ethgo.Hash, proof *ethgo.AccountProof) error {
+	val, err := VerifyProof(stateRoot, ethgo.Keccak256(proof.Address[:]), proof.AccountProof)
+	if err != nil {
+		return fmt.Errorf("invalid account proof: %v", err)
+	}
+
+	if val == nil {
+		// the account does not exist, clients return either
+		// zero values or the values of an empty account
+		if proof.Nonce != 0 || (proof.Balance != nil && proof.Balance.Sign() != 0) {
+			return fmt.Errorf("account %s does not exist but has nonce or balance", proof.Address)
+		}
+		if proof.CodeHash != (ethgo.Hash{}) && proof.CodeHash != emptyCodeHash {
+			return fmt.Errorf("account %s does not exist but has code hash %s", proof.Address, proof.CodeHash)
+		}
+		if proof.StorageHash != (ethgo.Hash{}) && proof.StorageHash != EmptyRoot {
+			return fmt.Errorf("account %s does not exist but has storage hash %s", proof.Address, proof.StorageHash)
+		}
+	} else if err := verifyAccount(val, proof); err != nil {
+		return err
+	}
+
+	storageRoot := proof.StorageHash
+	if val == nil {
+		storageRoot = EmptyRoot
+	}
+	for _, storage := range proof.StorageProof {
+		if err := VerifyStorageProof(storageRoot, storage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func verifyAccount(raw []byte, proof *ethgo.AccountProof) error {
+	var p fastrlp.Parser
+	v, err := p.Parse(raw)
+	if err != nil {
+		return err
+	}
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+	if len(elems) != 4 {
+		return fmt.Errorf("incorrect number of elements to decode account, expected 4 but found %d", len(elems))
+	}
+
+	nonce, err := elems[0].GetUint64()
+	if err != nil {
+		return err
+	}
+	balance := new(big.Int)
+	if err := elems[1].GetBigInt(balance); err != nil {
+		return err
+	}
+	storageRoot, err := elems[2].GetBytes(nil, 32)
+	if err != nil {
+		return err
+	}
+	codeHash, err := elems[3].GetBytes(nil, 32)
+	if err != nil {
+		return err
+	}
+
+	if nonce != proof.Nonce {
+		return fmt.Errorf("account nonce %d does not match the proof %d", proof.Nonce, nonce)
+	}
+	if proof.Balance == nil || balance.Cmp(proof.Balance) != 0 {
+		return fmt.Errorf("account balance %s does not match the proof %s", proof.Balance, balance)
+	}
+	if !bytes.Equal(storageRoot, proof.StorageHash[:]) {
+		return fmt.Errorf("account storage hash %s does not match the proof 0x%x", proof.StorageHash, storageRoot)
+	}
+	if !bytes.Equal(codeHash, proof.CodeHash[:]) {
+		return fmt.Errorf("account code hash %s does not match the proof 0x%x", proof.CodeHash, codeHash)
+	}
+	return nil
+}
+
+// VerifyStorageProof checks a storage proof against the storage root of the account
+func VerifyStorageProof(storageRoot ethgo.Hash, proof *ethgo.StorageProof) error {
+	val, err := VerifyProof(storageRoot, ethgo.Keccak256(proof.Key[:]), proof.Proof)
+	if err != nil {
+		return fmt.Errorf("invalid storage proof for slot %s: %v", proof.Key, err)
+	}
+
+	value := new(big.Int)
+	if val != nil {
+		// the values are stored rlp encoded
+		var p fastrlp.Parser
+		v, err := p.Parse(val)
+		if err != nil {
+			return err
+		}
+		if err := v.GetBigInt(value); err != nil {
+			return err
+		}
+	}
+	if proof.Value == nil || value.Cmp(proof.Value) != 0 {
+		return fmt.Errorf("storage value %s of slot %s does not match the proof %s", proof.Value, proof.Key, value)
+	}
+	return nil
+}
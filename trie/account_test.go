@@ -0,0 +1,66 @@
+package trie
+
+import (
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/stretchr/testify/assert"
+)
+
+// state root of the proofs generated with go-ethereum
+var proofStateRoot = ethgo.HexToHash("0x5734f00814472f891cd4cc7d869a814be1c010a5e4a1bf0612e8b331bff40780")
+
+func readAccountProof(t *testing.T, name string) *ethgo.AccountProof {
+	data, err := ioutil.ReadFile("../testsuite/" + name)
+	assert.NoError(t, err)
+
+	proof := new(ethgo.AccountProof)
+	assert.NoError(t, proof.UnmarshalJSON(data))
+	return proof
+}
+
+func TestVerifyAccountProof(t *testing.T) {
+	proof := readAccountProof(t, "account-proof.json")
+	assert.Equal(t, uint64(3), proof.Nonce)
+	assert.Len(t, proof.StorageProof, 3)
+	assert.Equal(t, ethgo.Hash{31: 0x1}, proof.StorageProof[1].Key)
+
+	assert.NoError(t, VerifyAccountProof(proofStateRoot, proof))
+
+	// another state root
+	assert.Error(t, VerifyAccountProof(ethgo.Hash{0x1}, proof))
+
+	// the values do not match the proof
+	proof = readAccountProof(t, "account-proof.json")
+	proof.Balance = big.NewInt(1)
+	assert.Error(t, VerifyAccountProof(proofStateRoot, proof))
+
+	proof = readAccountProof(t, "account-proof.json")
+	proof.Nonce = 4
+	assert.Error(t, VerifyAccountProof(proofStateRoot, proof))
+
+	proof = readAccountProof(t, "account-proof.json")
+	proof.StorageProof[0].Value = big.NewInt(43)
+	assert.Error(t, VerifyAccountProof(proofStateRoot, proof))
+
+	// the slot 5 is empty
+	proof = readAccountProof(t, "account-proof.json")
+	proof.StorageProof[2].Value = big.NewInt(1)
+	assert.Error(t, VerifyAccountProof(proofStateRoot, proof))
+}
+
+func TestVerifyAccountProof_Missing(t *testing.T) {
+	proof := readAccountProof(t, "account-proof-missing.json")
+	assert.NoError(t, VerifyAccountProof(proofStateRoot, proof))
+
+	// an account that does not exist cannot have balance
+	proof.Balance = big.NewInt(1)
+	assert.Error(t, VerifyAccountProof(proofStateRoot, proof))
+
+	// nor storage
+	proof = readAccountProof(t, "account-proof-missing.json")
+	proof.StorageProof[0].Value = big.NewInt(1)
+	assert.Error(t, VerifyAccountProof(proofStateRoot, proof))
+}
@@ -0,0 +1,45 @@
+package trie
+
+import (
+	"github.com/git-yongge/ethgo"
+	"github.com/umbracle/fastrlp"
+)
+
+// DeriveRoot returns the root of the trie of an ordered list. The key of each
+// item is its rlp encoded index in the list and the value is returned by encode.
+func DeriveRoot(num int, encode func(indx int) ([]byte, error)) (ethgo.Hash, error) {
+	a := fastrlp.DefaultArenaPool.Get()
+	defer fastrlp.DefaultArenaPool.Put(a)
+
+	t := NewTrie()
+	for indx := 0; indx < num; indx++ {
+		value, err := encode(indx)
+		if err != nil {
+			return ethgo.Hash{}, err
+		}
+		key := a.NewUint(uint64(indx)).MarshalTo(nil)
+		t.Put(key, value)
+	}
+	return t.Hash(), nil
+}
+
+// TransactionsRoot returns the root of the transactions of a block (Block.TransactionsRoot)
+func TransactionsRoot(txns []*ethgo.Transaction) (ethgo.Hash, error) {
+	return DeriveRoot(len(txns), func(indx int) ([]byte, error) {
+		return txns[indx].MarshalRLPTo(nil)
+	})
+}
+
+// ReceiptsRoot returns the root of the receipts of a block (Block.ReceiptsRoot)
+func ReceiptsRoot(receipts []*ethgo.Receipt) (ethgo.Hash, error) {
+	return DeriveRoot(len(receipts), func(indx int) ([]byte, error) {
+		return receipts[indx].MarshalRLPTo(nil)
+	})
+}
+
+// WithdrawalsRoot returns the root of the withdrawals of a block (Block.WithdrawalsRoot)
+func WithdrawalsRoot(withdrawals []*ethgo.Withdrawal) (ethgo.Hash, error) {
+	return DeriveRoot(len(withdrawals), func(indx int) ([]byte, error) {
+		return withdrawals[indx].MarshalRLPTo(nil)
+	})
+}
@@ -0,0 +1,173 @@
+package trie
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAddr(i int) ethgo.Address {
+	return ethgo.BytesToAddress(big.NewInt(int64(i + 1)).Bytes())
+}
+
+func testHash(i int) ethgo.Hash {
+	return ethgo.BytesToHash(big.NewInt(int64(i + 1)).Bytes())
+}
+
+func testTxn(i int) *ethgo.Transaction {
+	txn := &ethgo.Transaction{
+		Nonce: uint64(i),
+		Gas:   21000 + uint64(i),
+		Value: big.NewInt(int64(i)),
+		Input: []byte{byte(i)},
+		R:     big.NewInt(int64(i + 1)).Bytes(),
+		S:     big.NewInt(int64(i + 2)).Bytes(),
+	}
+	if i%7 != 0 {
+		to := testAddr(i)
+		txn.To = &to
+	}
+	switch i % 3 {
+	case 0:
+		txn.Type = ethgo.TransactionLegacy
+		txn.GasPrice = uint64(i + 1)
+		txn.V = []byte{27}
+	case 1:
+		txn.Type = ethgo.TransactionAccessList
+		txn.ChainID = big.NewInt(1)
+		txn.GasPrice = uint64(i + 1)
+		txn.AccessList = ethgo.AccessList{
+			{Address: testAddr(i), Storage: []ethgo.Hash{testHash(i)}},
+		}
+		txn.V = big.NewInt(int64(i % 2)).Bytes()
+	case 2:
+		txn.Type = ethgo.TransactionDynamicFee
+		txn.ChainID = big.NewInt(1)
+		txn.MaxPriorityFeePerGas = big.NewInt(int64(i))
+		txn.MaxFeePerGas = big.NewInt(int64(2*i + 1))
+		txn.V = big.NewInt(int64(i % 2)).Bytes()
+	}
+	return txn
+}
+
+func testReceipt(i int) *ethgo.Receipt {
+	r := &ethgo.Receipt{
+		Type:              ethgo.TransactionType(i % 4),
+		Status:            uint64(i % 2),
+		CumulativeGasUsed: 21000 * uint64(i+1),
+	}
+	if i%3 == 0 {
+		r.Logs = []*ethgo.Log{
+			{Address: testAddr(i), Topics: []ethgo.Hash{testHash(i)}, Data: []byte{byte(i)}},
+		}
+	}
+	return r
+}
+
+func TestDeriveRoot_TransactionsAndReceipts(t *testing.T) {
+	// roots computed by go-ethereum (DeriveSha) for the same lists. The sizes
+	// cover the changes in the rlp encoding of the index keys.
+	cases := []struct {
+		num          int
+		txnsRoot     string
+		receiptsRoot string
+	}{
+		{0, "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421", "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421"},
+		{1, "0x3fdae6bc1ce266aa08fbadc122a3e22a69b01ada480998cfcf09ac6482c0b136", "0xdc146ad84195b3d764c406a2e57bdfcca77ecd4930b86d3c6216ea176c4b81c7"},
+		{2, "0xc9b598e46e15a7335379cedf851ef63f3e240ce034900bc851ad9b232ad80f11", "0x22f167271b7567a770676f8ff4933a21b44897843fda5a89f2c552a7efc790b9"},
+		{16, "0x827340d027586bfd2f5f6b6a18cec9cb9c7601d8001399c671f7c6896dd4df50", "0x175e6404dd5e32c0f0932891dd6dcbf0635944f17f9ad91715e88f2e930f7d79"},
+		{127, "0x55911b1fa6f3aab8ed8e1a15a0d6d8c7759b2e68860fe045f9e6c1e9a9d95d6c", "0x84cf5b5a7955292e4897a45b203151a1726b8194b39ef1b2312c0f2cfeceba42"},
+		{128, "0x9544936283c7465a2fb62446624cd3abf3e92f5a3f6e9d79fc8b62fecbc1fe39", "0xc972019b702f26328d063bd1f7dd2d02108ff5733673d8bcc7780940995b2e60"},
+		{129, "0x8df9beae92272ba4aa044a73e30696bf099a5519f63956532affbd08dee4a0dd", "0xf5c754f98d66b93a368e7b3ac9ad00a643702e9f89a83786d91c5a46eeda6420"},
+		{300, "0xc2e04a77788150588e267ea99ae91af717e1bb7885b8f65626f5acf13d73d354", "0x31d71abb0d6a048d1b4a0e68ba53fa3d8314ab281ca836eb46bf17317d04db6b"},
+	}
+	for _, c := range cases {
+		txns := []*ethgo.Transaction{}
+		receipts := []*ethgo.Receipt{}
+		for i := 0; i < c.num; i++ {
+			txns = append(txns, testTxn(i))
+			receipts = append(receipts, testReceipt(i))
+		}
+
+		root, err := TransactionsRoot(txns)
+		assert.NoError(t, err)
+		assert.Equal(t, c.txnsRoot, root.String(), c.num)
+
+		root, err = ReceiptsRoot(receipts)
+		assert.NoError(t, err)
+		assert.Equal(t, c.receiptsRoot, root.String(), c.num)
+	}
+}
+
+func TestDeriveRoot_Withdrawals(t *testing.T) {
+	// blocks built by go-ethereum with the withdrawals root it computed
+	for _, name := range []string{"block-shanghai.json", "block-cancun.json"} {
+		data, err := ioutil.ReadFile(filepath.Join("../testsuite", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b := new(ethgo.Block)
+		assert.NoError(t, b.UnmarshalJSON(data))
+		assert.NotEmpty(t, b.Withdrawals)
+
+		root, err := WithdrawalsRoot(b.Withdrawals)
+		assert.NoError(t, err)
+		assert.Equal(t, *b.WithdrawalsRoot, root, name)
+	}
+}
+
+func TestDeriveRoot_Blocks(t *testing.T) {
+	// blocks of each typed transaction era with their receipts. The chain-*
+	// blocks are built by go-ethereum and the mainnet-* blocks are written
+	// by 'make update-testsuite'.
+	files := []string{
+		"../testsuite/chain-legacy.json",
+		"../testsuite/chain-access-list.json",
+		"../testsuite/chain-dynamic-fee.json",
+		"../testsuite/chain-blob.json",
+	}
+	mainnet, err := filepath.Glob("../testsuite/mainnet-*.json")
+	assert.NoError(t, err)
+	files = append(files, mainnet...)
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var res struct {
+				Block    json.RawMessage
+				Receipts []json.RawMessage
+			}
+			assert.NoError(t, json.Unmarshal(data, &res))
+
+			b := new(ethgo.Block)
+			assert.NoError(t, b.UnmarshalJSON(res.Block))
+			assert.NotEmpty(t, b.Transactions)
+			assert.Len(t, res.Receipts, len(b.Transactions))
+
+			receipts := []*ethgo.Receipt{}
+			for _, raw := range res.Receipts {
+				receipt := new(ethgo.Receipt)
+				assert.NoError(t, receipt.UnmarshalJSON(raw))
+				receipts = append(receipts, receipt)
+			}
+
+			root, err := TransactionsRoot(b.Transactions)
+			assert.NoError(t, err)
+			assert.Equal(t, b.TransactionsRoot, root)
+
+			root, err = ReceiptsRoot(receipts)
+			assert.NoError(t, err)
+			assert.Equal(t, b.ReceiptsRoot, root)
+		})
+	}
+}
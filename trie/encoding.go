@@ -0,0 +1,119 @@
+package trie
+
+import (
+	"github.com/git-yongge/ethgo"
+	"github.com/umbracle/fastrlp"
+)
+
+// encodeNode returns the rlp encoding of the node
+func encodeNode(a *fastrlp.Arena, n node) *fastrlp.Value {
+	switch n := n.(type) {
+	case valueNode:
+		return a.NewCopyBytes(n)
+
+	case *shortNode:
+		v := a.NewArray()
+		v.Set(a.NewCopyBytes(nibblesToCompact(n.key)))
+		v.Set(encodeRef(a, n.val))
+		return v
+
+	case *fullNode:
+		v := a.NewArray()
+		for _, child := range n.children {
+			v.Set(encodeRef(a, child))
+		}
+		return v
+
+	default:
+		panic("BUG: unexpected trie node")
+	}
+}
+
+// encodeRef returns the reference to a node from its parent. Nodes with an
+// encoding shorter than 32 bytes are embedded, the others are referenced by hash.
+func encodeRef(a *fastrlp.Arena, n node) *fastrlp.Value {
+	switch n := n.(type) {
+	case nil:
+		return a.NewNull()
+	case valueNode:
+		return a.NewCopyBytes(n)
+	}
+
+	v := encodeNode(a, n)
+	raw := v.MarshalTo(nil)
+	if len(raw) < 32 {
+		return v
+	}
+	return a.NewCopyBytes(ethgo.Keccak256(raw))
+}
+
+// keyToNibbles splits the key in nibbles and appends the terminator
+func keyToNibbles(key []byte) []byte {
+	nibbles := make([]byte, len(key)*2+1)
+	for i, b := range key {
+		nibbles[i*2] = b / 16
+		nibbles[i*2+1] = b % 16
+	}
+	nibbles[len(nibbles)-1] = terminator
+	return nibbles
+}
+
+// nibblesToCompact returns the hex-prefix encoding of the nibbles. The flag
+// in the first nibble marks the leaves and the odd length keys.
+func nibblesToCompact(nibbles []byte) []byte {
+	flag := byte(0)
+	if hasTerminator(nibbles) {
+		flag = 2
+		nibbles = nibbles[:len(nibbles)-1]
+	}
+	buf := make([]byte, len(nibbles)/2+1)
+	buf[0] = flag << 4
+	if len(nibbles)%2 == 1 {
+		buf[0] |= (1 << 4) | nibbles[0]
+		nibbles = nibbles[1:]
+	}
+	for i := 0; i < len(nibbles); i += 2 {
+		buf[i/2+1] = nibbles[i]<<4 | nibbles[i+1]
+	}
+	return buf
+}
+
+// compactToNibbles decodes the hex-prefix encoding of a key
+func compactToNibbles(compact []byte) []byte {
+	if len(compact) == 0 {
+		return nil
+	}
+	nibbles := make([]byte, 0, len(compact)*2+1)
+	for _, b := range compact {
+		nibbles = append(nibbles, b/16, b%16)
+	}
+	flag := nibbles[0]
+	if flag&1 == 0 {
+		// even length, skip the padding nibble
+		nibbles = nibbles[2:]
+	} else {
+		nibbles = nibbles[1:]
+	}
+	if flag >= 2 {
+		nibbles = append(nibbles, terminator)
+	}
+	return nibbles
+}
+
+func hasTerminator(nibbles []byte) bool {
+	return len(nibbles) != 0 && nibbles[len(nibbles)-1] == terminator
+}
+
+func prefixLen(a, b []byte) int {
+	i := 0
+	for ; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			break
+		}
+	}
+	return i
+}
+
+func hasPrefix(key, prefix []byte) bool {
+	return len(key) >= len(prefix) && prefixLen(key, prefix) == len(prefix)
+}
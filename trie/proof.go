@@ -0,0 +1,150 @@
+package trie
+
+import (
+	"fmt"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/umbracle/fastrlp"
+)
+
+// Prove returns the proof of a key. The proof is the list of the rlp encoded
+// nodes referenced by hash in the path from the root to the key. If the key is
+// not in the trie, the proof shows where the path ends.
+func (t *Trie) Prove(key []byte) [][]byte {
+	a := fastrlp.DefaultArenaPool.Get()
+	defer fastrlp.DefaultArenaPool.Put(a)
+
+	proof := [][]byte{}
+
+	n := t.root
+	nibbles := keyToNibbles(key)
+	for i := 0; ; i++ {
+		switch n.(type) {
+		case nil, valueNode:
+			return proof
+		}
+
+		raw := encodeNode(a, n).MarshalTo(nil)
+		if i == 0 || len(raw) >= 32 {
+			// the root and the nodes referenced by hash
+			proof = append(proof, raw)
+		}
+
+		switch nn := n.(type) {
+		case *shortNode:
+			if !hasPrefix(nibbles, nn.key) {
+				return proof
+			}
+			nibbles = nibbles[len(nn.key):]
+			n = nn.val
+		case *fullNode:
+			n = nn.children[nibbles[0]]
+			nibbles = nibbles[1:]
+		}
+	}
+}
+
+// VerifyProof checks the proof of a key against the root hash of the trie. It
+// returns the value of the key or nil if the proof shows that the key is not in
+// the trie. An error is returned if the proof is not valid.
+func VerifyProof(root ethgo.Hash, key []byte, proof [][]byte) ([]byte, error) {
+	if root == EmptyRoot {
+		// no key is in the empty trie
+		return nil, nil
+	}
+
+	nodes := map[ethgo.Hash][]byte{}
+	for _, raw := range proof {
+		nodes[ethgo.BytesToHash(ethgo.Keccak256(raw))] = raw
+	}
+
+	nibbles := keyToNibbles(key)
+	want := root
+	for {
+		raw, ok := nodes[want]
+		if !ok {
+			return nil, fmt.Errorf("proof node %s not found", want)
+		}
+
+		var p fastrlp.Parser
+		v, err := p.Parse(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		var ref *fastrlp.Value
+		if ref, nibbles, err = resolveNode(v, nibbles); err != nil {
+			return nil, err
+		}
+
+		// follow the embedded nodes until a reference by hash
+		for ref != nil && ref.Type() == fastrlp.TypeArray {
+			if ref, nibbles, err = resolveNode(ref, nibbles); err != nil {
+				return nil, err
+			}
+		}
+		if ref == nil {
+			// the path ends before the key
+			return nil, nil
+		}
+
+		buf, err := ref.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		if len(nibbles) == 0 {
+			// value of the key
+			return append([]byte{}, buf...), nil
+		}
+		switch len(buf) {
+		case 0:
+			return nil, nil
+		case 32:
+			want = ethgo.BytesToHash(buf)
+		default:
+			return nil, fmt.Errorf("invalid node reference of %d bytes", len(buf))
+		}
+	}
+}
+
+// resolveNode returns the child of the node in the path of the key and the rest
+// of the key. The child is nil if the path does not continue.
+func resolveNode(v *fastrlp.Value, nibbles []byte) (*fastrlp.Value, []byte, error) {
+	elems, err := v.GetElems()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch len(elems) {
+	case 2:
+		// short node
+		compact, err := elems[0].Bytes()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := compactToNibbles(compact)
+		if !hasPrefix(nibbles, key) {
+			return nil, nil, nil
+		}
+		if hasTerminator(key) && elems[1].Type() == fastrlp.TypeArray {
+			return nil, nil, fmt.Errorf("leaf node with a node as value")
+		}
+		return elems[1], nibbles[len(key):], nil
+
+	case 17:
+		// full node
+		if len(nibbles) == 0 {
+			return nil, nil, fmt.Errorf("key ends before the value")
+		}
+		child := elems[nibbles[0]]
+		if nibbles[0] == terminator {
+			if buf, _ := child.Bytes(); len(buf) == 0 {
+				return nil, nil, nil
+			}
+		}
+		return child, nibbles[1:], nil
+
+	default:
+		return nil, nil, fmt.Errorf("invalid node with %d elements", len(elems))
+	}
+}
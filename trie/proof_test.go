@@ -0,0 +1,96 @@
+package trie
+
+import (
+	"testing"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/fastrlp"
+)
+
+func TestProof_Verify(t *testing.T) {
+	a := &fastrlp.Arena{}
+
+	tr := NewTrie()
+	for i := 0; i < 300; i++ {
+		key := a.NewUint(uint64(i)).MarshalTo(nil)
+		raw, err := testTxn(i).MarshalRLPTo(nil)
+		assert.NoError(t, err)
+		tr.Put(key, raw)
+	}
+	root := tr.Hash()
+
+	for _, i := range []uint64{0, 1, 127, 128, 299} {
+		key := a.NewUint(i).MarshalTo(nil)
+		expected, _ := tr.Get(key)
+
+		proof := tr.Prove(key)
+		val, err := VerifyProof(root, key, proof)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+
+	// the proof of a key not in the trie does not return a value
+	key := a.NewUint(1000).MarshalTo(nil)
+	val, err := VerifyProof(root, key, tr.Prove(key))
+	assert.NoError(t, err)
+	assert.Nil(t, val)
+
+	// a proof for another root is not valid
+	key = a.NewUint(5).MarshalTo(nil)
+	_, err = VerifyProof(ethgo.Hash{0x1}, key, tr.Prove(key))
+	assert.Error(t, err)
+
+	// a tampered proof is not valid
+	proof := tr.Prove(key)
+	last := proof[len(proof)-1]
+	last[len(last)-1] ^= 0xff
+	_, err = VerifyProof(root, key, proof)
+	assert.Error(t, err)
+}
+
+func TestProof_EmbeddedNodes(t *testing.T) {
+	// small values are embedded in the parent nodes
+	tr := NewTrie()
+	tr.Put([]byte("doe"), []byte("reindeer"))
+	tr.Put([]byte("dog"), []byte("puppy"))
+	tr.Put([]byte("dogglesworth"), []byte("cat"))
+	root := tr.Hash()
+
+	for _, key := range []string{"doe", "dog", "dogglesworth"} {
+		expected, _ := tr.Get([]byte(key))
+
+		val, err := VerifyProof(root, []byte(key), tr.Prove([]byte(key)))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+
+	val, err := VerifyProof(root, []byte("do"), tr.Prove([]byte("do")))
+	assert.NoError(t, err)
+	assert.Nil(t, val)
+}
+
+func TestProof_GenesisAccount(t *testing.T) {
+	tr, alloc := genesisTrie(t)
+	root := tr.Hash()
+
+	num := 0
+	for addr := range alloc {
+		key := ethgo.Keccak256(addr[:])
+		expected, _ := tr.Get(key)
+
+		val, err := VerifyProof(root, key, tr.Prove(key))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val)
+
+		if num++; num == 10 {
+			break
+		}
+	}
+
+	// account not in the genesis
+	key := ethgo.Keccak256(ethgo.ZeroAddress[:])
+	val, err := VerifyProof(root, key, tr.Prove(key))
+	assert.NoError(t, err)
+	assert.Nil(t, val)
+}
@@ -0,0 +1,116 @@
+package trie
+
+import (
+	"github.com/git-yongge/ethgo"
+	"github.com/umbracle/fastrlp"
+)
+
+// EmptyRoot is the root hash of an empty trie
+var EmptyRoot = ethgo.HexToHash("0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+// terminator is the nibble appended to the keys to mark the leaves
+const terminator = 16
+
+type node interface{}
+
+// shortNode is either an extension or a leaf (if the key ends with the terminator)
+type shortNode struct {
+	key []byte
+	val node
+}
+
+// fullNode is a branch node with 16 children and a value
+type fullNode struct {
+	children [17]node
+}
+
+type valueNode []byte
+
+// Trie is an in-memory Merkle Patricia Trie
+type Trie struct {
+	root node
+}
+
+// NewTrie creates a new empty trie
+func NewTrie() *Trie {
+	return &Trie{}
+}
+
+// Put inserts or replaces the value of a key
+func (t *Trie) Put(key, value []byte) {
+	val := make([]byte, len(value))
+	copy(val, value)
+
+	t.root = insert(t.root, keyToNibbles(key), valueNode(val))
+}
+
+// Get returns the value of a key
+func (t *Trie) Get(key []byte) ([]byte, bool) {
+	n := t.root
+	nibbles := keyToNibbles(key)
+	for {
+		switch nn := n.(type) {
+		case nil:
+			return nil, false
+		case valueNode:
+			return nn, true
+		case *shortNode:
+			if !hasPrefix(nibbles, nn.key) {
+				return nil, false
+			}
+			nibbles = nibbles[len(nn.key):]
+			n = nn.val
+		case *fullNode:
+			n = nn.children[nibbles[0]]
+			nibbles = nibbles[1:]
+		}
+	}
+}
+
+// Hash returns the root hash of the trie
+func (t *Trie) Hash() ethgo.Hash {
+	if t.root == nil {
+		return EmptyRoot
+	}
+
+	a := fastrlp.DefaultArenaPool.Get()
+	defer fastrlp.DefaultArenaPool.Put(a)
+
+	// the root is always hashed even if the encoding is shorter than 32 bytes
+	raw := encodeNode(a, t.root).MarshalTo(nil)
+	return ethgo.BytesToHash(ethgo.Keccak256(raw))
+}
+
+func insert(n node, key []byte, value node) node {
+	if len(key) == 0 {
+		return value
+	}
+
+	switch n := n.(type) {
+	case nil:
+		return &shortNode{key: key, val: value}
+
+	case *shortNode:
+		matchlen := prefixLen(key, n.key)
+		if matchlen == len(n.key) {
+			n.val = insert(n.val, key[matchlen:], value)
+			return n
+		}
+
+		// split the node in a branch at the first nibble that differs
+		branch := &fullNode{}
+		branch.children[n.key[matchlen]] = insert(nil, n.key[matchlen+1:], n.val)
+		branch.children[key[matchlen]] = insert(nil, key[matchlen+1:], value)
+		if matchlen == 0 {
+			return branch
+		}
+		return &shortNode{key: key[:matchlen], val: branch}
+
+	case *fullNode:
+		n.children[key[0]] = insert(n.children[key[0]], key[1:], value)
+		return n
+
+	default:
+		panic("BUG: unexpected trie node")
+	}
+}
@@ -0,0 +1,105 @@
+package trie
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/umbracle/fastrlp"
+)
+
+func TestTrie_Empty(t *testing.T) {
+	assert.Equal(t, EmptyRoot, NewTrie().Hash())
+	assert.Equal(t, EmptyRoot, ethgo.BytesToHash(ethgo.Keccak256([]byte{0x80})))
+}
+
+func TestTrie_Insert(t *testing.T) {
+	tr := NewTrie()
+	tr.Put([]byte("doe"), []byte("reindeer"))
+	tr.Put([]byte("dog"), []byte("puppy"))
+	tr.Put([]byte("dogglesworth"), []byte("cat"))
+	assert.Equal(t, "0x8aad789dff2f538bca5d8ea56e8abe10f4c7ba3a5dea95fea4cd6e7c3a1168d3", tr.Hash().String())
+
+	val, ok := tr.Get([]byte("dog"))
+	assert.True(t, ok)
+	assert.Equal(t, []byte("puppy"), val)
+
+	_, ok = tr.Get([]byte("do"))
+	assert.False(t, ok)
+
+	tr = NewTrie()
+	tr.Put([]byte("A"), []byte("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"))
+	assert.Equal(t, "0xd23786fb4a010da3ce639d66d5e904a11dbc02746d1ce25029e53290cabf28ab", tr.Hash().String())
+}
+
+func TestTrie_Replace(t *testing.T) {
+	tr := NewTrie()
+	tr.Put([]byte("dog"), []byte("cat"))
+	tr.Put([]byte("doe"), []byte("reindeer"))
+	tr.Put([]byte("dogglesworth"), []byte("cat"))
+	tr.Put([]byte("dog"), []byte("puppy"))
+
+	// the root does not depend on the order of the inserts
+	assert.Equal(t, "0x8aad789dff2f538bca5d8ea56e8abe10f4c7ba3a5dea95fea4cd6e7c3a1168d3", tr.Hash().String())
+}
+
+func readGenesisAlloc(t *testing.T) map[ethgo.Address]*big.Int {
+	f, err := os.Open("./testdata/mainnet-genesis-alloc.rlp.gz")
+	assert.NoError(t, err)
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+
+	var p fastrlp.Parser
+	v, err := p.Parse(data)
+	assert.NoError(t, err)
+
+	elems, err := v.GetElems()
+	assert.NoError(t, err)
+
+	alloc := map[ethgo.Address]*big.Int{}
+	for _, elem := range elems {
+		var addr ethgo.Address
+		assert.NoError(t, elem.Get(0).GetAddr(addr[:]))
+
+		balance := new(big.Int)
+		assert.NoError(t, elem.Get(1).GetBigInt(balance))
+		alloc[addr] = balance
+	}
+	return alloc
+}
+
+// genesisTrie builds the state trie of the mainnet genesis block
+func genesisTrie(t *testing.T) (*Trie, map[ethgo.Address]*big.Int) {
+	alloc := readGenesisAlloc(t)
+	emptyCodeHash := ethgo.Keccak256(nil)
+
+	a := &fastrlp.Arena{}
+	tr := NewTrie()
+	for addr, balance := range alloc {
+		a.Reset()
+
+		acct := a.NewArray()
+		acct.Set(a.NewUint(0))
+		acct.Set(a.NewBigInt(balance))
+		acct.Set(a.NewCopyBytes(EmptyRoot[:]))
+		acct.Set(a.NewCopyBytes(emptyCodeHash))
+
+		tr.Put(ethgo.Keccak256(addr[:]), acct.MarshalTo(nil))
+	}
+	return tr, alloc
+}
+
+func TestTrie_MainnetGenesisStateRoot(t *testing.T) {
+	tr, alloc := genesisTrie(t)
+	assert.Len(t, alloc, 8893)
+	assert.Equal(t, "0xd7f8974fb5ac78d9ac099b9ad5018bedc2ce0a72dad1827a1709da30580f0544", tr.Hash().String())
+}
@@ -0,0 +1,73 @@
+package wallet
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/git-yongge/ethgo"
+)
+
+// personalMessagePrefix is the prefix of the version 0x45 messages of EIP-191
+const personalMessagePrefix = "\x19Ethereum Signed Message:\n"
+
+// PersonalMessageHash returns the hash of a version 0x45 (personal_sign) message:
+// keccak256("\x19Ethereum Signed Message:\n" ‖ len(msg) ‖ msg)
+func PersonalMessageHash(msg []byte) []byte {
+	prefix := personalMessagePrefix + strconv.Itoa(len(msg))
+	return ethgo.Keccak256([]byte(prefix), msg)
+}
+
+// IntendedValidatorHash returns the hash of the version 0x00 data with an intended
+// validator: keccak256(0x19 ‖ 0x00 ‖ validator ‖ data)
+func IntendedValidatorHash(validator ethgo.Address, data []byte) []byte {
+	return ethgo.Keccak256([]byte{0x19, 0x00}, validator[:], data)
+}
+
+// SignPersonalMessage signs a message as personal_sign does (EIP-191 version 0x45).
+// The signature is encoded as r ‖ s ‖ v with v being 27 or 28.
+func (k *Key) SignPersonalMessage(msg []byte) ([]byte, error) {
+	return signEIP191(k, PersonalMessageHash(msg))
+}
+
+// SignIntendedValidator signs the data for an intended validator (EIP-191 version 0x00).
+// The signature is encoded as r ‖ s ‖ v with v being 27 or 28.
+func (k *Key) SignIntendedValidator(validator ethgo.Address, data []byte) ([]byte, error) {
+	return signEIP191(k, IntendedValidatorHash(validator, data))
+}
+
+func signEIP191(key ethgo.Key, hash []byte) ([]byte, error) {
+	sig, err := key.Sign(hash)
+	if err != nil {
+		return nil, err
+	}
+	if sig[64] < 27 {
+		sig[64] += 27
+	}
+	return sig, nil
+}
+
+// RecoverPersonalMessage returns the address that signed a personal message
+func RecoverPersonalMessage(msg, signature []byte) (ethgo.Address, error) {
+	return Ecrecover(PersonalMessageHash(msg), signature)
+}
+
+// RecoverIntendedValidator returns the address that signed the data for an intended validator
+func RecoverIntendedValidator(validator ethgo.Address, data, signature []byte) (ethgo.Address, error) {
+	return Ecrecover(IntendedValidatorHash(validator, data), signature)
+}
+
+// NormalizeSignature returns a copy of the signature with v being 0 or 1.
+// It accepts v as 0 or 1 or as 27 or 28.
+func NormalizeSignature(signature []byte) ([]byte, error) {
+	if len(signature) != 65 {
+		return nil, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+	sig := append([]byte{}, signature...)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	if sig[64] > 1 {
+		return nil, fmt.Errorf("invalid signature v value %d", signature[64])
+	}
+	return sig, nil
+}
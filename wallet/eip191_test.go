@@ -0,0 +1,86 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/git-yongge/ethgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEIP191_PersonalMessage(t *testing.T) {
+	// the vectors are computed with go-ethereum
+	cases := []struct {
+		msg       string
+		hash      string
+		signature string
+	}{
+		{
+			"hello world",
+			"d9eba16ed0ecae432b71fe008c98cc872bb4cc214d3220a36f365326cf807d68",
+			"e1ffe99aa71b20a5a9bfe344aa88d410141149c2be44ab8ff3e4fa4cdc1e90dc41ad65439332078f28538cd371b4bf9b216af7478cff49abda5ce04a3d603c6a1b",
+		},
+		{
+			"",
+			"5f35dce98ba4fba25530a026ed80b2cecdaa31091ba4958b99b52ea1d068adad",
+			"68c36703cfae77b264e66cf9587aa39dd76b66ff1317e563b4566d9ea5d8d60e5b9be8c58a324e1dbb424365aa778a2faec2d3f922bf0339cda43d76c492a5ab1c",
+		},
+	}
+
+	key, err := NewWalletFromPrivKey(ethgo.Keccak256([]byte("cow")))
+	assert.NoError(t, err)
+
+	for _, c := range cases {
+		msg := []byte(c.msg)
+		assert.Equal(t, c.hash, hex.EncodeToString(PersonalMessageHash(msg)))
+
+		signature, err := key.SignPersonalMessage(msg)
+		assert.NoError(t, err)
+		assert.Equal(t, c.signature, hex.EncodeToString(signature))
+
+		addr, err := RecoverPersonalMessage(msg, signature)
+		assert.NoError(t, err)
+		assert.Equal(t, key.Address(), addr)
+	}
+}
+
+func TestEIP191_IntendedValidator(t *testing.T) {
+	key, err := NewWalletFromPrivKey(ethgo.Keccak256([]byte("cow")))
+	assert.NoError(t, err)
+
+	validator := ethgo.HexToAddress("0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC")
+	data := []byte("hello world")
+
+	assert.Equal(t, "652d4c29facb8039d3e246ea0bc807963b1171871356a8207677126a3e501abb", hex.EncodeToString(IntendedValidatorHash(validator, data)))
+
+	signature, err := key.SignIntendedValidator(validator, data)
+	assert.NoError(t, err)
+	assert.Equal(t, "16524a6fd92db2c5bfb1f30526c5e236273b0b4c346b7d52bd6377206ff8c3547fe7f7e0e7c107f6535a0b3b57b8627bd21a1dc20eb6a3d2b11f4c85461145b21b", hex.EncodeToString(signature))
+
+	addr, err := RecoverIntendedValidator(validator, data, signature)
+	assert.NoError(t, err)
+	assert.Equal(t, key.Address(), addr)
+
+	// the signature is for a different validator
+	addr, err = RecoverIntendedValidator(ethgo.Address{0x1}, data, signature)
+	assert.NoError(t, err)
+	assert.NotEqual(t, key.Address(), addr)
+}
+
+func TestNormalizeSignature(t *testing.T) {
+	sig := make([]byte, 65)
+
+	for v, expected := range map[byte]byte{0: 0, 1: 1, 27: 0, 28: 1} {
+		sig[64] = v
+		res, err := NormalizeSignature(sig)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res[64])
+	}
+
+	sig[64] = 2
+	_, err := NormalizeSignature(sig)
+	assert.Error(t, err)
+
+	_, err = NormalizeSignature(sig[:64])
+	assert.Error(t, err)
+}
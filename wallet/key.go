@@ -29,6 +29,8 @@ func (k *Key) MarshallPrivateKey() ([]byte, error) {
 	return (*btcec.PrivateKey)(k.priv).Serialize(), nil
 }
 
+// SignMsg signs the keccak256 hash of the message without the EIP-191 prefix.
+// Use SignPersonalMessage for signatures compatible with personal_sign.
 func (k *Key) SignMsg(msg []byte) ([]byte, error) {
 	return k.Sign(ethgo.Keccak256(msg))
 }
@@ -81,14 +83,15 @@ func Ecrecover(hash, signature []byte) (ethgo.Address, error) {
 	return pubKeyToAddress(pub), nil
 }
 
+// RecoverPubkey returns the public key that signed the hash. The v value
+// of the signature can be either 0 or 1 or 27 or 28.
 func RecoverPubkey(signature, hash []byte) (*ecdsa.PublicKey, error) {
-	size := len(signature)
-	term := byte(27)
-	if signature[size-1] == 1 {
-		term = 28
+	signature, err := NormalizeSignature(signature)
+	if err != nil {
+		return nil, err
 	}
 
-	sig := append([]byte{term}, signature[:size-1]...)
+	sig := append([]byte{signature[64] + 27}, signature[:64]...)
 	pub, _, err := btcec.RecoverCompact(S256, sig, hash)
 	if err != nil {
 		return nil, err
@@ -1,6 +1,7 @@
 package wallet
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/git-yongge/ethgo"
@@ -15,6 +16,18 @@ type Signer interface {
 	SignTx(tx *ethgo.Transaction, key *Key) (*ethgo.Transaction, error)
 }
 
+// KeySigner is a Signer that also signs with any ethgo.Key,
+// like the keys held by a remote signer or a hardware wallet
+type KeySigner interface {
+	Signer
+
+	// SignTxWithKey signs a transaction with an ethgo.Key
+	SignTxWithKey(tx *ethgo.Transaction, key ethgo.Key) (*ethgo.Transaction, error)
+}
+
+// EIP1155Signer signs legacy transactions with the EIP-155 replay protection
+// and typed transactions (EIP-2930 and EIP-1559) with their envelope signing hash.
+// The scheme is selected with the type of the transaction.
 type EIP1155Signer struct {
 	chainID uint64
 }
@@ -24,16 +37,35 @@ func NewEIP155Signer(chainID uint64) *EIP1155Signer {
 }
 
 func (e *EIP1155Signer) RecoverSender(tx *ethgo.Transaction) (ethgo.Address, error) {
+	if err := e.validateChainID(tx); err != nil {
+		return ethgo.Address{}, err
+	}
+
 	v := new(big.Int).SetBytes(tx.V).Uint64()
-	v -= e.chainID * 2
-	v -= 8
-	v -= 27
+	chainID := e.chainID
+
+	if tx.Type == ethgo.TransactionLegacy {
+		if v == 27 || v == 28 {
+			// legacy transaction without replay protection
+			chainID = 0
+			v -= 27
+		} else {
+			v -= e.chainID * 2
+			v -= 8
+			v -= 27
+		}
+	}
+	// typed transactions use the y-parity (0 or 1) as V
+
+	if v > 1 {
+		return ethgo.Address{}, fmt.Errorf("invalid signature recovery id %d", v)
+	}
 
 	sig, err := encodeSignature(tx.R, tx.S, byte(v))
 	if err != nil {
 		return ethgo.Address{}, err
 	}
-	addr, err := Ecrecover(signHash(tx, e.chainID), sig)
+	addr, err := Ecrecover(signHash(tx, chainID), sig)
 	if err != nil {
 		return ethgo.Address{}, err
 	}
@@ -41,6 +73,17 @@ func (e *EIP1155Signer) RecoverSender(tx *ethgo.Transaction) (ethgo.Address, err
 }
 
 func (e *EIP1155Signer) SignTx(tx *ethgo.Transaction, key *Key) (*ethgo.Transaction, error) {
+	return e.SignTxWithKey(tx, key)
+}
+
+func (e *EIP1155Signer) SignTxWithKey(tx *ethgo.Transaction, key ethgo.Key) (*ethgo.Transaction, error) {
+	if err := e.validateChainID(tx); err != nil {
+		return nil, err
+	}
+	if tx.Type != ethgo.TransactionLegacy && tx.ChainID == nil {
+		tx.ChainID = new(big.Int).SetUint64(e.chainID)
+	}
+
 	hash := signHash(tx, e.chainID)
 
 	sig, err := key.Sign(hash)
@@ -48,20 +91,52 @@ func (e *EIP1155Signer) SignTx(tx *ethgo.Transaction, key *Key) (*ethgo.Transact
 		return nil, err
 	}
 
-	vv := uint64(sig[64]) + 35 + e.chainID*2
+	vv := uint64(sig[64])
+	if tx.Type == ethgo.TransactionLegacy {
+		vv = vv + 35 + e.chainID*2
+	}
 
-	tx.R = sig[:32]
-	tx.S = sig[32:64]
+	// r and s are big-endian integers without leading zeros
+	tx.R = new(big.Int).SetBytes(sig[:32]).Bytes()
+	tx.S = new(big.Int).SetBytes(sig[32:64]).Bytes()
 	tx.V = new(big.Int).SetUint64(vv).Bytes()
 	return tx, nil
 }
 
+func (e *EIP1155Signer) validateChainID(tx *ethgo.Transaction) error {
+	switch tx.Type {
+	case ethgo.TransactionLegacy:
+		return nil
+	case ethgo.TransactionAccessList, ethgo.TransactionDynamicFee:
+		if tx.ChainID != nil && (!tx.ChainID.IsUint64() || tx.ChainID.Uint64() != e.chainID) {
+			return fmt.Errorf("transaction chain id %s does not match signer chain id %d", tx.ChainID, e.chainID)
+		}
+		return nil
+	default:
+		return fmt.Errorf("transaction type %d not supported", tx.Type)
+	}
+}
+
 func signHash(tx *ethgo.Transaction, chainID uint64) []byte {
 	a := fastrlp.DefaultArenaPool.Get()
 
 	v := a.NewArray()
+
+	if tx.Type != ethgo.TransactionLegacy {
+		// either dynamic and access type
+		v.Set(a.NewUint(chainID))
+	}
+
 	v.Set(a.NewUint(tx.Nonce))
-	v.Set(a.NewUint(tx.GasPrice))
+
+	if tx.Type == ethgo.TransactionDynamicFee {
+		v.Set(a.NewBigInt(tx.MaxPriorityFeePerGas))
+		v.Set(a.NewBigInt(tx.MaxFeePerGas))
+	} else {
+		// legacy and access type use gas price
+		v.Set(a.NewUint(tx.GasPrice))
+	}
+
 	v.Set(a.NewUint(tx.Gas))
 	if tx.To == nil {
 		v.Set(a.NewNull())
@@ -71,14 +146,25 @@ func signHash(tx *ethgo.Transaction, chainID uint64) []byte {
 	v.Set(a.NewBigInt(tx.Value))
 	v.Set(a.NewCopyBytes(tx.Input))
 
+	if tx.Type != ethgo.TransactionLegacy {
+		accessList, _ := tx.AccessList.MarshalRLPWith(a)
+		v.Set(accessList)
+	}
+
 	// EIP155
-	if chainID != 0 {
+	if tx.Type == ethgo.TransactionLegacy && chainID != 0 {
 		v.Set(a.NewUint(chainID))
 		v.Set(a.NewUint(0))
 		v.Set(a.NewUint(0))
 	}
 
-	hash := ethgo.Keccak256(v.MarshalTo(nil))
+	dst := v.MarshalTo(nil)
+	if tx.Type != ethgo.TransactionLegacy {
+		// typed transactions hash the envelope: type || rlp(payload)
+		dst = append([]byte{byte(tx.Type)}, dst...)
+	}
+
+	hash := ethgo.Keccak256(dst)
 	fastrlp.DefaultArenaPool.Put(a)
 	return hash
 }
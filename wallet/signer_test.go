@@ -41,6 +41,94 @@ func TestSigner_EIP1155(t *testing.T) {
 	*/
 }
 
+func TestSigner_TypedTransactions(t *testing.T) {
+	// test vectors generated with go-ethereum
+	priv, err := hex.DecodeString("4646464646464646464646464646464646464646464646464646464646464646")
+	assert.NoError(t, err)
+	key, err := NewWalletFromPrivKey(priv)
+	assert.NoError(t, err)
+
+	to := ethgo.HexToAddress("0x3535353535353535353535353535353535353535")
+	accessList := ethgo.AccessList{
+		{Address: ethgo.HexToAddress("0x1"), Storage: []ethgo.Hash{ethgo.HexToHash("0x2")}},
+	}
+
+	cases := []struct {
+		txn  *ethgo.Transaction
+		hash string
+		raw  string
+	}{
+		{
+			&ethgo.Transaction{
+				Type:       ethgo.TransactionAccessList,
+				Nonce:      9,
+				GasPrice:   20000000000,
+				Gas:        21000,
+				To:         &to,
+				Value:      ethgo.Ether(1),
+				AccessList: accessList,
+			},
+			"0x2a0d6986884f2685bc328c0633c92b372aa749e6bd9ef5b451f156f23f03dd1b",
+			"01f8a701098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a764000080f838f7940000000000000000000000000000000000000001e1a0000000000000000000000000000000000000000000000000000000000000000280a03d41ab9ab09c88874a972b01f64f830e2dc23efda4e0ae066e3782a5e5e51e4da056b62788b863e5374db5f9e026448b4a73b21dfd9a56e6d8e9446b78ad8aa0a2",
+		},
+		{
+			&ethgo.Transaction{
+				Type:                 ethgo.TransactionDynamicFee,
+				Nonce:                9,
+				MaxPriorityFeePerGas: ethgo.Gwei(2),
+				MaxFeePerGas:         ethgo.Gwei(100),
+				Gas:                  21000,
+				To:                   &to,
+				Value:                ethgo.Ether(1),
+				Input:                []byte{0x1, 0x2},
+				AccessList:           accessList,
+			},
+			"0x266b1a01444c5533eac011328ad5423c72d39954acfe5053055ebbe2d7c74c89",
+			"02f8ae0109847735940085174876e800825208943535353535353535353535353535353535353535880de0b6b3a7640000820102f838f7940000000000000000000000000000000000000001e1a0000000000000000000000000000000000000000000000000000000000000000201a0d446153798b8e5fbd739beef934ce48c39872c7fb281585acf397f87a739a5b6a02aefc412484c445bf397bb980cebdb044eb09d41897f39a7340844545be118fe",
+		},
+		{
+			// contract creation
+			&ethgo.Transaction{
+				Type:                 ethgo.TransactionDynamicFee,
+				MaxPriorityFeePerGas: big.NewInt(1),
+				MaxFeePerGas:         big.NewInt(1),
+				Gas:                  100000,
+				Value:                big.NewInt(0),
+				Input:                []byte{0x60, 0x00},
+			},
+			"0x57c88d3438afd0d77836f94ca5ce961f98d0d15362a1ef3049a89c4e6b4707fa",
+			"02f85101800101830186a08080826000c080a01c6e73b231be3d508ce0349188d518666b9f6634f5b0be99d9c0752ba86201c5a0195869f6c431e51f2b110c1f5e3db24c7d1c7c64f17ad87feb7b6046b3dfe1ca",
+		},
+	}
+
+	signer := NewEIP155Signer(1)
+	for _, c := range cases {
+		txn, err := signer.SignTx(c.txn, key)
+		assert.NoError(t, err)
+
+		raw, err := txn.MarshalRLPTo(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, c.raw, hex.EncodeToString(raw))
+
+		hash, err := txn.GetHash()
+		assert.NoError(t, err)
+		assert.Equal(t, c.hash, hash.String())
+
+		// decode the raw transaction and recover the sender
+		txn2 := &ethgo.Transaction{}
+		assert.NoError(t, txn2.UnmarshalRLP(raw))
+		assert.Equal(t, c.txn.Type, txn2.Type)
+
+		from, err := signer.RecoverSender(txn2)
+		assert.NoError(t, err)
+		assert.Equal(t, key.Address(), from)
+	}
+
+	// the chain id of the transaction must match the one of the signer
+	_, err = NewEIP155Signer(2).SignTx(&ethgo.Transaction{Type: ethgo.TransactionDynamicFee, ChainID: big.NewInt(1)}, key)
+	assert.Error(t, err)
+}
+
 func TestKey_Sign(t *testing.T) {
 	signer1 := NewEIP155Signer(100)
 
@@ -90,3 +178,100 @@ func TestKey_Sign(t *testing.T) {
 	}
 	t.Log("recover==>", from2.String())
 }
+
+// remoteKey is an ethgo.Key that is not a wallet key
+type remoteKey struct {
+	key *Key
+}
+
+func (r *remoteKey) Address() ethgo.Address {
+	return r.key.Address()
+}
+
+func (r *remoteKey) Sign(hash []byte) ([]byte, error) {
+	return r.key.Sign(hash)
+}
+
+func TestSigner_SignatureLeadingZeros(t *testing.T) {
+	// test vectors generated with go-ethereum where r or s
+	// starts with a zero byte
+	priv, err := hex.DecodeString("4646464646464646464646464646464646464646464646464646464646464646")
+	assert.NoError(t, err)
+	key, err := NewWalletFromPrivKey(priv)
+	assert.NoError(t, err)
+
+	to := ethgo.HexToAddress("0x3535353535353535353535353535353535353535")
+
+	cases := []struct {
+		txn *ethgo.Transaction
+		raw string
+	}{
+		{
+			// s starts with a zero byte
+			&ethgo.Transaction{Nonce: 25, GasPrice: 20000000000, Gas: 21000, To: &to, Value: big.NewInt(1)},
+			"f863198504a817c800825208943535353535353535353535353535353535353535018025a082964e0808f1c0ec10586bc7ab97317a5a250e37fc6e1a61b941845c63f523f79f3a040eb92d5860e439034808c31cf255d6e196f53080c800bcbc8206ea937b",
+		},
+		{
+			// r starts with a zero byte
+			&ethgo.Transaction{Nonce: 204, GasPrice: 20000000000, Gas: 21000, To: &to, Value: big.NewInt(1)},
+			"f86481cc8504a817c8008252089435353535353535353535353535353535353535350180269f61761eb91b386157f16f96884af619a5718f007b8aae408f67b9b82ae98402a0123de922e8ad1d362a4cf30fe99645b466c06d76244d2b26c614e5b4748ff2f1",
+		},
+		{
+			&ethgo.Transaction{Type: ethgo.TransactionDynamicFee, Nonce: 33, MaxPriorityFeePerGas: ethgo.Gwei(2), MaxFeePerGas: ethgo.Gwei(100), Gas: 21000, To: &to, Value: big.NewInt(1)},
+			"02f86a0121847735940085174876e8008252089435353535353535353535353535353535353535350180c080a0aa8b79f1dfa3094646cf3b87c6b4775bca26059f1f8eff03dcae6a2184b616b29f4385606bb5e9fe4445e4eedcf26d56c995cee6f698d3f57a91be935b64dfb6",
+		},
+		{
+			&ethgo.Transaction{Type: ethgo.TransactionDynamicFee, Nonce: 398, MaxPriorityFeePerGas: ethgo.Gwei(2), MaxFeePerGas: ethgo.Gwei(100), Gas: 21000, To: &to, Value: big.NewInt(1)},
+			"02f86c0182018e847735940085174876e8008252089435353535353535353535353535353535353535350180c0019f15a417f109e0f334b1deab86e6dccc149a5f026051c7c4ca4afd72de6b2e85a04b7a6e27c626ee11b647bffe334c70ae7c96944335cbfdcfa22e7c0b162f3aa9",
+		},
+	}
+
+	signer := NewEIP155Signer(1)
+	for _, c := range cases {
+		txn, err := signer.SignTx(c.txn, key)
+		assert.NoError(t, err)
+		assert.True(t, len(txn.R) < 32 || len(txn.S) < 32)
+		assert.NotEqual(t, byte(0), txn.R[0])
+		assert.NotEqual(t, byte(0), txn.S[0])
+
+		raw, err := txn.MarshalRLPTo(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, c.raw, hex.EncodeToString(raw))
+
+		// the decoded transaction encodes to the same bytes
+		txn2 := &ethgo.Transaction{}
+		assert.NoError(t, txn2.UnmarshalRLP(raw))
+
+		raw2, err := txn2.MarshalRLPTo(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, raw, raw2)
+
+		from, err := signer.RecoverSender(txn2)
+		assert.NoError(t, err)
+		assert.Equal(t, key.Address(), from)
+	}
+
+	// a padded signature is encoded without the leading zeros
+	txn, err := signer.SignTx(cases[0].txn, key)
+	assert.NoError(t, err)
+	txn.S = append([]byte{0x0}, txn.S...)
+
+	raw, err := txn.MarshalRLPTo(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, cases[0].raw, hex.EncodeToString(raw))
+}
+
+func TestSigner_SignTxWithKey(t *testing.T) {
+	signer := NewEIP155Signer(1337)
+
+	key, err := GenerateKey()
+	assert.NoError(t, err)
+
+	addr0 := ethgo.Address{0x1}
+	txn, err := signer.SignTxWithKey(&ethgo.Transaction{To: &addr0, Value: big.NewInt(10)}, &remoteKey{key})
+	assert.NoError(t, err)
+
+	from, err := signer.RecoverSender(txn)
+	assert.NoError(t, err)
+	assert.Equal(t, key.Address(), from)
+}